@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/_scaffolds/app"
+	"github.com/goadesign/goa"
+)
+
+// ArchiveController implements the archive resource.
+type ArchiveController struct {
+	*goa.Controller
+}
+
+// NewArchiveController creates a archive controller.
+func NewArchiveController(service *goa.Service) *ArchiveController {
+	return &ArchiveController{Controller: service.NewController("ArchiveController")}
+}
+
+// List runs the list action.
+func (c *ArchiveController) List(ctx *app.ListArchiveContext) error {
+	// ArchiveController_List: start_implement
+
+	// Put your logic here
+
+	// ArchiveController_List: end_implement
+	res := app.PinbaseArchivedPinCollection{}
+	return ctx.OK(res)
+}
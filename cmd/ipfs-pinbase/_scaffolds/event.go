@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/_scaffolds/app"
+	"github.com/goadesign/goa"
+)
+
+// EventController implements the event resource.
+type EventController struct {
+	*goa.Controller
+}
+
+// NewEventController creates a event controller.
+func NewEventController(service *goa.Service) *EventController {
+	return &EventController{Controller: service.NewController("EventController")}
+}
+
+// Party runs the party action.
+func (c *EventController) Party(ctx *app.PartyEventContext) error {
+	// EventController_Party: start_implement
+
+	// Put your logic here
+
+	// EventController_Party: end_implement
+	return nil
+}
+
+// Stream runs the stream action.
+func (c *EventController) Stream(ctx *app.StreamEventContext) error {
+	// EventController_Stream: start_implement
+
+	// Put your logic here
+
+	// EventController_Stream: end_implement
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/_scaffolds/app"
+	"github.com/goadesign/goa"
+)
+
+// KeyController implements the key resource.
+type KeyController struct {
+	*goa.Controller
+}
+
+// NewKeyController creates a key controller.
+func NewKeyController(service *goa.Service) *KeyController {
+	return &KeyController{Controller: service.NewController("KeyController")}
+}
+
+// Create runs the create action.
+func (c *KeyController) Create(ctx *app.CreateKeyContext) error {
+	// KeyController_Create: start_implement
+
+	// Put your logic here
+
+	// KeyController_Create: end_implement
+	return nil
+}
+
+// Delete runs the delete action.
+func (c *KeyController) Delete(ctx *app.DeleteKeyContext) error {
+	// KeyController_Delete: start_implement
+
+	// Put your logic here
+
+	// KeyController_Delete: end_implement
+	return nil
+}
+
+// List runs the list action.
+func (c *KeyController) List(ctx *app.ListKeyContext) error {
+	// KeyController_List: start_implement
+
+	// Put your logic here
+
+	// KeyController_List: end_implement
+	res := app.PinbaseKeyCollection{}
+	return ctx.OK(res)
+}
+
+// Show runs the show action.
+func (c *KeyController) Show(ctx *app.ShowKeyContext) error {
+	// KeyController_Show: start_implement
+
+	// Put your logic here
+
+	// KeyController_Show: end_implement
+	res := &app.PinbaseKey{}
+	return ctx.OK(res)
+}
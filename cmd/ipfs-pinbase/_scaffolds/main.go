@@ -18,12 +18,27 @@ func main() {
 	service.Use(middleware.ErrorHandler(service, true))
 	service.Use(middleware.Recover())
 
+	// Mount "archive" controller
+	c := NewArchiveController(service)
+	app.MountArchiveController(service, c)
+	// Mount "event" controller
+	c2 := NewEventController(service)
+	app.MountEventController(service, c2)
+	// Mount "key" controller
+	c3 := NewKeyController(service)
+	app.MountKeyController(service, c3)
+	// Mount "node" controller
+	c4 := NewNodeController(service)
+	app.MountNodeController(service, c4)
 	// Mount "party" controller
-	c := NewPartyController(service)
-	app.MountPartyController(service, c)
+	c5 := NewPartyController(service)
+	app.MountPartyController(service, c5)
 	// Mount "pin" controller
-	c2 := NewPinController(service)
-	app.MountPinController(service, c2)
+	c6 := NewPinController(service)
+	app.MountPinController(service, c6)
+	// Mount "webhook" controller
+	c7 := NewWebhookController(service)
+	app.MountWebhookController(service, c7)
 
 	// Start service
 	if err := service.ListenAndServe(":3000"); err != nil {
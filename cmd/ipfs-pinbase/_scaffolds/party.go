@@ -35,6 +35,16 @@ func (c *PartyController) Delete(ctx *app.DeletePartyContext) error {
 	return nil
 }
 
+// Grant runs the grant action.
+func (c *PartyController) Grant(ctx *app.GrantPartyContext) error {
+	// PartyController_Grant: start_implement
+
+	// Put your logic here
+
+	// PartyController_Grant: end_implement
+	return nil
+}
+
 // List runs the list action.
 func (c *PartyController) List(ctx *app.ListPartyContext) error {
 	// PartyController_List: start_implement
@@ -35,6 +35,17 @@ func (c *PinController) Delete(ctx *app.DeletePinContext) error {
 	return nil
 }
 
+// History runs the history action.
+func (c *PinController) History(ctx *app.HistoryPinContext) error {
+	// PinController_History: start_implement
+
+	// Put your logic here
+
+	// PinController_History: end_implement
+	res := app.PinbasePinHistoryCollection{}
+	return ctx.OK(res)
+}
+
 // List runs the list action.
 func (c *PinController) List(ctx *app.ListPinContext) error {
 	// PinController_List: start_implement
@@ -46,6 +57,28 @@ func (c *PinController) List(ctx *app.ListPinContext) error {
 	return ctx.OK(res)
 }
 
+// Renew runs the renew action.
+func (c *PinController) Renew(ctx *app.RenewPinContext) error {
+	// PinController_Renew: start_implement
+
+	// Put your logic here
+
+	// PinController_Renew: end_implement
+	res := &app.PinbasePin{}
+	return ctx.OK(res)
+}
+
+// Reset runs the reset action.
+func (c *PinController) Reset(ctx *app.ResetPinContext) error {
+	// PinController_Reset: start_implement
+
+	// Put your logic here
+
+	// PinController_Reset: end_implement
+	res := &app.PinbasePin{}
+	return ctx.OK(res)
+}
+
 // Show runs the show action.
 func (c *PinController) Show(ctx *app.ShowPinContext) error {
 	// PinController_Show: start_implement
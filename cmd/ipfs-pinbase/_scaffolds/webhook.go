@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/_scaffolds/app"
+	"github.com/goadesign/goa"
+)
+
+// WebhookController implements the webhook resource.
+type WebhookController struct {
+	*goa.Controller
+}
+
+// NewWebhookController creates a webhook controller.
+func NewWebhookController(service *goa.Service) *WebhookController {
+	return &WebhookController{Controller: service.NewController("WebhookController")}
+}
+
+// Create runs the create action.
+func (c *WebhookController) Create(ctx *app.CreateWebhookContext) error {
+	// WebhookController_Create: start_implement
+
+	// Put your logic here
+
+	// WebhookController_Create: end_implement
+	return nil
+}
+
+// Delete runs the delete action.
+func (c *WebhookController) Delete(ctx *app.DeleteWebhookContext) error {
+	// WebhookController_Delete: start_implement
+
+	// Put your logic here
+
+	// WebhookController_Delete: end_implement
+	return nil
+}
+
+// List runs the list action.
+func (c *WebhookController) List(ctx *app.ListWebhookContext) error {
+	// WebhookController_List: start_implement
+
+	// Put your logic here
+
+	// WebhookController_List: end_implement
+	res := app.PinbaseWebhookCollection{}
+	return ctx.OK(res)
+}
+
+// Show runs the show action.
+func (c *WebhookController) Show(ctx *app.ShowWebhookContext) error {
+	// WebhookController_Show: start_implement
+
+	// Put your logic here
+
+	// WebhookController_Show: end_implement
+	res := &app.PinbaseWebhook{}
+	return ctx.OK(res)
+}
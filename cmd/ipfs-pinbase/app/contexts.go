@@ -13,254 +13,1617 @@ package app
 import (
 	"github.com/goadesign/goa"
 	"golang.org/x/net/context"
+	"time"
 )
 
+// ListArchiveContext provides the archive list action context.
+type ListArchiveContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+}
+
+// NewListArchiveContext parses the incoming request URL and body, performs validations and creates the
+// context used by the archive controller list action.
+func NewListArchiveContext(ctx context.Context, service *goa.Service) (*ListArchiveContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListArchiveContext{Context: ctx, ResponseData: resp, RequestData: req}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *ListArchiveContext) OK(r PinbaseArchivedPinCollection) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.archived-pin+json; type=collection")
+	if r == nil {
+		r = PinbaseArchivedPinCollection{}
+	}
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ListArchiveContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// PartyEventContext provides the event party action context.
+type PartyEventContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	PartyHash string
+}
+
+// NewPartyEventContext parses the incoming request URL and body, performs validations and creates the
+// context used by the event controller party action.
+func NewPartyEventContext(ctx context.Context, service *goa.Service) (*PartyEventContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := PartyEventContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramPartyHash := req.Params["partyHash"]
+	if len(paramPartyHash) > 0 {
+		rawPartyHash := paramPartyHash[0]
+		rctx.PartyHash = rawPartyHash
+	}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *PartyEventContext) OK(resp []byte) error {
+	ctx.ResponseData.Header().Set("Content-Type", "text/event-stream")
+	ctx.ResponseData.WriteHeader(200)
+	_, err := ctx.ResponseData.Write(resp)
+	return err
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *PartyEventContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *PartyEventContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// StreamEventContext provides the event stream action context.
+type StreamEventContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+}
+
+// NewStreamEventContext parses the incoming request URL and body, performs validations and creates the
+// context used by the event controller stream action.
+func NewStreamEventContext(ctx context.Context, service *goa.Service) (*StreamEventContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := StreamEventContext{Context: ctx, ResponseData: resp, RequestData: req}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *StreamEventContext) OK(resp []byte) error {
+	ctx.ResponseData.Header().Set("Content-Type", "text/event-stream")
+	ctx.ResponseData.WriteHeader(200)
+	_, err := ctx.ResponseData.Write(resp)
+	return err
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *StreamEventContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// CreateKeyContext provides the key create action context.
+type CreateKeyContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Payload *CreateKeyPayload
+}
+
+// NewCreateKeyContext parses the incoming request URL and body, performs validations and creates the
+// context used by the key controller create action.
+func NewCreateKeyContext(ctx context.Context, service *goa.Service) (*CreateKeyContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := CreateKeyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	return &rctx, err
+}
+
+// createKeyPayload is the key create action payload.
+type createKeyPayload struct {
+	// Admin keys may do anything, others only what they are granted on each party
+	Admin *bool `form:"admin,omitempty" json:"admin,omitempty" xml:"admin,omitempty"`
+	// What or who the key is for
+	Description *string `form:"description,omitempty" json:"description,omitempty" xml:"description,omitempty"`
+}
+
+// Finalize sets the default values defined in the design.
+func (payload *createKeyPayload) Finalize() {
+	var defaultAdmin = false
+	if payload.Admin == nil {
+		payload.Admin = &defaultAdmin
+	}
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *createKeyPayload) Validate() (err error) {
+	if payload.Description == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "description"))
+	}
+	return
+}
+
+// Publicize creates CreateKeyPayload from createKeyPayload
+func (payload *createKeyPayload) Publicize() *CreateKeyPayload {
+	var pub CreateKeyPayload
+	if payload.Admin != nil {
+		pub.Admin = *payload.Admin
+	}
+	if payload.Description != nil {
+		pub.Description = *payload.Description
+	}
+	return &pub
+}
+
+// CreateKeyPayload is the key create action payload.
+type CreateKeyPayload struct {
+	// Admin keys may do anything, others only what they are granted on each party
+	Admin bool `form:"admin" json:"admin" xml:"admin"`
+	// What or who the key is for
+	Description string `form:"description" json:"description" xml:"description"`
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *CreateKeyPayload) Validate() (err error) {
+	if payload.Description == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "description"))
+	}
+	return
+}
+
+// CreatedSecret sends a HTTP response with status code 201.
+func (ctx *CreateKeyContext) CreatedSecret(r *PinbaseKeySecret) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.key+json")
+	return ctx.ResponseData.Service.Send(ctx.Context, 201, r)
+}
+
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *CreateKeyContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *CreateKeyContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// DeleteKeyContext provides the key delete action context.
+type DeleteKeyContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	KeyID string
+}
+
+// NewDeleteKeyContext parses the incoming request URL and body, performs validations and creates the
+// context used by the key controller delete action.
+func NewDeleteKeyContext(ctx context.Context, service *goa.Service) (*DeleteKeyContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := DeleteKeyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramKeyID := req.Params["keyID"]
+	if len(paramKeyID) > 0 {
+		rawKeyID := paramKeyID[0]
+		rctx.KeyID = rawKeyID
+	}
+	return &rctx, err
+}
+
+// NoContent sends a HTTP response with status code 204.
+func (ctx *DeleteKeyContext) NoContent() error {
+	ctx.ResponseData.WriteHeader(204)
+	return nil
+}
+
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *DeleteKeyContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *DeleteKeyContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *DeleteKeyContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// ListKeyContext provides the key list action context.
+type ListKeyContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+}
+
+// NewListKeyContext parses the incoming request URL and body, performs validations and creates the
+// context used by the key controller list action.
+func NewListKeyContext(ctx context.Context, service *goa.Service) (*ListKeyContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListKeyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *ListKeyContext) OK(r PinbaseKeyCollection) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.key+json; type=collection")
+	if r == nil {
+		r = PinbaseKeyCollection{}
+	}
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// OKSecret sends a HTTP response with status code 200.
+func (ctx *ListKeyContext) OKSecret(r PinbaseKeySecretCollection) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.key+json; type=collection")
+	if r == nil {
+		r = PinbaseKeySecretCollection{}
+	}
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ListKeyContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// ShowKeyContext provides the key show action context.
+type ShowKeyContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	KeyID string
+}
+
+// NewShowKeyContext parses the incoming request URL and body, performs validations and creates the
+// context used by the key controller show action.
+func NewShowKeyContext(ctx context.Context, service *goa.Service) (*ShowKeyContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ShowKeyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramKeyID := req.Params["keyID"]
+	if len(paramKeyID) > 0 {
+		rawKeyID := paramKeyID[0]
+		rctx.KeyID = rawKeyID
+	}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *ShowKeyContext) OK(r *PinbaseKey) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.key+json")
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// OKSecret sends a HTTP response with status code 200.
+func (ctx *ShowKeyContext) OKSecret(r *PinbaseKeySecret) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.key+json")
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ShowKeyContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *ShowKeyContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// CreateNodeContext provides the node create action context.
+type CreateNodeContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Payload *CreateNodePayload
+}
+
+// NewCreateNodeContext parses the incoming request URL and body, performs validations and creates the
+// context used by the node controller create action.
+func NewCreateNodeContext(ctx context.Context, service *goa.Service) (*CreateNodeContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := CreateNodeContext{Context: ctx, ResponseData: resp, RequestData: req}
+	return &rctx, err
+}
+
+// createNodePayload is the node create action payload.
+type createNodePayload struct {
+	// The host:port of the node's IPFS API
+	APIAddress *string `form:"api-address,omitempty" json:"api-address,omitempty" xml:"api-address,omitempty"`
+	// The name pins refer to the node by
+	Name *string `form:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"`
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *createNodePayload) Validate() (err error) {
+	if payload.Name == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "name"))
+	}
+	if payload.APIAddress == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "api-address"))
+	}
+	return
+}
+
+// Publicize creates CreateNodePayload from createNodePayload
+func (payload *createNodePayload) Publicize() *CreateNodePayload {
+	var pub CreateNodePayload
+	if payload.APIAddress != nil {
+		pub.APIAddress = *payload.APIAddress
+	}
+	if payload.Name != nil {
+		pub.Name = *payload.Name
+	}
+	return &pub
+}
+
+// CreateNodePayload is the node create action payload.
+type CreateNodePayload struct {
+	// The host:port of the node's IPFS API
+	APIAddress string `form:"api-address" json:"api-address" xml:"api-address"`
+	// The name pins refer to the node by
+	Name string `form:"name" json:"name" xml:"name"`
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *CreateNodePayload) Validate() (err error) {
+	if payload.Name == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "name"))
+	}
+	if payload.APIAddress == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "api-address"))
+	}
+	return
+}
+
+// Created sends a HTTP response with status code 201.
+func (ctx *CreateNodeContext) Created() error {
+	ctx.ResponseData.WriteHeader(201)
+	return nil
+}
+
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *CreateNodeContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *CreateNodeContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// DeleteNodeContext provides the node delete action context.
+type DeleteNodeContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	NodeName string
+}
+
+// NewDeleteNodeContext parses the incoming request URL and body, performs validations and creates the
+// context used by the node controller delete action.
+func NewDeleteNodeContext(ctx context.Context, service *goa.Service) (*DeleteNodeContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := DeleteNodeContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramNodeName := req.Params["nodeName"]
+	if len(paramNodeName) > 0 {
+		rawNodeName := paramNodeName[0]
+		rctx.NodeName = rawNodeName
+	}
+	return &rctx, err
+}
+
+// NoContent sends a HTTP response with status code 204.
+func (ctx *DeleteNodeContext) NoContent() error {
+	ctx.ResponseData.WriteHeader(204)
+	return nil
+}
+
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *DeleteNodeContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *DeleteNodeContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *DeleteNodeContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// ListNodeContext provides the node list action context.
+type ListNodeContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+}
+
+// NewListNodeContext parses the incoming request URL and body, performs validations and creates the
+// context used by the node controller list action.
+func NewListNodeContext(ctx context.Context, service *goa.Service) (*ListNodeContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListNodeContext{Context: ctx, ResponseData: resp, RequestData: req}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *ListNodeContext) OK(r PinbaseNodeCollection) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.node+json; type=collection")
+	if r == nil {
+		r = PinbaseNodeCollection{}
+	}
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ListNodeContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// ShowNodeContext provides the node show action context.
+type ShowNodeContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	NodeName string
+}
+
+// NewShowNodeContext parses the incoming request URL and body, performs validations and creates the
+// context used by the node controller show action.
+func NewShowNodeContext(ctx context.Context, service *goa.Service) (*ShowNodeContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ShowNodeContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramNodeName := req.Params["nodeName"]
+	if len(paramNodeName) > 0 {
+		rawNodeName := paramNodeName[0]
+		rctx.NodeName = rawNodeName
+	}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *ShowNodeContext) OK(r *PinbaseNode) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.node+json")
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ShowNodeContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *ShowNodeContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// UpdateNodeContext provides the node update action context.
+type UpdateNodeContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	NodeName string
+	Payload  *UpdateNodePayload
+}
+
+// NewUpdateNodeContext parses the incoming request URL and body, performs validations and creates the
+// context used by the node controller update action.
+func NewUpdateNodeContext(ctx context.Context, service *goa.Service) (*UpdateNodeContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := UpdateNodeContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramNodeName := req.Params["nodeName"]
+	if len(paramNodeName) > 0 {
+		rawNodeName := paramNodeName[0]
+		rctx.NodeName = rawNodeName
+	}
+	return &rctx, err
+}
+
+// updateNodePayload is the node update action payload.
+type updateNodePayload struct {
+	// The host:port of the node's IPFS API
+	APIAddress *string `form:"api-address,omitempty" json:"api-address,omitempty" xml:"api-address,omitempty"`
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *updateNodePayload) Validate() (err error) {
+	if payload.APIAddress == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "api-address"))
+	}
+	return
+}
+
+// Publicize creates UpdateNodePayload from updateNodePayload
+func (payload *updateNodePayload) Publicize() *UpdateNodePayload {
+	var pub UpdateNodePayload
+	if payload.APIAddress != nil {
+		pub.APIAddress = *payload.APIAddress
+	}
+	return &pub
+}
+
+// UpdateNodePayload is the node update action payload.
+type UpdateNodePayload struct {
+	// The host:port of the node's IPFS API
+	APIAddress string `form:"api-address" json:"api-address" xml:"api-address"`
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *UpdateNodePayload) Validate() (err error) {
+	if payload.APIAddress == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "api-address"))
+	}
+	return
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *UpdateNodeContext) OK(r *PinbaseNode) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.node+json")
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *UpdateNodeContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *UpdateNodeContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *UpdateNodeContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
 // CreatePartyContext provides the party create action context.
 type CreatePartyContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
-	Payload *CreatePartyPayload
+	Payload *CreatePartyPayload
+}
+
+// NewCreatePartyContext parses the incoming request URL and body, performs validations and creates the
+// context used by the party controller create action.
+func NewCreatePartyContext(ctx context.Context, service *goa.Service) (*CreatePartyContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := CreatePartyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	return &rctx, err
+}
+
+// createPartyPayload is the party create action payload.
+type createPartyPayload struct {
+	// A helpful description of the party
+	Description *string `form:"description,omitempty" json:"description,omitempty" xml:"description,omitempty"`
+	// The hash of the object describing the party
+	Hash *string `form:"hash,omitempty" json:"hash,omitempty" xml:"hash,omitempty"`
+	// Most bytes the party's wanted pins may add up to, 0 for no limit
+	MaxBytes *int `form:"max-bytes,omitempty" json:"max-bytes,omitempty" xml:"max-bytes,omitempty"`
+	// Most pins the party may want pinned at once, 0 for no limit
+	MaxPins *int `form:"max-pins,omitempty" json:"max-pins,omitempty" xml:"max-pins,omitempty"`
+	// Base64 ed25519 public key the party is bound to, requests to its pins must then be signed with the matching private key
+	PublicKey *string `form:"public-key,omitempty" json:"public-key,omitempty" xml:"public-key,omitempty"`
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *createPartyPayload) Validate() (err error) {
+	if payload.Hash == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "hash"))
+	}
+	if payload.Description == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "description"))
+	}
+	if payload.MaxBytes != nil {
+		if *payload.MaxBytes < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`raw.max-bytes`, *payload.MaxBytes, 0, true))
+		}
+	}
+	if payload.MaxPins != nil {
+		if *payload.MaxPins < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`raw.max-pins`, *payload.MaxPins, 0, true))
+		}
+	}
+	return
+}
+
+// Publicize creates CreatePartyPayload from createPartyPayload
+func (payload *createPartyPayload) Publicize() *CreatePartyPayload {
+	var pub CreatePartyPayload
+	if payload.Description != nil {
+		pub.Description = *payload.Description
+	}
+	if payload.Hash != nil {
+		pub.Hash = *payload.Hash
+	}
+	if payload.MaxBytes != nil {
+		pub.MaxBytes = payload.MaxBytes
+	}
+	if payload.MaxPins != nil {
+		pub.MaxPins = payload.MaxPins
+	}
+	if payload.PublicKey != nil {
+		pub.PublicKey = payload.PublicKey
+	}
+	return &pub
+}
+
+// CreatePartyPayload is the party create action payload.
+type CreatePartyPayload struct {
+	// A helpful description of the party
+	Description string `form:"description" json:"description" xml:"description"`
+	// The hash of the object describing the party
+	Hash string `form:"hash" json:"hash" xml:"hash"`
+	// Most bytes the party's wanted pins may add up to, 0 for no limit
+	MaxBytes *int `form:"max-bytes,omitempty" json:"max-bytes,omitempty" xml:"max-bytes,omitempty"`
+	// Most pins the party may want pinned at once, 0 for no limit
+	MaxPins *int `form:"max-pins,omitempty" json:"max-pins,omitempty" xml:"max-pins,omitempty"`
+	// Base64 ed25519 public key the party is bound to, requests to its pins must then be signed with the matching private key
+	PublicKey *string `form:"public-key,omitempty" json:"public-key,omitempty" xml:"public-key,omitempty"`
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *CreatePartyPayload) Validate() (err error) {
+	if payload.Hash == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "hash"))
+	}
+	if payload.Description == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "description"))
+	}
+	if payload.MaxBytes != nil {
+		if *payload.MaxBytes < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`raw.max-bytes`, *payload.MaxBytes, 0, true))
+		}
+	}
+	if payload.MaxPins != nil {
+		if *payload.MaxPins < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`raw.max-pins`, *payload.MaxPins, 0, true))
+		}
+	}
+	return
+}
+
+// Created sends a HTTP response with status code 201.
+func (ctx *CreatePartyContext) Created() error {
+	ctx.ResponseData.WriteHeader(201)
+	return nil
+}
+
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *CreatePartyContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *CreatePartyContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// DeletePartyContext provides the party delete action context.
+type DeletePartyContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	PartyHash string
+}
+
+// NewDeletePartyContext parses the incoming request URL and body, performs validations and creates the
+// context used by the party controller delete action.
+func NewDeletePartyContext(ctx context.Context, service *goa.Service) (*DeletePartyContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := DeletePartyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramPartyHash := req.Params["partyHash"]
+	if len(paramPartyHash) > 0 {
+		rawPartyHash := paramPartyHash[0]
+		rctx.PartyHash = rawPartyHash
+	}
+	return &rctx, err
+}
+
+// NoContent sends a HTTP response with status code 204.
+func (ctx *DeletePartyContext) NoContent() error {
+	ctx.ResponseData.WriteHeader(204)
+	return nil
+}
+
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *DeletePartyContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *DeletePartyContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *DeletePartyContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// GrantPartyContext provides the party grant action context.
+type GrantPartyContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	KeyID     string
+	PartyHash string
+	Payload   *GrantPartyPayload
+}
+
+// NewGrantPartyContext parses the incoming request URL and body, performs validations and creates the
+// context used by the party controller grant action.
+func NewGrantPartyContext(ctx context.Context, service *goa.Service) (*GrantPartyContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := GrantPartyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramKeyID := req.Params["keyID"]
+	if len(paramKeyID) > 0 {
+		rawKeyID := paramKeyID[0]
+		rctx.KeyID = rawKeyID
+	}
+	paramPartyHash := req.Params["partyHash"]
+	if len(paramPartyHash) > 0 {
+		rawPartyHash := paramPartyHash[0]
+		rctx.PartyHash = rawPartyHash
+	}
+	return &rctx, err
+}
+
+// grantPartyPayload is the party grant action payload.
+type grantPartyPayload struct {
+	// What the key may do with the party
+	Role *string `form:"role,omitempty" json:"role,omitempty" xml:"role,omitempty"`
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *grantPartyPayload) Validate() (err error) {
+	if payload.Role == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "role"))
+	}
+	if payload.Role != nil {
+		if !(*payload.Role == "none" || *payload.Role == "read-only" || *payload.Role == "party-owner") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(`raw.role`, *payload.Role, []interface{}{"none", "read-only", "party-owner"}))
+		}
+	}
+	return
+}
+
+// Publicize creates GrantPartyPayload from grantPartyPayload
+func (payload *grantPartyPayload) Publicize() *GrantPartyPayload {
+	var pub GrantPartyPayload
+	if payload.Role != nil {
+		pub.Role = *payload.Role
+	}
+	return &pub
+}
+
+// GrantPartyPayload is the party grant action payload.
+type GrantPartyPayload struct {
+	// What the key may do with the party
+	Role string `form:"role" json:"role" xml:"role"`
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *GrantPartyPayload) Validate() (err error) {
+	if payload.Role == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "role"))
+	}
+	if !(payload.Role == "none" || payload.Role == "read-only" || payload.Role == "party-owner") {
+		err = goa.MergeErrors(err, goa.InvalidEnumValueError(`raw.role`, payload.Role, []interface{}{"none", "read-only", "party-owner"}))
+	}
+	return
+}
+
+// NoContent sends a HTTP response with status code 204.
+func (ctx *GrantPartyContext) NoContent() error {
+	ctx.ResponseData.WriteHeader(204)
+	return nil
+}
+
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *GrantPartyContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *GrantPartyContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *GrantPartyContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// ListPartyContext provides the party list action context.
+type ListPartyContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+}
+
+// NewListPartyContext parses the incoming request URL and body, performs validations and creates the
+// context used by the party controller list action.
+func NewListPartyContext(ctx context.Context, service *goa.Service) (*ListPartyContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListPartyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *ListPartyContext) OK(r PinbasePartyCollection) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.party+json; type=collection")
+	if r == nil {
+		r = PinbasePartyCollection{}
+	}
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ListPartyContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// ShowPartyContext provides the party show action context.
+type ShowPartyContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	PartyHash string
+}
+
+// NewShowPartyContext parses the incoming request URL and body, performs validations and creates the
+// context used by the party controller show action.
+func NewShowPartyContext(ctx context.Context, service *goa.Service) (*ShowPartyContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ShowPartyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramPartyHash := req.Params["partyHash"]
+	if len(paramPartyHash) > 0 {
+		rawPartyHash := paramPartyHash[0]
+		rctx.PartyHash = rawPartyHash
+	}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *ShowPartyContext) OK(r *PinbaseParty) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.party+json")
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ShowPartyContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *ShowPartyContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// UpdatePartyContext provides the party update action context.
+type UpdatePartyContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	PartyHash string
+	Payload   *PartyUpdatePayload
+}
+
+// NewUpdatePartyContext parses the incoming request URL and body, performs validations and creates the
+// context used by the party controller update action.
+func NewUpdatePartyContext(ctx context.Context, service *goa.Service) (*UpdatePartyContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := UpdatePartyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramPartyHash := req.Params["partyHash"]
+	if len(paramPartyHash) > 0 {
+		rawPartyHash := paramPartyHash[0]
+		rctx.PartyHash = rawPartyHash
+	}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *UpdatePartyContext) OK(r *PinbaseParty) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.party+json")
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *UpdatePartyContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *UpdatePartyContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *UpdatePartyContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// CreatePinContext provides the pin create action context.
+type CreatePinContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	PartyHash string
+	Payload   *CreatePinPayload
 }
 
-// NewCreatePartyContext parses the incoming request URL and body, performs validations and creates the
-// context used by the party controller create action.
-func NewCreatePartyContext(ctx context.Context, service *goa.Service) (*CreatePartyContext, error) {
+// NewCreatePinContext parses the incoming request URL and body, performs validations and creates the
+// context used by the pin controller create action.
+func NewCreatePinContext(ctx context.Context, service *goa.Service) (*CreatePinContext, error) {
 	var err error
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
-	rctx := CreatePartyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	rctx := CreatePinContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramPartyHash := req.Params["partyHash"]
+	if len(paramPartyHash) > 0 {
+		rawPartyHash := paramPartyHash[0]
+		rctx.PartyHash = rawPartyHash
+	}
 	return &rctx, err
 }
 
-// createPartyPayload is the party create action payload.
-type createPartyPayload struct {
-	// A helpful description of the party
-	Description *string `form:"description,omitempty" json:"description,omitempty" xml:"description,omitempty"`
-	// The hash of the object describing the party
+// createPinPayload is the pin create action payload.
+type createPinPayload struct {
+	// Aliases for the pinned object
+	Aliases []string `form:"aliases,omitempty" json:"aliases,omitempty" xml:"aliases,omitempty"`
+	// When the pin stops being wanted, never if left out
+	ExpiresAt *time.Time `form:"expires-at,omitempty" json:"expires-at,omitempty" xml:"expires-at,omitempty"`
+	// The hash of the object to be pinned, which is kept as a CIDv1 in base32, an IPFS path such as /ipfs/<hash>/sub/path to pin the object it points at, or the IPNS name or DNSLink domain of a name pin
 	Hash *string `form:"hash,omitempty" json:"hash,omitempty" xml:"hash,omitempty"`
+	// What the hash is: the hash to pin, or an IPNS name or DNSLink domain whose target gets pinned; hash if left out
+	Kind *string `form:"kind,omitempty" json:"kind,omitempty" xml:"kind,omitempty"`
+	// Pin everything the object links to (recursive) or just its root block (direct)
+	Mode *string `form:"mode,omitempty" json:"mode,omitempty" xml:"mode,omitempty"`
+	// When the pin's window closes, it stays open if left out
+	NotAfter *time.Time `form:"not-after,omitempty" json:"not-after,omitempty" xml:"not-after,omitempty"`
+	// When the pin's window opens, it is wanted from the start if left out
+	NotBefore *time.Time `form:"not-before,omitempty" json:"not-before,omitempty" xml:"not-before,omitempty"`
+	// Number of IPFS nodes the object should be pinned on
+	Replication *int `form:"replication,omitempty" json:"replication,omitempty" xml:"replication,omitempty"`
+	// How long from now the pin stays wanted, as in "720h" or "30d", instead of an expires-at
+	TTL *string `form:"ttl,omitempty" json:"ttl,omitempty" xml:"ttl,omitempty"`
+	// Indicates that the party wants to actually pin the object
+	WantPinned *bool `form:"want-pinned,omitempty" json:"want-pinned,omitempty" xml:"want-pinned,omitempty"`
+}
+
+// Finalize sets the default values defined in the design.
+func (payload *createPinPayload) Finalize() {
+	var defaultMode = "recursive"
+	if payload.Mode == nil {
+		payload.Mode = &defaultMode
+	}
+	var defaultReplication = 1
+	if payload.Replication == nil {
+		payload.Replication = &defaultReplication
+	}
 }
 
 // Validate runs the validation rules defined in the design.
-func (payload *createPartyPayload) Validate() (err error) {
+func (payload *createPinPayload) Validate() (err error) {
 	if payload.Hash == nil {
 		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "hash"))
 	}
-	if payload.Description == nil {
-		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "description"))
+	if payload.Aliases == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "aliases"))
+	}
+	if payload.WantPinned == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "want-pinned"))
+	}
+	if payload.Kind != nil {
+		if !(*payload.Kind == "hash" || *payload.Kind == "name") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(`raw.kind`, *payload.Kind, []interface{}{"hash", "name"}))
+		}
+	}
+	if payload.Mode != nil {
+		if !(*payload.Mode == "recursive" || *payload.Mode == "direct") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(`raw.mode`, *payload.Mode, []interface{}{"recursive", "direct"}))
+		}
+	}
+	if payload.Replication != nil {
+		if *payload.Replication < 1 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`raw.replication`, *payload.Replication, 1, true))
+		}
 	}
 	return
 }
 
-// Publicize creates CreatePartyPayload from createPartyPayload
-func (payload *createPartyPayload) Publicize() *CreatePartyPayload {
-	var pub CreatePartyPayload
-	if payload.Description != nil {
-		pub.Description = *payload.Description
+// Publicize creates CreatePinPayload from createPinPayload
+func (payload *createPinPayload) Publicize() *CreatePinPayload {
+	var pub CreatePinPayload
+	if payload.Aliases != nil {
+		pub.Aliases = payload.Aliases
+	}
+	if payload.ExpiresAt != nil {
+		pub.ExpiresAt = payload.ExpiresAt
 	}
 	if payload.Hash != nil {
 		pub.Hash = *payload.Hash
 	}
+	if payload.Kind != nil {
+		pub.Kind = payload.Kind
+	}
+	if payload.Mode != nil {
+		pub.Mode = *payload.Mode
+	}
+	if payload.NotAfter != nil {
+		pub.NotAfter = payload.NotAfter
+	}
+	if payload.NotBefore != nil {
+		pub.NotBefore = payload.NotBefore
+	}
+	if payload.Replication != nil {
+		pub.Replication = *payload.Replication
+	}
+	if payload.TTL != nil {
+		pub.TTL = payload.TTL
+	}
+	if payload.WantPinned != nil {
+		pub.WantPinned = *payload.WantPinned
+	}
 	return &pub
 }
 
-// CreatePartyPayload is the party create action payload.
-type CreatePartyPayload struct {
-	// A helpful description of the party
-	Description string `form:"description" json:"description" xml:"description"`
-	// The hash of the object describing the party
+// CreatePinPayload is the pin create action payload.
+type CreatePinPayload struct {
+	// Aliases for the pinned object
+	Aliases []string `form:"aliases" json:"aliases" xml:"aliases"`
+	// When the pin stops being wanted, never if left out
+	ExpiresAt *time.Time `form:"expires-at,omitempty" json:"expires-at,omitempty" xml:"expires-at,omitempty"`
+	// The hash of the object to be pinned, which is kept as a CIDv1 in base32, an IPFS path such as /ipfs/<hash>/sub/path to pin the object it points at, or the IPNS name or DNSLink domain of a name pin
 	Hash string `form:"hash" json:"hash" xml:"hash"`
+	// What the hash is: the hash to pin, or an IPNS name or DNSLink domain whose target gets pinned; hash if left out
+	Kind *string `form:"kind,omitempty" json:"kind,omitempty" xml:"kind,omitempty"`
+	// Pin everything the object links to (recursive) or just its root block (direct)
+	Mode string `form:"mode" json:"mode" xml:"mode"`
+	// When the pin's window closes, it stays open if left out
+	NotAfter *time.Time `form:"not-after,omitempty" json:"not-after,omitempty" xml:"not-after,omitempty"`
+	// When the pin's window opens, it is wanted from the start if left out
+	NotBefore *time.Time `form:"not-before,omitempty" json:"not-before,omitempty" xml:"not-before,omitempty"`
+	// Number of IPFS nodes the object should be pinned on
+	Replication int `form:"replication" json:"replication" xml:"replication"`
+	// How long from now the pin stays wanted, as in "720h" or "30d", instead of an expires-at
+	TTL *string `form:"ttl,omitempty" json:"ttl,omitempty" xml:"ttl,omitempty"`
+	// Indicates that the party wants to actually pin the object
+	WantPinned bool `form:"want-pinned" json:"want-pinned" xml:"want-pinned"`
 }
 
 // Validate runs the validation rules defined in the design.
-func (payload *CreatePartyPayload) Validate() (err error) {
+func (payload *CreatePinPayload) Validate() (err error) {
 	if payload.Hash == "" {
 		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "hash"))
 	}
-	if payload.Description == "" {
-		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "description"))
+	if payload.Aliases == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "aliases"))
+	}
+
+	if payload.Kind != nil {
+		if !(*payload.Kind == "hash" || *payload.Kind == "name") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(`raw.kind`, *payload.Kind, []interface{}{"hash", "name"}))
+		}
+	}
+	if !(payload.Mode == "recursive" || payload.Mode == "direct") {
+		err = goa.MergeErrors(err, goa.InvalidEnumValueError(`raw.mode`, payload.Mode, []interface{}{"recursive", "direct"}))
+	}
+	if payload.Replication < 1 {
+		err = goa.MergeErrors(err, goa.InvalidRangeError(`raw.replication`, payload.Replication, 1, true))
 	}
 	return
 }
 
 // Created sends a HTTP response with status code 201.
-func (ctx *CreatePartyContext) Created() error {
+func (ctx *CreatePinContext) Created() error {
 	ctx.ResponseData.WriteHeader(201)
 	return nil
 }
 
 // BadRequest sends a HTTP response with status code 400.
-func (ctx *CreatePartyContext) BadRequest(r error) error {
+func (ctx *CreatePinContext) BadRequest(r error) error {
 	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
 	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
 }
 
-// DeletePartyContext provides the party delete action context.
-type DeletePartyContext struct {
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *CreatePinContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// DeletePinContext provides the pin delete action context.
+type DeletePinContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
 	PartyHash string
+	PinHash   string
 }
 
-// NewDeletePartyContext parses the incoming request URL and body, performs validations and creates the
-// context used by the party controller delete action.
-func NewDeletePartyContext(ctx context.Context, service *goa.Service) (*DeletePartyContext, error) {
+// NewDeletePinContext parses the incoming request URL and body, performs validations and creates the
+// context used by the pin controller delete action.
+func NewDeletePinContext(ctx context.Context, service *goa.Service) (*DeletePinContext, error) {
 	var err error
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
-	rctx := DeletePartyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	rctx := DeletePinContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramPartyHash := req.Params["partyHash"]
+	if len(paramPartyHash) > 0 {
+		rawPartyHash := paramPartyHash[0]
+		rctx.PartyHash = rawPartyHash
+	}
+	paramPinHash := req.Params["pinHash"]
+	if len(paramPinHash) > 0 {
+		rawPinHash := paramPinHash[0]
+		rctx.PinHash = rawPinHash
+	}
+	return &rctx, err
+}
+
+// NoContent sends a HTTP response with status code 204.
+func (ctx *DeletePinContext) NoContent() error {
+	ctx.ResponseData.WriteHeader(204)
+	return nil
+}
+
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *DeletePinContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *DeletePinContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *DeletePinContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// HistoryPinContext provides the pin history action context.
+type HistoryPinContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	PartyHash string
+	PinHash   string
+}
+
+// NewHistoryPinContext parses the incoming request URL and body, performs validations and creates the
+// context used by the pin controller history action.
+func NewHistoryPinContext(ctx context.Context, service *goa.Service) (*HistoryPinContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := HistoryPinContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramPartyHash := req.Params["partyHash"]
+	if len(paramPartyHash) > 0 {
+		rawPartyHash := paramPartyHash[0]
+		rctx.PartyHash = rawPartyHash
+	}
+	paramPinHash := req.Params["pinHash"]
+	if len(paramPinHash) > 0 {
+		rawPinHash := paramPinHash[0]
+		rctx.PinHash = rawPinHash
+	}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *HistoryPinContext) OK(r PinbasePinHistoryCollection) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.pin-history+json; type=collection")
+	if r == nil {
+		r = PinbasePinHistoryCollection{}
+	}
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *HistoryPinContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *HistoryPinContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *HistoryPinContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// ListPinContext provides the pin list action context.
+type ListPinContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	PartyHash string
+}
+
+// NewListPinContext parses the incoming request URL and body, performs validations and creates the
+// context used by the pin controller list action.
+func NewListPinContext(ctx context.Context, service *goa.Service) (*ListPinContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListPinContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramPartyHash := req.Params["partyHash"]
+	if len(paramPartyHash) > 0 {
+		rawPartyHash := paramPartyHash[0]
+		rctx.PartyHash = rawPartyHash
+	}
+	return &rctx, err
+}
+
+// OK sends a HTTP response with status code 200.
+func (ctx *ListPinContext) OK(r PinbasePinCollection) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.pin+json; type=collection")
+	if r == nil {
+		r = PinbasePinCollection{}
+	}
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ListPinContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// RenewPinContext provides the pin renew action context.
+type RenewPinContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	PartyHash string
+	PinHash   string
+	Payload   *PinRenewPayload
+}
+
+// NewRenewPinContext parses the incoming request URL and body, performs validations and creates the
+// context used by the pin controller renew action.
+func NewRenewPinContext(ctx context.Context, service *goa.Service) (*RenewPinContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := RenewPinContext{Context: ctx, ResponseData: resp, RequestData: req}
 	paramPartyHash := req.Params["partyHash"]
 	if len(paramPartyHash) > 0 {
 		rawPartyHash := paramPartyHash[0]
 		rctx.PartyHash = rawPartyHash
 	}
+	paramPinHash := req.Params["pinHash"]
+	if len(paramPinHash) > 0 {
+		rawPinHash := paramPinHash[0]
+		rctx.PinHash = rawPinHash
+	}
 	return &rctx, err
 }
 
-// NoContent sends a HTTP response with status code 204.
-func (ctx *DeletePartyContext) NoContent() error {
-	ctx.ResponseData.WriteHeader(204)
-	return nil
+// OK sends a HTTP response with status code 200.
+func (ctx *RenewPinContext) OK(r *PinbasePin) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.pin+json")
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
 }
 
 // BadRequest sends a HTTP response with status code 400.
-func (ctx *DeletePartyContext) BadRequest(r error) error {
+func (ctx *RenewPinContext) BadRequest(r error) error {
 	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
 	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
 }
 
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *RenewPinContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
 // NotFound sends a HTTP response with status code 404.
-func (ctx *DeletePartyContext) NotFound() error {
+func (ctx *RenewPinContext) NotFound() error {
 	ctx.ResponseData.WriteHeader(404)
 	return nil
 }
 
-// ListPartyContext provides the party list action context.
-type ListPartyContext struct {
+// ResetPinContext provides the pin reset action context.
+type ResetPinContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
+	PartyHash string
+	PinHash   string
 }
 
-// NewListPartyContext parses the incoming request URL and body, performs validations and creates the
-// context used by the party controller list action.
-func NewListPartyContext(ctx context.Context, service *goa.Service) (*ListPartyContext, error) {
+// NewResetPinContext parses the incoming request URL and body, performs validations and creates the
+// context used by the pin controller reset action.
+func NewResetPinContext(ctx context.Context, service *goa.Service) (*ResetPinContext, error) {
 	var err error
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
-	rctx := ListPartyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	rctx := ResetPinContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramPartyHash := req.Params["partyHash"]
+	if len(paramPartyHash) > 0 {
+		rawPartyHash := paramPartyHash[0]
+		rctx.PartyHash = rawPartyHash
+	}
+	paramPinHash := req.Params["pinHash"]
+	if len(paramPinHash) > 0 {
+		rawPinHash := paramPinHash[0]
+		rctx.PinHash = rawPinHash
+	}
 	return &rctx, err
 }
 
 // OK sends a HTTP response with status code 200.
-func (ctx *ListPartyContext) OK(r PinbasePartyCollection) error {
-	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.party+json; type=collection")
-	if r == nil {
-		r = PinbasePartyCollection{}
-	}
+func (ctx *ResetPinContext) OK(r *PinbasePin) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.pin+json")
 	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
 }
 
-// ShowPartyContext provides the party show action context.
-type ShowPartyContext struct {
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *ResetPinContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ResetPinContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *ResetPinContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// ShowPinContext provides the pin show action context.
+type ShowPinContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
 	PartyHash string
+	PinHash   string
 }
 
-// NewShowPartyContext parses the incoming request URL and body, performs validations and creates the
-// context used by the party controller show action.
-func NewShowPartyContext(ctx context.Context, service *goa.Service) (*ShowPartyContext, error) {
+// NewShowPinContext parses the incoming request URL and body, performs validations and creates the
+// context used by the pin controller show action.
+func NewShowPinContext(ctx context.Context, service *goa.Service) (*ShowPinContext, error) {
 	var err error
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
-	rctx := ShowPartyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	rctx := ShowPinContext{Context: ctx, ResponseData: resp, RequestData: req}
 	paramPartyHash := req.Params["partyHash"]
 	if len(paramPartyHash) > 0 {
 		rawPartyHash := paramPartyHash[0]
 		rctx.PartyHash = rawPartyHash
 	}
+	paramPinHash := req.Params["pinHash"]
+	if len(paramPinHash) > 0 {
+		rawPinHash := paramPinHash[0]
+		rctx.PinHash = rawPinHash
+	}
 	return &rctx, err
 }
 
 // OK sends a HTTP response with status code 200.
-func (ctx *ShowPartyContext) OK(r *PinbaseParty) error {
-	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.party+json")
+func (ctx *ShowPinContext) OK(r *PinbasePin) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.pin+json")
 	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
 }
 
+// BadRequest sends a HTTP response with status code 400.
+func (ctx *ShowPinContext) BadRequest(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ShowPinContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
 // NotFound sends a HTTP response with status code 404.
-func (ctx *ShowPartyContext) NotFound() error {
+func (ctx *ShowPinContext) NotFound() error {
 	ctx.ResponseData.WriteHeader(404)
 	return nil
 }
 
-// UpdatePartyContext provides the party update action context.
-type UpdatePartyContext struct {
+// UpdatePinContext provides the pin update action context.
+type UpdatePinContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
 	PartyHash string
-	Payload   *PartyUpdatePayload
+	PinHash   string
+	Payload   *PinUpdatePayload
 }
 
-// NewUpdatePartyContext parses the incoming request URL and body, performs validations and creates the
-// context used by the party controller update action.
-func NewUpdatePartyContext(ctx context.Context, service *goa.Service) (*UpdatePartyContext, error) {
+// NewUpdatePinContext parses the incoming request URL and body, performs validations and creates the
+// context used by the pin controller update action.
+func NewUpdatePinContext(ctx context.Context, service *goa.Service) (*UpdatePinContext, error) {
 	var err error
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
-	rctx := UpdatePartyContext{Context: ctx, ResponseData: resp, RequestData: req}
+	rctx := UpdatePinContext{Context: ctx, ResponseData: resp, RequestData: req}
 	paramPartyHash := req.Params["partyHash"]
 	if len(paramPartyHash) > 0 {
 		rawPartyHash := paramPartyHash[0]
 		rctx.PartyHash = rawPartyHash
 	}
+	paramPinHash := req.Params["pinHash"]
+	if len(paramPinHash) > 0 {
+		rawPinHash := paramPinHash[0]
+		rctx.PinHash = rawPinHash
+	}
 	return &rctx, err
 }
 
 // OK sends a HTTP response with status code 200.
-func (ctx *UpdatePartyContext) OK(r *PinbaseParty) error {
-	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.party+json")
+func (ctx *UpdatePinContext) OK(r *PinbasePin) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.pin+json")
 	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
 }
 
 // BadRequest sends a HTTP response with status code 400.
-func (ctx *UpdatePartyContext) BadRequest(r error) error {
+func (ctx *UpdatePinContext) BadRequest(r error) error {
 	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
 	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
 }
 
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *UpdatePinContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
 // NotFound sends a HTTP response with status code 404.
-func (ctx *UpdatePartyContext) NotFound() error {
+func (ctx *UpdatePinContext) NotFound() error {
 	ctx.ResponseData.WriteHeader(404)
 	return nil
 }
 
-// CreatePinContext provides the pin create action context.
-type CreatePinContext struct {
+// CreateWebhookContext provides the webhook create action context.
+type CreateWebhookContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
 	PartyHash string
-	Payload   *CreatePinPayload
+	Payload   *CreateWebhookPayload
 }
 
-// NewCreatePinContext parses the incoming request URL and body, performs validations and creates the
-// context used by the pin controller create action.
-func NewCreatePinContext(ctx context.Context, service *goa.Service) (*CreatePinContext, error) {
+// NewCreateWebhookContext parses the incoming request URL and body, performs validations and creates the
+// context used by the webhook controller create action.
+func NewCreateWebhookContext(ctx context.Context, service *goa.Service) (*CreateWebhookContext, error) {
 	var err error
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
-	rctx := CreatePinContext{Context: ctx, ResponseData: resp, RequestData: req}
+	rctx := CreateWebhookContext{Context: ctx, ResponseData: resp, RequestData: req}
 	paramPartyHash := req.Params["partyHash"]
 	if len(paramPartyHash) > 0 {
 		rawPartyHash := paramPartyHash[0]
@@ -269,143 +1632,145 @@ func NewCreatePinContext(ctx context.Context, service *goa.Service) (*CreatePinC
 	return &rctx, err
 }
 
-// createPinPayload is the pin create action payload.
-type createPinPayload struct {
-	// Aliases for the pinned object
-	Aliases []string `form:"aliases,omitempty" json:"aliases,omitempty" xml:"aliases,omitempty"`
-	// The hash of the object to be pinned
-	Hash *string `form:"hash,omitempty" json:"hash,omitempty" xml:"hash,omitempty"`
-	// Indicates that the party wants to actually pin the object
-	WantPinned *bool `form:"want-pinned,omitempty" json:"want-pinned,omitempty" xml:"want-pinned,omitempty"`
+// createWebhookPayload is the webhook create action payload.
+type createWebhookPayload struct {
+	// The http or https URL pin status changes are posted to
+	URL *string `form:"url,omitempty" json:"url,omitempty" xml:"url,omitempty"`
 }
 
 // Validate runs the validation rules defined in the design.
-func (payload *createPinPayload) Validate() (err error) {
-	if payload.Hash == nil {
-		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "hash"))
+func (payload *createWebhookPayload) Validate() (err error) {
+	if payload.URL == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "url"))
 	}
-	if payload.Aliases == nil {
-		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "aliases"))
-	}
-	if payload.WantPinned == nil {
-		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "want-pinned"))
+	if payload.URL != nil {
+		if err2 := goa.ValidateFormat(goa.FormatURI, *payload.URL); err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidFormatError(`raw.url`, *payload.URL, goa.FormatURI, err2))
+		}
 	}
 	return
 }
 
-// Publicize creates CreatePinPayload from createPinPayload
-func (payload *createPinPayload) Publicize() *CreatePinPayload {
-	var pub CreatePinPayload
-	if payload.Aliases != nil {
-		pub.Aliases = payload.Aliases
-	}
-	if payload.Hash != nil {
-		pub.Hash = *payload.Hash
-	}
-	if payload.WantPinned != nil {
-		pub.WantPinned = *payload.WantPinned
+// Publicize creates CreateWebhookPayload from createWebhookPayload
+func (payload *createWebhookPayload) Publicize() *CreateWebhookPayload {
+	var pub CreateWebhookPayload
+	if payload.URL != nil {
+		pub.URL = *payload.URL
 	}
 	return &pub
 }
 
-// CreatePinPayload is the pin create action payload.
-type CreatePinPayload struct {
-	// Aliases for the pinned object
-	Aliases []string `form:"aliases" json:"aliases" xml:"aliases"`
-	// The hash of the object to be pinned
-	Hash string `form:"hash" json:"hash" xml:"hash"`
-	// Indicates that the party wants to actually pin the object
-	WantPinned bool `form:"want-pinned" json:"want-pinned" xml:"want-pinned"`
+// CreateWebhookPayload is the webhook create action payload.
+type CreateWebhookPayload struct {
+	// The http or https URL pin status changes are posted to
+	URL string `form:"url" json:"url" xml:"url"`
 }
 
 // Validate runs the validation rules defined in the design.
-func (payload *CreatePinPayload) Validate() (err error) {
-	if payload.Hash == "" {
-		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "hash"))
+func (payload *CreateWebhookPayload) Validate() (err error) {
+	if payload.URL == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "url"))
 	}
-	if payload.Aliases == nil {
-		err = goa.MergeErrors(err, goa.MissingAttributeError(`raw`, "aliases"))
+	if err2 := goa.ValidateFormat(goa.FormatURI, payload.URL); err2 != nil {
+		err = goa.MergeErrors(err, goa.InvalidFormatError(`raw.url`, payload.URL, goa.FormatURI, err2))
 	}
-
 	return
 }
 
-// Created sends a HTTP response with status code 201.
-func (ctx *CreatePinContext) Created() error {
-	ctx.ResponseData.WriteHeader(201)
-	return nil
+// CreatedSecret sends a HTTP response with status code 201.
+func (ctx *CreateWebhookContext) CreatedSecret(r *PinbaseWebhookSecret) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.webhook+json")
+	return ctx.ResponseData.Service.Send(ctx.Context, 201, r)
 }
 
 // BadRequest sends a HTTP response with status code 400.
-func (ctx *CreatePinContext) BadRequest(r error) error {
+func (ctx *CreateWebhookContext) BadRequest(r error) error {
 	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
 	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
 }
 
-// DeletePinContext provides the pin delete action context.
-type DeletePinContext struct {
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *CreateWebhookContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
+// NotFound sends a HTTP response with status code 404.
+func (ctx *CreateWebhookContext) NotFound() error {
+	ctx.ResponseData.WriteHeader(404)
+	return nil
+}
+
+// DeleteWebhookContext provides the webhook delete action context.
+type DeleteWebhookContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
 	PartyHash string
-	PinHash   string
+	WebhookID string
 }
 
-// NewDeletePinContext parses the incoming request URL and body, performs validations and creates the
-// context used by the pin controller delete action.
-func NewDeletePinContext(ctx context.Context, service *goa.Service) (*DeletePinContext, error) {
+// NewDeleteWebhookContext parses the incoming request URL and body, performs validations and creates the
+// context used by the webhook controller delete action.
+func NewDeleteWebhookContext(ctx context.Context, service *goa.Service) (*DeleteWebhookContext, error) {
 	var err error
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
-	rctx := DeletePinContext{Context: ctx, ResponseData: resp, RequestData: req}
+	rctx := DeleteWebhookContext{Context: ctx, ResponseData: resp, RequestData: req}
 	paramPartyHash := req.Params["partyHash"]
 	if len(paramPartyHash) > 0 {
 		rawPartyHash := paramPartyHash[0]
 		rctx.PartyHash = rawPartyHash
 	}
-	paramPinHash := req.Params["pinHash"]
-	if len(paramPinHash) > 0 {
-		rawPinHash := paramPinHash[0]
-		rctx.PinHash = rawPinHash
+	paramWebhookID := req.Params["webhookID"]
+	if len(paramWebhookID) > 0 {
+		rawWebhookID := paramWebhookID[0]
+		rctx.WebhookID = rawWebhookID
 	}
 	return &rctx, err
 }
 
 // NoContent sends a HTTP response with status code 204.
-func (ctx *DeletePinContext) NoContent() error {
+func (ctx *DeleteWebhookContext) NoContent() error {
 	ctx.ResponseData.WriteHeader(204)
 	return nil
 }
 
 // BadRequest sends a HTTP response with status code 400.
-func (ctx *DeletePinContext) BadRequest(r error) error {
+func (ctx *DeleteWebhookContext) BadRequest(r error) error {
 	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
 	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
 }
 
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *DeleteWebhookContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
+}
+
 // NotFound sends a HTTP response with status code 404.
-func (ctx *DeletePinContext) NotFound() error {
+func (ctx *DeleteWebhookContext) NotFound() error {
 	ctx.ResponseData.WriteHeader(404)
 	return nil
 }
 
-// ListPinContext provides the pin list action context.
-type ListPinContext struct {
+// ListWebhookContext provides the webhook list action context.
+type ListWebhookContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
 	PartyHash string
 }
 
-// NewListPinContext parses the incoming request URL and body, performs validations and creates the
-// context used by the pin controller list action.
-func NewListPinContext(ctx context.Context, service *goa.Service) (*ListPinContext, error) {
+// NewListWebhookContext parses the incoming request URL and body, performs validations and creates the
+// context used by the webhook controller list action.
+func NewListWebhookContext(ctx context.Context, service *goa.Service) (*ListWebhookContext, error) {
 	var err error
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
-	rctx := ListPinContext{Context: ctx, ResponseData: resp, RequestData: req}
+	rctx := ListWebhookContext{Context: ctx, ResponseData: resp, RequestData: req}
 	paramPartyHash := req.Params["partyHash"]
 	if len(paramPartyHash) > 0 {
 		rawPartyHash := paramPartyHash[0]
@@ -415,101 +1780,85 @@ func NewListPinContext(ctx context.Context, service *goa.Service) (*ListPinConte
 }
 
 // OK sends a HTTP response with status code 200.
-func (ctx *ListPinContext) OK(r PinbasePinCollection) error {
-	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.pin+json; type=collection")
+func (ctx *ListWebhookContext) OK(r PinbaseWebhookCollection) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.webhook+json; type=collection")
 	if r == nil {
-		r = PinbasePinCollection{}
+		r = PinbaseWebhookCollection{}
 	}
 	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
 }
 
-// ShowPinContext provides the pin show action context.
-type ShowPinContext struct {
-	context.Context
-	*goa.ResponseData
-	*goa.RequestData
-	PartyHash string
-	PinHash   string
-}
-
-// NewShowPinContext parses the incoming request URL and body, performs validations and creates the
-// context used by the pin controller show action.
-func NewShowPinContext(ctx context.Context, service *goa.Service) (*ShowPinContext, error) {
-	var err error
-	resp := goa.ContextResponse(ctx)
-	resp.Service = service
-	req := goa.ContextRequest(ctx)
-	rctx := ShowPinContext{Context: ctx, ResponseData: resp, RequestData: req}
-	paramPartyHash := req.Params["partyHash"]
-	if len(paramPartyHash) > 0 {
-		rawPartyHash := paramPartyHash[0]
-		rctx.PartyHash = rawPartyHash
-	}
-	paramPinHash := req.Params["pinHash"]
-	if len(paramPinHash) > 0 {
-		rawPinHash := paramPinHash[0]
-		rctx.PinHash = rawPinHash
+// OKSecret sends a HTTP response with status code 200.
+func (ctx *ListWebhookContext) OKSecret(r PinbaseWebhookSecretCollection) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.webhook+json; type=collection")
+	if r == nil {
+		r = PinbaseWebhookSecretCollection{}
 	}
-	return &rctx, err
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
 }
 
-// OK sends a HTTP response with status code 200.
-func (ctx *ShowPinContext) OK(r *PinbasePin) error {
-	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.pin+json")
-	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ListWebhookContext) Forbidden(r error) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
 }
 
 // NotFound sends a HTTP response with status code 404.
-func (ctx *ShowPinContext) NotFound() error {
+func (ctx *ListWebhookContext) NotFound() error {
 	ctx.ResponseData.WriteHeader(404)
 	return nil
 }
 
-// UpdatePinContext provides the pin update action context.
-type UpdatePinContext struct {
+// ShowWebhookContext provides the webhook show action context.
+type ShowWebhookContext struct {
 	context.Context
 	*goa.ResponseData
 	*goa.RequestData
 	PartyHash string
-	PinHash   string
-	Payload   *PinUpdatePayload
+	WebhookID string
 }
 
-// NewUpdatePinContext parses the incoming request URL and body, performs validations and creates the
-// context used by the pin controller update action.
-func NewUpdatePinContext(ctx context.Context, service *goa.Service) (*UpdatePinContext, error) {
+// NewShowWebhookContext parses the incoming request URL and body, performs validations and creates the
+// context used by the webhook controller show action.
+func NewShowWebhookContext(ctx context.Context, service *goa.Service) (*ShowWebhookContext, error) {
 	var err error
 	resp := goa.ContextResponse(ctx)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
-	rctx := UpdatePinContext{Context: ctx, ResponseData: resp, RequestData: req}
+	rctx := ShowWebhookContext{Context: ctx, ResponseData: resp, RequestData: req}
 	paramPartyHash := req.Params["partyHash"]
 	if len(paramPartyHash) > 0 {
 		rawPartyHash := paramPartyHash[0]
 		rctx.PartyHash = rawPartyHash
 	}
-	paramPinHash := req.Params["pinHash"]
-	if len(paramPinHash) > 0 {
-		rawPinHash := paramPinHash[0]
-		rctx.PinHash = rawPinHash
+	paramWebhookID := req.Params["webhookID"]
+	if len(paramWebhookID) > 0 {
+		rawWebhookID := paramWebhookID[0]
+		rctx.WebhookID = rawWebhookID
 	}
 	return &rctx, err
 }
 
 // OK sends a HTTP response with status code 200.
-func (ctx *UpdatePinContext) OK(r *PinbasePin) error {
-	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.pin+json")
+func (ctx *ShowWebhookContext) OK(r *PinbaseWebhook) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.webhook+json")
 	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
 }
 
-// BadRequest sends a HTTP response with status code 400.
-func (ctx *UpdatePinContext) BadRequest(r error) error {
+// OKSecret sends a HTTP response with status code 200.
+func (ctx *ShowWebhookContext) OKSecret(r *PinbaseWebhookSecret) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.pinbase.webhook+json")
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+
+// Forbidden sends a HTTP response with status code 403.
+func (ctx *ShowWebhookContext) Forbidden(r error) error {
 	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.goa.error")
-	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+	return ctx.ResponseData.Service.Send(ctx.Context, 403, r)
 }
 
 // NotFound sends a HTTP response with status code 404.
-func (ctx *UpdatePinContext) NotFound() error {
+func (ctx *ShowWebhookContext) NotFound() error {
 	ctx.ResponseData.WriteHeader(404)
 	return nil
 }
@@ -28,11 +28,324 @@ func initService(service *goa.Service) {
 	service.Decoder.Register(goa.NewJSONDecoder, "*/*")
 }
 
+// ArchiveController is the controller interface for the Archive actions.
+type ArchiveController interface {
+	goa.Muxer
+	List(*ListArchiveContext) error
+}
+
+// MountArchiveController "mounts" a Archive resource controller on the given service.
+func MountArchiveController(service *goa.Service, ctrl ArchiveController) {
+	initService(service)
+	var h goa.Handler
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewListArchiveContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.List(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("GET", "/api/archive", ctrl.MuxHandler("List", h, nil))
+	service.LogInfo("mount", "ctrl", "Archive", "action", "List", "route", "GET /api/archive", "security", "api_key")
+}
+
+// EventController is the controller interface for the Event actions.
+type EventController interface {
+	goa.Muxer
+	Party(*PartyEventContext) error
+	Stream(*StreamEventContext) error
+}
+
+// MountEventController "mounts" a Event resource controller on the given service.
+func MountEventController(service *goa.Service, ctrl EventController) {
+	initService(service)
+	var h goa.Handler
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewPartyEventContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.Party(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("GET", "/api/events/:partyHash", ctrl.MuxHandler("Party", h, nil))
+	service.LogInfo("mount", "ctrl", "Event", "action", "Party", "route", "GET /api/events/:partyHash", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewStreamEventContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.Stream(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("GET", "/api/events", ctrl.MuxHandler("Stream", h, nil))
+	service.LogInfo("mount", "ctrl", "Event", "action", "Stream", "route", "GET /api/events", "security", "api_key")
+}
+
+// KeyController is the controller interface for the Key actions.
+type KeyController interface {
+	goa.Muxer
+	Create(*CreateKeyContext) error
+	Delete(*DeleteKeyContext) error
+	List(*ListKeyContext) error
+	Show(*ShowKeyContext) error
+}
+
+// MountKeyController "mounts" a Key resource controller on the given service.
+func MountKeyController(service *goa.Service, ctrl KeyController) {
+	initService(service)
+	var h goa.Handler
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewCreateKeyContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		// Build the payload
+		if rawPayload := goa.ContextRequest(ctx).Payload; rawPayload != nil {
+			rctx.Payload = rawPayload.(*CreateKeyPayload)
+		} else {
+			return goa.MissingPayloadError()
+		}
+		return ctrl.Create(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("POST", "/api/keys", ctrl.MuxHandler("Create", h, unmarshalCreateKeyPayload))
+	service.LogInfo("mount", "ctrl", "Key", "action", "Create", "route", "POST /api/keys", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewDeleteKeyContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.Delete(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("DELETE", "/api/keys/:keyID", ctrl.MuxHandler("Delete", h, nil))
+	service.LogInfo("mount", "ctrl", "Key", "action", "Delete", "route", "DELETE /api/keys/:keyID", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewListKeyContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.List(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("GET", "/api/keys", ctrl.MuxHandler("List", h, nil))
+	service.LogInfo("mount", "ctrl", "Key", "action", "List", "route", "GET /api/keys", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewShowKeyContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.Show(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("GET", "/api/keys/:keyID", ctrl.MuxHandler("Show", h, nil))
+	service.LogInfo("mount", "ctrl", "Key", "action", "Show", "route", "GET /api/keys/:keyID", "security", "api_key")
+}
+
+// unmarshalCreateKeyPayload unmarshals the request body into the context request data Payload field.
+func unmarshalCreateKeyPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	payload := &createKeyPayload{}
+	if err := service.DecodeRequest(req, payload); err != nil {
+		return err
+	}
+	payload.Finalize()
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+}
+
+// NodeController is the controller interface for the Node actions.
+type NodeController interface {
+	goa.Muxer
+	Create(*CreateNodeContext) error
+	Delete(*DeleteNodeContext) error
+	List(*ListNodeContext) error
+	Show(*ShowNodeContext) error
+	Update(*UpdateNodeContext) error
+}
+
+// MountNodeController "mounts" a Node resource controller on the given service.
+func MountNodeController(service *goa.Service, ctrl NodeController) {
+	initService(service)
+	var h goa.Handler
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewCreateNodeContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		// Build the payload
+		if rawPayload := goa.ContextRequest(ctx).Payload; rawPayload != nil {
+			rctx.Payload = rawPayload.(*CreateNodePayload)
+		} else {
+			return goa.MissingPayloadError()
+		}
+		return ctrl.Create(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("POST", "/api/nodes", ctrl.MuxHandler("Create", h, unmarshalCreateNodePayload))
+	service.LogInfo("mount", "ctrl", "Node", "action", "Create", "route", "POST /api/nodes", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewDeleteNodeContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.Delete(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("DELETE", "/api/nodes/:nodeName", ctrl.MuxHandler("Delete", h, nil))
+	service.LogInfo("mount", "ctrl", "Node", "action", "Delete", "route", "DELETE /api/nodes/:nodeName", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewListNodeContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.List(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("GET", "/api/nodes", ctrl.MuxHandler("List", h, nil))
+	service.LogInfo("mount", "ctrl", "Node", "action", "List", "route", "GET /api/nodes", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewShowNodeContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.Show(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("GET", "/api/nodes/:nodeName", ctrl.MuxHandler("Show", h, nil))
+	service.LogInfo("mount", "ctrl", "Node", "action", "Show", "route", "GET /api/nodes/:nodeName", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewUpdateNodeContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		// Build the payload
+		if rawPayload := goa.ContextRequest(ctx).Payload; rawPayload != nil {
+			rctx.Payload = rawPayload.(*UpdateNodePayload)
+		} else {
+			return goa.MissingPayloadError()
+		}
+		return ctrl.Update(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("PATCH", "/api/nodes/:nodeName", ctrl.MuxHandler("Update", h, unmarshalUpdateNodePayload))
+	service.LogInfo("mount", "ctrl", "Node", "action", "Update", "route", "PATCH /api/nodes/:nodeName", "security", "api_key")
+}
+
+// unmarshalCreateNodePayload unmarshals the request body into the context request data Payload field.
+func unmarshalCreateNodePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	payload := &createNodePayload{}
+	if err := service.DecodeRequest(req, payload); err != nil {
+		return err
+	}
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+}
+
+// unmarshalUpdateNodePayload unmarshals the request body into the context request data Payload field.
+func unmarshalUpdateNodePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	payload := &updateNodePayload{}
+	if err := service.DecodeRequest(req, payload); err != nil {
+		return err
+	}
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+}
+
 // PartyController is the controller interface for the Party actions.
 type PartyController interface {
 	goa.Muxer
 	Create(*CreatePartyContext) error
 	Delete(*DeletePartyContext) error
+	Grant(*GrantPartyContext) error
 	List(*ListPartyContext) error
 	Show(*ShowPartyContext) error
 	Update(*UpdatePartyContext) error
@@ -61,8 +374,9 @@ func MountPartyController(service *goa.Service, ctrl PartyController) {
 		}
 		return ctrl.Create(rctx)
 	}
+	h = handleSecurity("api_key", h)
 	service.Mux.Handle("POST", "/api/parties", ctrl.MuxHandler("Create", h, unmarshalCreatePartyPayload))
-	service.LogInfo("mount", "ctrl", "Party", "action", "Create", "route", "POST /api/parties")
+	service.LogInfo("mount", "ctrl", "Party", "action", "Create", "route", "POST /api/parties", "security", "api_key")
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -76,8 +390,31 @@ func MountPartyController(service *goa.Service, ctrl PartyController) {
 		}
 		return ctrl.Delete(rctx)
 	}
+	h = handleSecurity("api_key", h)
 	service.Mux.Handle("DELETE", "/api/parties/:partyHash", ctrl.MuxHandler("Delete", h, nil))
-	service.LogInfo("mount", "ctrl", "Party", "action", "Delete", "route", "DELETE /api/parties/:partyHash")
+	service.LogInfo("mount", "ctrl", "Party", "action", "Delete", "route", "DELETE /api/parties/:partyHash", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewGrantPartyContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		// Build the payload
+		if rawPayload := goa.ContextRequest(ctx).Payload; rawPayload != nil {
+			rctx.Payload = rawPayload.(*GrantPartyPayload)
+		} else {
+			return goa.MissingPayloadError()
+		}
+		return ctrl.Grant(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("PUT", "/api/parties/:partyHash/grants/:keyID", ctrl.MuxHandler("Grant", h, unmarshalGrantPartyPayload))
+	service.LogInfo("mount", "ctrl", "Party", "action", "Grant", "route", "PUT /api/parties/:partyHash/grants/:keyID", "security", "api_key")
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -91,8 +428,9 @@ func MountPartyController(service *goa.Service, ctrl PartyController) {
 		}
 		return ctrl.List(rctx)
 	}
+	h = handleSecurity("api_key", h)
 	service.Mux.Handle("GET", "/api/parties", ctrl.MuxHandler("List", h, nil))
-	service.LogInfo("mount", "ctrl", "Party", "action", "List", "route", "GET /api/parties")
+	service.LogInfo("mount", "ctrl", "Party", "action", "List", "route", "GET /api/parties", "security", "api_key")
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -106,8 +444,9 @@ func MountPartyController(service *goa.Service, ctrl PartyController) {
 		}
 		return ctrl.Show(rctx)
 	}
+	h = handleSecurity("api_key", h)
 	service.Mux.Handle("GET", "/api/parties/:partyHash", ctrl.MuxHandler("Show", h, nil))
-	service.LogInfo("mount", "ctrl", "Party", "action", "Show", "route", "GET /api/parties/:partyHash")
+	service.LogInfo("mount", "ctrl", "Party", "action", "Show", "route", "GET /api/parties/:partyHash", "security", "api_key")
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -127,8 +466,9 @@ func MountPartyController(service *goa.Service, ctrl PartyController) {
 		}
 		return ctrl.Update(rctx)
 	}
+	h = handleSecurity("api_key", h)
 	service.Mux.Handle("PATCH", "/api/parties/:partyHash", ctrl.MuxHandler("Update", h, unmarshalUpdatePartyPayload))
-	service.LogInfo("mount", "ctrl", "Party", "action", "Update", "route", "PATCH /api/parties/:partyHash")
+	service.LogInfo("mount", "ctrl", "Party", "action", "Update", "route", "PATCH /api/parties/:partyHash", "security", "api_key")
 }
 
 // unmarshalCreatePartyPayload unmarshals the request body into the context request data Payload field.
@@ -146,12 +486,32 @@ func unmarshalCreatePartyPayload(ctx context.Context, service *goa.Service, req
 	return nil
 }
 
+// unmarshalGrantPartyPayload unmarshals the request body into the context request data Payload field.
+func unmarshalGrantPartyPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	payload := &grantPartyPayload{}
+	if err := service.DecodeRequest(req, payload); err != nil {
+		return err
+	}
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+}
+
 // unmarshalUpdatePartyPayload unmarshals the request body into the context request data Payload field.
 func unmarshalUpdatePartyPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
 	payload := &partyUpdatePayload{}
 	if err := service.DecodeRequest(req, payload); err != nil {
 		return err
 	}
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
 	goa.ContextRequest(ctx).Payload = payload.Publicize()
 	return nil
 }
@@ -161,7 +521,10 @@ type PinController interface {
 	goa.Muxer
 	Create(*CreatePinContext) error
 	Delete(*DeletePinContext) error
+	History(*HistoryPinContext) error
 	List(*ListPinContext) error
+	Renew(*RenewPinContext) error
+	Reset(*ResetPinContext) error
 	Show(*ShowPinContext) error
 	Update(*UpdatePinContext) error
 }
@@ -189,8 +552,9 @@ func MountPinController(service *goa.Service, ctrl PinController) {
 		}
 		return ctrl.Create(rctx)
 	}
+	h = handleSecurity("api_key", h)
 	service.Mux.Handle("POST", "/api/parties/:partyHash/pins", ctrl.MuxHandler("Create", h, unmarshalCreatePinPayload))
-	service.LogInfo("mount", "ctrl", "Pin", "action", "Create", "route", "POST /api/parties/:partyHash/pins")
+	service.LogInfo("mount", "ctrl", "Pin", "action", "Create", "route", "POST /api/parties/:partyHash/pins", "security", "api_key")
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -204,8 +568,25 @@ func MountPinController(service *goa.Service, ctrl PinController) {
 		}
 		return ctrl.Delete(rctx)
 	}
+	h = handleSecurity("api_key", h)
 	service.Mux.Handle("DELETE", "/api/parties/:partyHash/pins/:pinHash", ctrl.MuxHandler("Delete", h, nil))
-	service.LogInfo("mount", "ctrl", "Pin", "action", "Delete", "route", "DELETE /api/parties/:partyHash/pins/:pinHash")
+	service.LogInfo("mount", "ctrl", "Pin", "action", "Delete", "route", "DELETE /api/parties/:partyHash/pins/:pinHash", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewHistoryPinContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.History(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("GET", "/api/parties/:partyHash/pins/:pinHash/history", ctrl.MuxHandler("History", h, nil))
+	service.LogInfo("mount", "ctrl", "Pin", "action", "History", "route", "GET /api/parties/:partyHash/pins/:pinHash/history", "security", "api_key")
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -219,8 +600,45 @@ func MountPinController(service *goa.Service, ctrl PinController) {
 		}
 		return ctrl.List(rctx)
 	}
+	h = handleSecurity("api_key", h)
 	service.Mux.Handle("GET", "/api/parties/:partyHash/pins", ctrl.MuxHandler("List", h, nil))
-	service.LogInfo("mount", "ctrl", "Pin", "action", "List", "route", "GET /api/parties/:partyHash/pins")
+	service.LogInfo("mount", "ctrl", "Pin", "action", "List", "route", "GET /api/parties/:partyHash/pins", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewRenewPinContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		// Build the payload
+		if rawPayload := goa.ContextRequest(ctx).Payload; rawPayload != nil {
+			rctx.Payload = rawPayload.(*PinRenewPayload)
+		}
+		return ctrl.Renew(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("POST", "/api/parties/:partyHash/pins/:pinHash/renew", ctrl.MuxHandler("Renew", h, unmarshalRenewPinPayload))
+	service.LogInfo("mount", "ctrl", "Pin", "action", "Renew", "route", "POST /api/parties/:partyHash/pins/:pinHash/renew", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewResetPinContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.Reset(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("POST", "/api/parties/:partyHash/pins/:pinHash/reset", ctrl.MuxHandler("Reset", h, nil))
+	service.LogInfo("mount", "ctrl", "Pin", "action", "Reset", "route", "POST /api/parties/:partyHash/pins/:pinHash/reset", "security", "api_key")
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -234,8 +652,9 @@ func MountPinController(service *goa.Service, ctrl PinController) {
 		}
 		return ctrl.Show(rctx)
 	}
+	h = handleSecurity("api_key", h)
 	service.Mux.Handle("GET", "/api/parties/:partyHash/pins/:pinHash", ctrl.MuxHandler("Show", h, nil))
-	service.LogInfo("mount", "ctrl", "Pin", "action", "Show", "route", "GET /api/parties/:partyHash/pins/:pinHash")
+	service.LogInfo("mount", "ctrl", "Pin", "action", "Show", "route", "GET /api/parties/:partyHash/pins/:pinHash", "security", "api_key")
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -255,8 +674,9 @@ func MountPinController(service *goa.Service, ctrl PinController) {
 		}
 		return ctrl.Update(rctx)
 	}
+	h = handleSecurity("api_key", h)
 	service.Mux.Handle("PATCH", "/api/parties/:partyHash/pins/:pinHash", ctrl.MuxHandler("Update", h, unmarshalUpdatePinPayload))
-	service.LogInfo("mount", "ctrl", "Pin", "action", "Update", "route", "PATCH /api/parties/:partyHash/pins/:pinHash")
+	service.LogInfo("mount", "ctrl", "Pin", "action", "Update", "route", "PATCH /api/parties/:partyHash/pins/:pinHash", "security", "api_key")
 }
 
 // unmarshalCreatePinPayload unmarshals the request body into the context request data Payload field.
@@ -265,6 +685,7 @@ func unmarshalCreatePinPayload(ctx context.Context, service *goa.Service, req *h
 	if err := service.DecodeRequest(req, payload); err != nil {
 		return err
 	}
+	payload.Finalize()
 	if err := payload.Validate(); err != nil {
 		// Initialize payload with private data structure so it can be logged
 		goa.ContextRequest(ctx).Payload = payload
@@ -274,12 +695,127 @@ func unmarshalCreatePinPayload(ctx context.Context, service *goa.Service, req *h
 	return nil
 }
 
+// unmarshalRenewPinPayload unmarshals the request body into the context request data Payload field.
+func unmarshalRenewPinPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	payload := &pinRenewPayload{}
+	if err := service.DecodeRequest(req, payload); err != nil {
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+}
+
 // unmarshalUpdatePinPayload unmarshals the request body into the context request data Payload field.
 func unmarshalUpdatePinPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
 	payload := &pinUpdatePayload{}
 	if err := service.DecodeRequest(req, payload); err != nil {
 		return err
 	}
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+}
+
+// WebhookController is the controller interface for the Webhook actions.
+type WebhookController interface {
+	goa.Muxer
+	Create(*CreateWebhookContext) error
+	Delete(*DeleteWebhookContext) error
+	List(*ListWebhookContext) error
+	Show(*ShowWebhookContext) error
+}
+
+// MountWebhookController "mounts" a Webhook resource controller on the given service.
+func MountWebhookController(service *goa.Service, ctrl WebhookController) {
+	initService(service)
+	var h goa.Handler
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewCreateWebhookContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		// Build the payload
+		if rawPayload := goa.ContextRequest(ctx).Payload; rawPayload != nil {
+			rctx.Payload = rawPayload.(*CreateWebhookPayload)
+		} else {
+			return goa.MissingPayloadError()
+		}
+		return ctrl.Create(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("POST", "/api/parties/:partyHash/webhooks", ctrl.MuxHandler("Create", h, unmarshalCreateWebhookPayload))
+	service.LogInfo("mount", "ctrl", "Webhook", "action", "Create", "route", "POST /api/parties/:partyHash/webhooks", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewDeleteWebhookContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.Delete(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("DELETE", "/api/parties/:partyHash/webhooks/:webhookID", ctrl.MuxHandler("Delete", h, nil))
+	service.LogInfo("mount", "ctrl", "Webhook", "action", "Delete", "route", "DELETE /api/parties/:partyHash/webhooks/:webhookID", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewListWebhookContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.List(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("GET", "/api/parties/:partyHash/webhooks", ctrl.MuxHandler("List", h, nil))
+	service.LogInfo("mount", "ctrl", "Webhook", "action", "List", "route", "GET /api/parties/:partyHash/webhooks", "security", "api_key")
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewShowWebhookContext(ctx, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.Show(rctx)
+	}
+	h = handleSecurity("api_key", h)
+	service.Mux.Handle("GET", "/api/parties/:partyHash/webhooks/:webhookID", ctrl.MuxHandler("Show", h, nil))
+	service.LogInfo("mount", "ctrl", "Webhook", "action", "Show", "route", "GET /api/parties/:partyHash/webhooks/:webhookID", "security", "api_key")
+}
+
+// unmarshalCreateWebhookPayload unmarshals the request body into the context request data Payload field.
+func unmarshalCreateWebhookPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	payload := &createWebhookPayload{}
+	if err := service.DecodeRequest(req, payload); err != nil {
+		return err
+	}
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
 	goa.ContextRequest(ctx).Payload = payload.Publicize()
 	return nil
 }
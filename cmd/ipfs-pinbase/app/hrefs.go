@@ -15,6 +15,18 @@ import (
 	"strings"
 )
 
+// KeyHref returns the resource href.
+func KeyHref(keyID interface{}) string {
+	paramkeyID := strings.TrimLeftFunc(fmt.Sprintf("%v", keyID), func(r rune) bool { return r == '/' })
+	return fmt.Sprintf("/api/keys/%v", paramkeyID)
+}
+
+// NodeHref returns the resource href.
+func NodeHref(nodeName interface{}) string {
+	paramnodeName := strings.TrimLeftFunc(fmt.Sprintf("%v", nodeName), func(r rune) bool { return r == '/' })
+	return fmt.Sprintf("/api/nodes/%v", paramnodeName)
+}
+
 // PartyHref returns the resource href.
 func PartyHref(partyHash interface{}) string {
 	parampartyHash := strings.TrimLeftFunc(fmt.Sprintf("%v", partyHash), func(r rune) bool { return r == '/' })
@@ -27,3 +39,10 @@ func PinHref(partyHash, pinHash interface{}) string {
 	parampinHash := strings.TrimLeftFunc(fmt.Sprintf("%v", pinHash), func(r rune) bool { return r == '/' })
 	return fmt.Sprintf("/api/parties/%v/pins/%v", parampartyHash, parampinHash)
 }
+
+// WebhookHref returns the resource href.
+func WebhookHref(partyHash, webhookID interface{}) string {
+	parampartyHash := strings.TrimLeftFunc(fmt.Sprintf("%v", partyHash), func(r rune) bool { return r == '/' })
+	paramwebhookID := strings.TrimLeftFunc(fmt.Sprintf("%v", webhookID), func(r rune) bool { return r == '/' })
+	return fmt.Sprintf("/api/parties/%v/webhooks/%v", parampartyHash, paramwebhookID)
+}
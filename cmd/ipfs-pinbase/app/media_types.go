@@ -10,7 +10,188 @@
 
 package app
 
-import "github.com/goadesign/goa"
+import (
+	"github.com/goadesign/goa"
+	"time"
+)
+
+// An archived Pin (default view)
+//
+// Identifier: application/vnd.pinbase.archived-pin+json; view=default
+type PinbaseArchivedPin struct {
+	// The hash of the object to be pinned, which is kept as a CIDv1 in base32, an IPFS path such as /ipfs/<hash>/sub/path to pin the object it points at, or the IPNS name or DNSLink domain of a name pin
+	Hash string `form:"hash" json:"hash" xml:"hash"`
+	// Last unpin error message
+	LastError string `form:"last-error" json:"last-error" xml:"last-error"`
+	// The status of the unpinning
+	Status string `form:"status" json:"status" xml:"status"`
+}
+
+// Validate validates the PinbaseArchivedPin media type instance.
+func (mt *PinbaseArchivedPin) Validate() (err error) {
+	if mt.Hash == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "hash"))
+	}
+	if mt.Status == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "status"))
+	}
+	if mt.LastError == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "last-error"))
+	}
+	return
+}
+
+// PinbaseArchived-PinCollection is the media type for an array of PinbaseArchived-Pin (default view)
+//
+// Identifier: application/vnd.pinbase.archived-pin+json; type=collection; view=default
+type PinbaseArchivedPinCollection []*PinbaseArchivedPin
+
+// Validate validates the PinbaseArchivedPinCollection media type instance.
+func (mt PinbaseArchivedPinCollection) Validate() (err error) {
+	for _, e := range mt {
+		if e != nil {
+			if err2 := e.Validate(); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}
+	return
+}
+
+// An API key (default view)
+//
+// Identifier: application/vnd.pinbase.key+json; view=default
+type PinbaseKey struct {
+	// Admin keys may do anything, others only what they are granted on each party
+	Admin bool `form:"admin" json:"admin" xml:"admin"`
+	// When the key was created
+	Created time.Time `form:"created" json:"created" xml:"created"`
+	// What or who the key is for
+	Description string `form:"description" json:"description" xml:"description"`
+	// The public part of the key that identifies it
+	ID string `form:"id" json:"id" xml:"id"`
+}
+
+// Validate validates the PinbaseKey media type instance.
+func (mt *PinbaseKey) Validate() (err error) {
+	if mt.ID == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "id"))
+	}
+	if mt.Description == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "description"))
+	}
+
+	return
+}
+
+// An API key (secret view)
+//
+// Identifier: application/vnd.pinbase.key+json; view=secret
+type PinbaseKeySecret struct {
+	// Admin keys may do anything, others only what they are granted on each party
+	Admin bool `form:"admin" json:"admin" xml:"admin"`
+	// When the key was created
+	Created time.Time `form:"created" json:"created" xml:"created"`
+	// What or who the key is for
+	Description string `form:"description" json:"description" xml:"description"`
+	// The public part of the key that identifies it
+	ID string `form:"id" json:"id" xml:"id"`
+	// The key to send in the X-Pinbase-Key header
+	Key *string `form:"key,omitempty" json:"key,omitempty" xml:"key,omitempty"`
+}
+
+// Validate validates the PinbaseKeySecret media type instance.
+func (mt *PinbaseKeySecret) Validate() (err error) {
+	if mt.ID == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "id"))
+	}
+	if mt.Description == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "description"))
+	}
+
+	return
+}
+
+// PinbaseKeyCollection is the media type for an array of PinbaseKey (default view)
+//
+// Identifier: application/vnd.pinbase.key+json; type=collection; view=default
+type PinbaseKeyCollection []*PinbaseKey
+
+// Validate validates the PinbaseKeyCollection media type instance.
+func (mt PinbaseKeyCollection) Validate() (err error) {
+	for _, e := range mt {
+		if e != nil {
+			if err2 := e.Validate(); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}
+	return
+}
+
+// PinbaseKeyCollection is the media type for an array of PinbaseKey (secret view)
+//
+// Identifier: application/vnd.pinbase.key+json; type=collection; view=secret
+type PinbaseKeySecretCollection []*PinbaseKeySecret
+
+// Validate validates the PinbaseKeySecretCollection media type instance.
+func (mt PinbaseKeySecretCollection) Validate() (err error) {
+	for _, e := range mt {
+		if e != nil {
+			if err2 := e.Validate(); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}
+	return
+}
+
+// An IPFS node pins are spread over (default view)
+//
+// Identifier: application/vnd.pinbase.node+json; view=default
+type PinbaseNode struct {
+	// The host:port of the node's IPFS API
+	APIAddress string `form:"api-address" json:"api-address" xml:"api-address"`
+	// When the node last answered a check, if ever
+	LastSeen *time.Time `form:"last-seen,omitempty" json:"last-seen,omitempty" xml:"last-seen,omitempty"`
+	// The name pins refer to the node by
+	Name string `form:"name" json:"name" xml:"name"`
+	// Number of pins on the node as of the last answered check
+	PinCount int `form:"pin-count" json:"pin-count" xml:"pin-count"`
+	// Whether the node answered the last check
+	Reachable bool `form:"reachable" json:"reachable" xml:"reachable"`
+	// Bytes used by the node's repo as of the last answered check
+	RepoSize int `form:"repo-size" json:"repo-size" xml:"repo-size"`
+}
+
+// Validate validates the PinbaseNode media type instance.
+func (mt *PinbaseNode) Validate() (err error) {
+	if mt.Name == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "name"))
+	}
+	if mt.APIAddress == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "api-address"))
+	}
+
+	return
+}
+
+// PinbaseNodeCollection is the media type for an array of PinbaseNode (default view)
+//
+// Identifier: application/vnd.pinbase.node+json; type=collection; view=default
+type PinbaseNodeCollection []*PinbaseNode
+
+// Validate validates the PinbaseNodeCollection media type instance.
+func (mt PinbaseNodeCollection) Validate() (err error) {
+	for _, e := range mt {
+		if e != nil {
+			if err2 := e.Validate(); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}
+	return
+}
 
 // A Pinbase Party (default view)
 //
@@ -20,6 +201,20 @@ type PinbaseParty struct {
 	Description string `form:"description" json:"description" xml:"description"`
 	// The hash of the object describing the party
 	Hash string `form:"hash" json:"hash" xml:"hash"`
+	// Most bytes the party's wanted pins may add up to, 0 for no limit
+	MaxBytes int `form:"max-bytes" json:"max-bytes" xml:"max-bytes"`
+	// Most pins the party may want pinned at once, 0 for no limit
+	MaxPins int `form:"max-pins" json:"max-pins" xml:"max-pins"`
+	// Bytes the party's confirmed pins add up to, as far as they are known
+	PinnedBytes int `form:"pinned-bytes" json:"pinned-bytes" xml:"pinned-bytes"`
+	// Number of the party's pins the nodes confirmed as pinned
+	PinnedPins int `form:"pinned-pins" json:"pinned-pins" xml:"pinned-pins"`
+	// Base64 ed25519 public key the party is bound to, requests to its pins must then be signed with the matching private key
+	PublicKey *string `form:"public-key,omitempty" json:"public-key,omitempty" xml:"public-key,omitempty"`
+	// Bytes the party's wanted pins add up to, as far as they are known
+	UsedBytes int `form:"used-bytes" json:"used-bytes" xml:"used-bytes"`
+	// Number of pins the party wants pinned
+	UsedPins int `form:"used-pins" json:"used-pins" xml:"used-pins"`
 }
 
 // Validate validates the PinbaseParty media type instance.
@@ -30,6 +225,13 @@ func (mt *PinbaseParty) Validate() (err error) {
 	if mt.Description == "" {
 		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "description"))
 	}
+
+	if mt.MaxBytes < 0 {
+		err = goa.MergeErrors(err, goa.InvalidRangeError(`response.max-bytes`, mt.MaxBytes, 0, true))
+	}
+	if mt.MaxPins < 0 {
+		err = goa.MergeErrors(err, goa.InvalidRangeError(`response.max-pins`, mt.MaxPins, 0, true))
+	}
 	return
 }
 
@@ -56,12 +258,42 @@ func (mt PinbasePartyCollection) Validate() (err error) {
 type PinbasePin struct {
 	// Aliases for the pinned object
 	Aliases []string `form:"aliases" json:"aliases" xml:"aliases"`
-	// The hash of the object to be pinned
+	// Number of blocks fetched by the latest pinning
+	BlocksFetched int `form:"blocks-fetched" json:"blocks-fetched" xml:"blocks-fetched"`
+	// Number of bytes fetched by the latest pinning, if known
+	BytesFetched int `form:"bytes-fetched" json:"bytes-fetched" xml:"bytes-fetched"`
+	// Whether the pin stopped being wanted because its expiry passed
+	Expired bool `form:"expired" json:"expired" xml:"expired"`
+	// When the pin stops being wanted, never if left out
+	ExpiresAt *time.Time `form:"expires-at,omitempty" json:"expires-at,omitempty" xml:"expires-at,omitempty"`
+	// The hash of the object to be pinned, which is kept as a CIDv1 in base32, an IPFS path such as /ipfs/<hash>/sub/path to pin the object it points at, or the IPNS name or DNSLink domain of a name pin
 	Hash string `form:"hash" json:"hash" xml:"hash"`
+	// Whether the pin's window is open, the pin is left off the nodes otherwise
+	InWindow bool `form:"in-window" json:"in-window" xml:"in-window"`
+	// What the hash is: the hash to pin, or an IPNS name or DNSLink domain whose target gets pinned; hash if left out
+	Kind string `form:"kind" json:"kind" xml:"kind"`
 	// Last pin error message
 	LastError string `form:"last-error" json:"last-error" xml:"last-error"`
+	// Pin everything the object links to (recursive) or just its root block (direct)
+	Mode string `form:"mode" json:"mode" xml:"mode"`
+	// The nodes holding the pin or failing to
+	Nodes []*PinNode `form:"nodes" json:"nodes" xml:"nodes"`
+	// When the pin's window closes, it stays open if left out
+	NotAfter *time.Time `form:"not-after,omitempty" json:"not-after,omitempty" xml:"not-after,omitempty"`
+	// When the pin's window opens, it is wanted from the start if left out
+	NotBefore *time.Time `form:"not-before,omitempty" json:"not-before,omitempty" xml:"not-before,omitempty"`
+	// The IPFS path the hash was resolved from when the pin was created, empty for pins of a plain hash
+	Path *string `form:"path,omitempty" json:"path,omitempty" xml:"path,omitempty"`
+	// Number of IPFS nodes the object should be pinned on
+	Replication int `form:"replication" json:"replication" xml:"replication"`
+	// The latest outcomes of resolving the name of a name pin, oldest first
+	Resolutions []*PinResolution `form:"resolutions,omitempty" json:"resolutions,omitempty" xml:"resolutions,omitempty"`
+	// Cumulative size of the pinned object in bytes, or of its root block for direct pins, 0 until known
+	Size int `form:"size" json:"size" xml:"size"`
 	// The status of the pin
 	Status string `form:"status" json:"status" xml:"status"`
+	// The hash the name of a name pin last resolved to, empty until it first resolves
+	Target *string `form:"target,omitempty" json:"target,omitempty" xml:"target,omitempty"`
 	// Indicates that the party wants to actually pin the object
 	WantPinned bool `form:"want-pinned" json:"want-pinned" xml:"want-pinned"`
 }
@@ -75,12 +307,140 @@ func (mt *PinbasePin) Validate() (err error) {
 		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "aliases"))
 	}
 
+	if mt.Mode == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "mode"))
+	}
+
 	if mt.Status == "" {
 		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "status"))
 	}
 	if mt.LastError == "" {
 		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "last-error"))
 	}
+
+	if mt.Nodes == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "nodes"))
+	}
+
+	if mt.Kind == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "kind"))
+	}
+	if !(mt.Kind == "hash" || mt.Kind == "name") {
+		err = goa.MergeErrors(err, goa.InvalidEnumValueError(`response.kind`, mt.Kind, []interface{}{"hash", "name"}))
+	}
+	if !(mt.Mode == "recursive" || mt.Mode == "direct") {
+		err = goa.MergeErrors(err, goa.InvalidEnumValueError(`response.mode`, mt.Mode, []interface{}{"recursive", "direct"}))
+	}
+	for _, e := range mt.Nodes {
+		if e != nil {
+			if err2 := e.Validate(); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}
+	if mt.Replication < 1 {
+		err = goa.MergeErrors(err, goa.InvalidRangeError(`response.replication`, mt.Replication, 1, true))
+	}
+	for _, e := range mt.Resolutions {
+		if e != nil {
+			if err2 := e.Validate(); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}
+	return
+}
+
+// A change of a pin's status, sent as the data of an event stream's pin events (default view)
+//
+// Identifier: application/vnd.pinbase.pin-event+json; view=default
+type PinbasePinEvent struct {
+	// The hash of the object to be pinned, which is kept as a CIDv1 in base32, an IPFS path such as /ipfs/<hash>/sub/path to pin the object it points at, or the IPNS name or DNSLink domain of a name pin
+	Hash string `form:"hash" json:"hash" xml:"hash"`
+	// Last pin error message
+	LastError string `form:"last-error" json:"last-error" xml:"last-error"`
+	// The hash of the party the pin belongs to
+	Party string `form:"party" json:"party" xml:"party"`
+	// The new status of the pin
+	Status string `form:"status" json:"status" xml:"status"`
+	// When the status changed
+	Time time.Time `form:"time" json:"time" xml:"time"`
+}
+
+// Validate validates the PinbasePinEvent media type instance.
+func (mt *PinbasePinEvent) Validate() (err error) {
+	if mt.Party == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "party"))
+	}
+	if mt.Hash == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "hash"))
+	}
+	if mt.Status == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "status"))
+	}
+	if mt.LastError == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "last-error"))
+	}
+
+	return
+}
+
+// A change of a pin (default view)
+//
+// Identifier: application/vnd.pinbase.pin-history+json; view=default
+type PinbasePinHistory struct {
+	// The ID of the API key that made the change, empty for status changes
+	By string `form:"by" json:"by" xml:"by"`
+	// What happened to the pin
+	Change string `form:"change" json:"change" xml:"change"`
+	// What the change set or changed, as in "want-pinned: true -> false"
+	Changes []string `form:"changes" json:"changes" xml:"changes"`
+	// The error of the pin, if the change left it with one
+	LastError string `form:"last-error" json:"last-error" xml:"last-error"`
+	// The status the pin was left with
+	Status string `form:"status" json:"status" xml:"status"`
+	// When the change happened
+	Time time.Time `form:"time" json:"time" xml:"time"`
+}
+
+// Validate validates the PinbasePinHistory media type instance.
+func (mt *PinbasePinHistory) Validate() (err error) {
+
+	if mt.Change == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "change"))
+	}
+	if mt.By == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "by"))
+	}
+	if mt.Status == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "status"))
+	}
+	if mt.LastError == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "last-error"))
+	}
+	if mt.Changes == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "changes"))
+	}
+	if !(mt.Change == "status" || mt.Change == "created" || mt.Change == "updated" || mt.Change == "reset" || mt.Change == "deleted" || mt.Change == "expired" || mt.Change == "renewed" || mt.Change == "resolved") {
+		err = goa.MergeErrors(err, goa.InvalidEnumValueError(`response.change`, mt.Change, []interface{}{"status", "created", "updated", "reset", "deleted", "expired", "renewed", "resolved"}))
+	}
+	return
+}
+
+// PinbasePin-HistoryCollection is the media type for an array of PinbasePin-History (default view)
+//
+// Identifier: application/vnd.pinbase.pin-history+json; type=collection; view=default
+type PinbasePinHistoryCollection []*PinbasePinHistory
+
+// Validate validates the PinbasePinHistoryCollection media type instance.
+func (mt PinbasePinHistoryCollection) Validate() (err error) {
+	for _, e := range mt {
+		if e != nil {
+			if err2 := e.Validate(); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}
 	return
 }
 
@@ -100,3 +460,93 @@ func (mt PinbasePinCollection) Validate() (err error) {
 	}
 	return
 }
+
+// A webhook of a party (default view)
+//
+// Identifier: application/vnd.pinbase.webhook+json; view=default
+type PinbaseWebhook struct {
+	// When the webhook was registered
+	Created time.Time `form:"created" json:"created" xml:"created"`
+	// The ID of the webhook
+	ID string `form:"id" json:"id" xml:"id"`
+	// The http or https URL pin status changes are posted to
+	URL string `form:"url" json:"url" xml:"url"`
+}
+
+// Validate validates the PinbaseWebhook media type instance.
+func (mt *PinbaseWebhook) Validate() (err error) {
+	if mt.ID == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "id"))
+	}
+	if mt.URL == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "url"))
+	}
+
+	if err2 := goa.ValidateFormat(goa.FormatURI, mt.URL); err2 != nil {
+		err = goa.MergeErrors(err, goa.InvalidFormatError(`response.url`, mt.URL, goa.FormatURI, err2))
+	}
+	return
+}
+
+// A webhook of a party (secret view)
+//
+// Identifier: application/vnd.pinbase.webhook+json; view=secret
+type PinbaseWebhookSecret struct {
+	// When the webhook was registered
+	Created time.Time `form:"created" json:"created" xml:"created"`
+	// The ID of the webhook
+	ID string `form:"id" json:"id" xml:"id"`
+	// The key of the HMAC-SHA256 in the X-Pinbase-Webhook-Signature header of each post
+	Secret *string `form:"secret,omitempty" json:"secret,omitempty" xml:"secret,omitempty"`
+	// The http or https URL pin status changes are posted to
+	URL string `form:"url" json:"url" xml:"url"`
+}
+
+// Validate validates the PinbaseWebhookSecret media type instance.
+func (mt *PinbaseWebhookSecret) Validate() (err error) {
+	if mt.ID == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "id"))
+	}
+	if mt.URL == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "url"))
+	}
+
+	if err2 := goa.ValidateFormat(goa.FormatURI, mt.URL); err2 != nil {
+		err = goa.MergeErrors(err, goa.InvalidFormatError(`response.url`, mt.URL, goa.FormatURI, err2))
+	}
+	return
+}
+
+// PinbaseWebhookCollection is the media type for an array of PinbaseWebhook (default view)
+//
+// Identifier: application/vnd.pinbase.webhook+json; type=collection; view=default
+type PinbaseWebhookCollection []*PinbaseWebhook
+
+// Validate validates the PinbaseWebhookCollection media type instance.
+func (mt PinbaseWebhookCollection) Validate() (err error) {
+	for _, e := range mt {
+		if e != nil {
+			if err2 := e.Validate(); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}
+	return
+}
+
+// PinbaseWebhookCollection is the media type for an array of PinbaseWebhook (secret view)
+//
+// Identifier: application/vnd.pinbase.webhook+json; type=collection; view=secret
+type PinbaseWebhookSecretCollection []*PinbaseWebhookSecret
+
+// Validate validates the PinbaseWebhookSecretCollection media type instance.
+func (mt PinbaseWebhookSecretCollection) Validate() (err error) {
+	for _, e := range mt {
+		if e != nil {
+			if err2 := e.Validate(); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,50 @@
+// Code generated by goagen v1.1.0-dirty, command line:
+// $ goagen
+// --design=github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/design
+// --out=$(GOPATH)/src/github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase
+// --version=v1.1.0-dirty
+//
+// API "pinbase": Application Security
+//
+// The content of this file is auto-generated, DO NOT MODIFY
+
+package app
+
+import (
+	"github.com/goadesign/goa"
+	"golang.org/x/net/context"
+	"net/http"
+)
+
+type (
+	// Private type used to store auth handler info in request context
+	authMiddlewareKey string
+)
+
+// UseAPIKeyMiddleware mounts the api_key auth middleware onto the service.
+func UseAPIKeyMiddleware(service *goa.Service, middleware goa.Middleware) {
+	service.Context = context.WithValue(service.Context, authMiddlewareKey("api_key"), middleware)
+}
+
+// NewAPIKeySecurity creates a api_key security definition.
+func NewAPIKeySecurity() *goa.APIKeySecurity {
+	def := goa.APIKeySecurity{
+		In:   goa.LocHeader,
+		Name: "X-Pinbase-Key",
+	}
+	def.Description = "A key handed out by the key resource, sent with every request"
+	return &def
+}
+
+// handleSecurity creates a handler that runs the auth middleware for the security scheme.
+func handleSecurity(schemeName string, h goa.Handler, scopes ...string) goa.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		scheme := ctx.Value(authMiddlewareKey(schemeName))
+		am, ok := scheme.(goa.Middleware)
+		if !ok {
+			return goa.NoAuthMiddleware(schemeName)
+		}
+		ctx = goa.WithRequiredScopes(ctx, scopes)
+		return am(h)(ctx, rw, req)
+	}
+}
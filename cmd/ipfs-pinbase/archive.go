@@ -0,0 +1,49 @@
+package main
+
+import (
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/app"
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/goadesign/goa"
+)
+
+// ArchiveController implements the archive resource.
+type ArchiveController struct {
+	*goa.Controller
+	P pinbase.PinProvider
+}
+
+// NewArchiveController creates a archive controller.
+func NewArchiveController(service *goa.Service, P pinbase.PinProvider) *ArchiveController {
+	return &ArchiveController{Controller: service.NewController("ArchiveController"), P: P}
+}
+
+// List runs the list action.
+func (c *ArchiveController) List(ctx *app.ListArchiveContext) error {
+	// ArchiveController_List: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	as, err := c.P.PinService().ArchivedPins()
+	if err != nil {
+		return err
+	}
+
+	res := app.PinbaseArchivedPinCollection{}
+	for _, a := range as {
+		var e string
+		if a.LastError != nil {
+			e = a.LastError.Error()
+		}
+
+		res = append(res, &app.PinbaseArchivedPin{
+			Hash:      string(a.ID),
+			Status:    a.Status.String(),
+			LastError: e,
+		})
+	}
+
+	// ArchiveController_List: end_implement
+	return ctx.OK(res)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/app"
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/goadesign/goa"
+	"golang.org/x/net/context"
+)
+
+// ErrForbidden is sent back for requests the API key does not have the role
+// for.
+var ErrForbidden = goa.NewErrorClass("forbidden", 403)
+
+func forbidden(need pinbase.Role) error {
+	return ErrForbidden(fmt.Sprintf("the API key needs the %s role", need))
+}
+
+type keyContextKey struct{}
+
+// NewAPIKeyMiddleware only lets through requests carrying one of the keys of
+// the key service, which is then available through ContextKey.
+func NewAPIKeyMiddleware(K pinbase.KeyProvider) goa.Middleware {
+	scheme := app.NewAPIKeySecurity()
+
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			key := req.Header.Get(scheme.Name)
+			if key == "" {
+				return goa.ErrUnauthorized("missing API key")
+			}
+
+			k, err := K.KeyService().Authenticate(key)
+			if err != nil {
+				return err
+			}
+			if k == nil {
+				return goa.ErrUnauthorized("invalid API key")
+			}
+
+			return h(context.WithValue(ctx, keyContextKey{}, k), rw, req)
+		}
+	}
+}
+
+// ContextKey returns the API key the request was made with.
+func ContextKey(ctx context.Context) *pinbase.KeyView {
+	k, _ := ctx.Value(keyContextKey{}).(*pinbase.KeyView)
+	return k
+}
+
+// keyID returns the ID of the API key the request was made with, for the
+// records of who did what.
+func keyID(ctx context.Context) string {
+	if k := ContextKey(ctx); k != nil {
+		return k.ID
+	}
+	return ""
+}
+
+func isAdmin(ctx context.Context) bool {
+	k := ContextKey(ctx)
+	return k != nil && k.Admin
+}
+
+// partyRole returns the role the request's API key has on the party. Admin
+// keys are admins of every party, even the ones that do not exist yet.
+func partyRole(ctx context.Context, ps pinbase.PinService, partyID pinbase.Hash) (pinbase.Role, error) {
+	k := ContextKey(ctx)
+	switch {
+	case k == nil:
+		return pinbase.RoleNone, nil
+	case k.Admin:
+		return pinbase.RoleAdmin, nil
+	}
+
+	p, err := ps.Party(partyID)
+	if err != nil || p == nil {
+		return pinbase.RoleNone, err
+	}
+
+	return p.Grants[k.ID], nil
+}
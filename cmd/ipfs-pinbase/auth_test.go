@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/app"
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/apiarian/ipfs-pinbase/pinbase/bolt"
+	"github.com/goadesign/goa"
+)
+
+func showParty(t *testing.T, ctx context.Context, service *goa.Service, c *PartyController, partyHash string) int {
+	rw := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", app.PartyHref(partyHash), nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %+v", err)
+	}
+	prms := url.Values{"partyHash": []string{partyHash}}
+
+	goaCtx := goa.NewContext(goa.WithAction(ctx, "PartyTest"), rw, req, prms)
+	showCtx, err := app.NewShowPartyContext(goaCtx, service)
+	if err != nil {
+		t.Fatalf("failed to create context: %+v", err)
+	}
+
+	err = c.Show(showCtx)
+	if err != nil {
+		t.Fatalf("show failed: %+v", err)
+	}
+
+	return rw.Code
+}
+
+func TestPartyRoles(t *testing.T) {
+	f, err := ioutil.TempFile("", "pinbase-auth-")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %+v", err)
+	}
+	f.Close()
+	os.Remove(f.Name())
+	defer os.Remove(f.Name())
+
+	P := bolt.NewClient(f.Name())
+	err = P.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+	defer P.Close()
+
+	ps := P.PinService()
+	for _, h := range []pinbase.Hash{"foo", "bar"} {
+		err = ps.CreateParty(&pinbase.PartyCreate{ID: h})
+		if err != nil {
+			t.Fatalf("failed to create party %s: %+v", h, err)
+		}
+	}
+
+	ks := P.KeyService()
+	newKey := func(admin bool) context.Context {
+		k, _, err := ks.CreateKey(&pinbase.KeyCreate{Admin: admin})
+		if err != nil {
+			t.Fatalf("failed to create key: %+v", err)
+		}
+		return context.WithValue(context.Background(), keyContextKey{}, k)
+	}
+	admin := newKey(true)
+	alice := newKey(false)
+	bob := newKey(false)
+
+	err = ps.GrantParty("foo", ContextKey(alice).ID, pinbase.RolePartyOwner)
+	if err != nil {
+		t.Fatalf("failed to grant alice: %+v", err)
+	}
+	err = ps.GrantParty("foo", ContextKey(bob).ID, pinbase.RoleReadOnly)
+	if err != nil {
+		t.Fatalf("failed to grant bob: %+v", err)
+	}
+
+	for _, tc := range []struct {
+		who      string
+		ctx      context.Context
+		party    pinbase.Hash
+		expected pinbase.Role
+	}{
+		{"admin", admin, "foo", pinbase.RoleAdmin},
+		{"admin", admin, "baz", pinbase.RoleAdmin},
+		{"alice", alice, "foo", pinbase.RolePartyOwner},
+		{"alice", alice, "bar", pinbase.RoleNone},
+		{"bob", bob, "foo", pinbase.RoleReadOnly},
+		{"bob", bob, "baz", pinbase.RoleNone},
+		{"nobody", context.Background(), "foo", pinbase.RoleNone},
+	} {
+		r, err := partyRole(tc.ctx, ps, tc.party)
+		if err != nil {
+			t.Errorf("%s on %s: %+v", tc.who, tc.party, err)
+		}
+		if r != tc.expected {
+			t.Errorf("%s on %s: got role %s, expected %s", tc.who, tc.party, r, tc.expected)
+		}
+	}
+
+	service := goa.New("test")
+	service.Encoder.Register(goa.NewJSONEncoder, "*/*")
+	c := NewPartyController(service, P)
+
+	for _, tc := range []struct {
+		who      string
+		ctx      context.Context
+		party    string
+		expected int
+	}{
+		{"admin", admin, "bar", http.StatusOK},
+		{"admin", admin, "baz", http.StatusNotFound},
+		{"bob", bob, "foo", http.StatusOK},
+		{"bob", bob, "bar", http.StatusForbidden},
+		{"bob", bob, "baz", http.StatusForbidden},
+	} {
+		code := showParty(t, tc.ctx, service, c, tc.party)
+		if code != tc.expected {
+			t.Errorf("%s showing %s: got status %d, expected %d", tc.who, tc.party, code, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Code generated by goagen v1.1.0-dirty, command line:
+// $ goagen
+// --design=github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/design
+// --out=$(GOPATH)/src/github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase
+// --version=v1.1.0-dirty
+//
+// API "pinbase": archive Resource Client
+//
+// The content of this file is auto-generated, DO NOT MODIFY
+
+package client
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"net/http"
+	"net/url"
+)
+
+// ListArchivePath computes a request path to the list action of archive.
+func ListArchivePath() string {
+
+	return fmt.Sprintf("/api/archive")
+}
+
+// List the archived hashes and how their unpinning is going
+func (c *Client) ListArchive(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewListArchiveRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewListArchiveRequest create the request corresponding to the list action endpoint of the archive resource.
+func (c *Client) NewListArchiveRequest(ctx context.Context, path string) (*http.Request, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
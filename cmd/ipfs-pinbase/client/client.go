@@ -18,8 +18,9 @@ import (
 // Client is the pinbase service client.
 type Client struct {
 	*goaclient.Client
-	Encoder *goa.HTTPEncoder
-	Decoder *goa.HTTPDecoder
+	APIKeySigner goaclient.Signer
+	Encoder      *goa.HTTPEncoder
+	Decoder      *goa.HTTPDecoder
 }
 
 // New instantiates the client.
@@ -40,3 +41,8 @@ func New(c goaclient.Doer) *Client {
 
 	return client
 }
+
+// SetAPIKeySigner sets the request signer for the api_key security scheme.
+func (c *Client) SetAPIKeySigner(signer goaclient.Signer) {
+	c.APIKeySigner = signer
+}
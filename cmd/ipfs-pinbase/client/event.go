@@ -0,0 +1,83 @@
+// Code generated by goagen v1.1.0-dirty, command line:
+// $ goagen
+// --design=github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/design
+// --out=$(GOPATH)/src/github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase
+// --version=v1.1.0-dirty
+//
+// API "pinbase": event Resource Client
+//
+// The content of this file is auto-generated, DO NOT MODIFY
+
+package client
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"net/http"
+	"net/url"
+)
+
+// PartyEventPath computes a request path to the party action of event.
+func PartyEventPath(partyHash string) string {
+	param0 := partyHash
+
+	return fmt.Sprintf("/api/events/%s", param0)
+}
+
+// Stream the pin status changes of a party
+func (c *Client) PartyEvent(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewPartyEventRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewPartyEventRequest create the request corresponding to the party action endpoint of the event resource.
+func (c *Client) NewPartyEventRequest(ctx context.Context, path string) (*http.Request, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
+
+// StreamEventPath computes a request path to the stream action of event.
+func StreamEventPath() string {
+
+	return fmt.Sprintf("/api/events")
+}
+
+// Stream the pin status changes of every party, for admin keys
+func (c *Client) StreamEvent(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewStreamEventRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewStreamEventRequest create the request corresponding to the stream action endpoint of the event resource.
+func (c *Client) NewStreamEventRequest(ctx context.Context, path string) (*http.Request, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
@@ -0,0 +1,162 @@
+// Code generated by goagen v1.1.0-dirty, command line:
+// $ goagen
+// --design=github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/design
+// --out=$(GOPATH)/src/github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase
+// --version=v1.1.0-dirty
+//
+// API "pinbase": key Resource Client
+//
+// The content of this file is auto-generated, DO NOT MODIFY
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"golang.org/x/net/context"
+	"net/http"
+	"net/url"
+)
+
+// CreateKeyPayload is the key create action payload.
+type CreateKeyPayload struct {
+	// Admin keys may do anything, others only what they are granted on each party
+	Admin bool `form:"admin" json:"admin" xml:"admin"`
+	// What or who the key is for
+	Description string `form:"description" json:"description" xml:"description"`
+}
+
+// CreateKeyPath computes a request path to the create action of key.
+func CreateKeyPath() string {
+
+	return fmt.Sprintf("/api/keys")
+}
+
+// Create an API key. The key itself is only ever shown in this response
+func (c *Client) CreateKey(ctx context.Context, path string, payload *CreateKeyPayload) (*http.Response, error) {
+	req, err := c.NewCreateKeyRequest(ctx, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewCreateKeyRequest create the request corresponding to the create action endpoint of the key resource.
+func (c *Client) NewCreateKeyRequest(ctx context.Context, path string, payload *CreateKeyPayload) (*http.Request, error) {
+	var body bytes.Buffer
+	err := c.Encoder.Encode(payload, &body, "*/*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode body: %s", err)
+	}
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("POST", u.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
+
+// DeleteKeyPath computes a request path to the delete action of key.
+func DeleteKeyPath(keyID string) string {
+	param0 := keyID
+
+	return fmt.Sprintf("/api/keys/%s", param0)
+}
+
+// Revoke an API key
+func (c *Client) DeleteKey(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewDeleteKeyRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewDeleteKeyRequest create the request corresponding to the delete action endpoint of the key resource.
+func (c *Client) NewDeleteKeyRequest(ctx context.Context, path string) (*http.Request, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
+
+// ListKeyPath computes a request path to the list action of key.
+func ListKeyPath() string {
+
+	return fmt.Sprintf("/api/keys")
+}
+
+// List the API keys
+func (c *Client) ListKey(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewListKeyRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewListKeyRequest create the request corresponding to the list action endpoint of the key resource.
+func (c *Client) NewListKeyRequest(ctx context.Context, path string) (*http.Request, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
+
+// ShowKeyPath computes a request path to the show action of key.
+func ShowKeyPath(keyID string) string {
+	param0 := keyID
+
+	return fmt.Sprintf("/api/keys/%s", param0)
+}
+
+// Get the API key by ID
+func (c *Client) ShowKey(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewShowKeyRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewShowKeyRequest create the request corresponding to the show action endpoint of the key resource.
+func (c *Client) NewShowKeyRequest(ctx context.Context, path string) (*http.Request, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
@@ -24,6 +24,12 @@ type CreatePartyPayload struct {
 	Description string `form:"description" json:"description" xml:"description"`
 	// The hash of the object describing the party
 	Hash string `form:"hash" json:"hash" xml:"hash"`
+	// Most bytes the party's wanted pins may add up to, 0 for no limit
+	MaxBytes *int `form:"max-bytes,omitempty" json:"max-bytes,omitempty" xml:"max-bytes,omitempty"`
+	// Most pins the party may want pinned at once, 0 for no limit
+	MaxPins *int `form:"max-pins,omitempty" json:"max-pins,omitempty" xml:"max-pins,omitempty"`
+	// Base64 ed25519 public key the party is bound to, requests to its pins must then be signed with the matching private key
+	PublicKey *string `form:"public-key,omitempty" json:"public-key,omitempty" xml:"public-key,omitempty"`
 }
 
 // CreatePartyPath computes a request path to the create action of party.
@@ -57,6 +63,9 @@ func (c *Client) NewCreatePartyRequest(ctx context.Context, path string, payload
 	if err != nil {
 		return nil, err
 	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
 	return req, nil
 }
 
@@ -87,6 +96,54 @@ func (c *Client) NewDeletePartyRequest(ctx context.Context, path string) (*http.
 	if err != nil {
 		return nil, err
 	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
+
+// GrantPartyPayload is the party grant action payload.
+type GrantPartyPayload struct {
+	// What the key may do with the party
+	Role string `form:"role" json:"role" xml:"role"`
+}
+
+// GrantPartyPath computes a request path to the grant action of party.
+func GrantPartyPath(partyHash string, keyID string) string {
+	param0 := partyHash
+	param1 := keyID
+
+	return fmt.Sprintf("/api/parties/%s/grants/%s", param0, param1)
+}
+
+// Give an API key a role on the party, or take it away with the none role
+func (c *Client) GrantParty(ctx context.Context, path string, payload *GrantPartyPayload) (*http.Response, error) {
+	req, err := c.NewGrantPartyRequest(ctx, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewGrantPartyRequest create the request corresponding to the grant action endpoint of the party resource.
+func (c *Client) NewGrantPartyRequest(ctx context.Context, path string, payload *GrantPartyPayload) (*http.Request, error) {
+	var body bytes.Buffer
+	err := c.Encoder.Encode(payload, &body, "*/*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode body: %s", err)
+	}
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("PUT", u.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
 	return req, nil
 }
 
@@ -116,6 +173,9 @@ func (c *Client) NewListPartyRequest(ctx context.Context, path string) (*http.Re
 	if err != nil {
 		return nil, err
 	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
 	return req, nil
 }
 
@@ -146,6 +206,9 @@ func (c *Client) NewShowPartyRequest(ctx context.Context, path string) (*http.Re
 	if err != nil {
 		return nil, err
 	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
 	return req, nil
 }
 
@@ -181,5 +244,8 @@ func (c *Client) NewUpdatePartyRequest(ctx context.Context, path string, payload
 	if err != nil {
 		return nil, err
 	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
 	return req, nil
 }
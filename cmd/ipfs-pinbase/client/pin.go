@@ -16,14 +16,29 @@ import (
 	"golang.org/x/net/context"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // CreatePinPayload is the pin create action payload.
 type CreatePinPayload struct {
 	// Aliases for the pinned object
 	Aliases []string `form:"aliases" json:"aliases" xml:"aliases"`
-	// The hash of the object to be pinned
+	// When the pin stops being wanted, never if left out
+	ExpiresAt *time.Time `form:"expires-at,omitempty" json:"expires-at,omitempty" xml:"expires-at,omitempty"`
+	// The hash of the object to be pinned, which is kept as a CIDv1 in base32, an IPFS path such as /ipfs/<hash>/sub/path to pin the object it points at, or the IPNS name or DNSLink domain of a name pin
 	Hash string `form:"hash" json:"hash" xml:"hash"`
+	// What the hash is: the hash to pin, or an IPNS name or DNSLink domain whose target gets pinned; hash if left out
+	Kind *string `form:"kind,omitempty" json:"kind,omitempty" xml:"kind,omitempty"`
+	// Pin everything the object links to (recursive) or just its root block (direct)
+	Mode string `form:"mode" json:"mode" xml:"mode"`
+	// When the pin's window closes, it stays open if left out
+	NotAfter *time.Time `form:"not-after,omitempty" json:"not-after,omitempty" xml:"not-after,omitempty"`
+	// When the pin's window opens, it is wanted from the start if left out
+	NotBefore *time.Time `form:"not-before,omitempty" json:"not-before,omitempty" xml:"not-before,omitempty"`
+	// Number of IPFS nodes the object should be pinned on
+	Replication int `form:"replication" json:"replication" xml:"replication"`
+	// How long from now the pin stays wanted, as in "720h" or "30d", instead of an expires-at
+	TTL *string `form:"ttl,omitempty" json:"ttl,omitempty" xml:"ttl,omitempty"`
 	// Indicates that the party wants to actually pin the object
 	WantPinned bool `form:"want-pinned" json:"want-pinned" xml:"want-pinned"`
 }
@@ -60,6 +75,9 @@ func (c *Client) NewCreatePinRequest(ctx context.Context, path string, payload *
 	if err != nil {
 		return nil, err
 	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
 	return req, nil
 }
 
@@ -91,6 +109,43 @@ func (c *Client) NewDeletePinRequest(ctx context.Context, path string) (*http.Re
 	if err != nil {
 		return nil, err
 	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
+
+// HistoryPinPath computes a request path to the history action of pin.
+func HistoryPinPath(partyHash string, pinHash string) string {
+	param0 := partyHash
+	param1 := pinHash
+
+	return fmt.Sprintf("/api/parties/%s/pins/%s/history", param0, param1)
+}
+
+// List the status changes and edits of a pin under the party, oldest first. The history of a deleted pin is kept
+func (c *Client) HistoryPin(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewHistoryPinRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewHistoryPinRequest create the request corresponding to the history action endpoint of the pin resource.
+func (c *Client) NewHistoryPinRequest(ctx context.Context, path string) (*http.Request, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
 	return req, nil
 }
 
@@ -121,6 +176,82 @@ func (c *Client) NewListPinRequest(ctx context.Context, path string) (*http.Requ
 	if err != nil {
 		return nil, err
 	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
+
+// RenewPinPath computes a request path to the renew action of pin.
+func RenewPinPath(partyHash string, pinHash string) string {
+	param0 := partyHash
+	param1 := pinHash
+
+	return fmt.Sprintf("/api/parties/%s/pins/%s/renew", param0, param1)
+}
+
+// Want a pin under the party again until the new expiry, or for good without one
+func (c *Client) RenewPin(ctx context.Context, path string, payload *PinRenewPayload) (*http.Response, error) {
+	req, err := c.NewRenewPinRequest(ctx, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewRenewPinRequest create the request corresponding to the renew action endpoint of the pin resource.
+func (c *Client) NewRenewPinRequest(ctx context.Context, path string, payload *PinRenewPayload) (*http.Request, error) {
+	var body bytes.Buffer
+	err := c.Encoder.Encode(payload, &body, "*/*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode body: %s", err)
+	}
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("POST", u.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
+
+// ResetPinPath computes a request path to the reset action of pin.
+func ResetPinPath(partyHash string, pinHash string) string {
+	param0 := partyHash
+	param1 := pinHash
+
+	return fmt.Sprintf("/api/parties/%s/pins/%s/reset", param0, param1)
+}
+
+// Clear the failed attempts of a pin under the party and try it again
+func (c *Client) ResetPin(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewResetPinRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewResetPinRequest create the request corresponding to the reset action endpoint of the pin resource.
+func (c *Client) NewResetPinRequest(ctx context.Context, path string) (*http.Request, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("POST", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
 	return req, nil
 }
 
@@ -152,6 +283,9 @@ func (c *Client) NewShowPinRequest(ctx context.Context, path string) (*http.Requ
 	if err != nil {
 		return nil, err
 	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
 	return req, nil
 }
 
@@ -188,5 +322,8 @@ func (c *Client) NewUpdatePinRequest(ctx context.Context, path string, payload *
 	if err != nil {
 		return nil, err
 	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
 	return req, nil
 }
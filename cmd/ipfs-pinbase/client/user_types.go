@@ -10,12 +10,123 @@
 
 package client
 
+import (
+	"github.com/goadesign/goa"
+	"time"
+)
+
+// keyCreatePayload user type.
+type keyCreatePayload struct {
+	// Admin keys may do anything, others only what they are granted on each party
+	Admin *bool `form:"admin,omitempty" json:"admin,omitempty" xml:"admin,omitempty"`
+	// What or who the key is for
+	Description *string `form:"description,omitempty" json:"description,omitempty" xml:"description,omitempty"`
+}
+
+// Finalize sets the default values for keyCreatePayload type instance.
+func (ut *keyCreatePayload) Finalize() {
+	var defaultAdmin = false
+	if ut.Admin == nil {
+		ut.Admin = &defaultAdmin
+	}
+}
+
+// Publicize creates KeyCreatePayload from keyCreatePayload
+func (ut *keyCreatePayload) Publicize() *KeyCreatePayload {
+	var pub KeyCreatePayload
+	if ut.Admin != nil {
+		pub.Admin = *ut.Admin
+	}
+	if ut.Description != nil {
+		pub.Description = ut.Description
+	}
+	return &pub
+}
+
+// KeyCreatePayload user type.
+type KeyCreatePayload struct {
+	// Admin keys may do anything, others only what they are granted on each party
+	Admin bool `form:"admin" json:"admin" xml:"admin"`
+	// What or who the key is for
+	Description *string `form:"description,omitempty" json:"description,omitempty" xml:"description,omitempty"`
+}
+
+// nodeCreatePayload user type.
+type nodeCreatePayload struct {
+	// The host:port of the node's IPFS API
+	APIAddress *string `form:"api-address,omitempty" json:"api-address,omitempty" xml:"api-address,omitempty"`
+	// The name pins refer to the node by
+	Name *string `form:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"`
+}
+
+// Publicize creates NodeCreatePayload from nodeCreatePayload
+func (ut *nodeCreatePayload) Publicize() *NodeCreatePayload {
+	var pub NodeCreatePayload
+	if ut.APIAddress != nil {
+		pub.APIAddress = ut.APIAddress
+	}
+	if ut.Name != nil {
+		pub.Name = ut.Name
+	}
+	return &pub
+}
+
+// NodeCreatePayload user type.
+type NodeCreatePayload struct {
+	// The host:port of the node's IPFS API
+	APIAddress *string `form:"api-address,omitempty" json:"api-address,omitempty" xml:"api-address,omitempty"`
+	// The name pins refer to the node by
+	Name *string `form:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"`
+}
+
+// nodeUpdatePayload user type.
+type nodeUpdatePayload struct {
+	// The host:port of the node's IPFS API
+	APIAddress *string `form:"api-address,omitempty" json:"api-address,omitempty" xml:"api-address,omitempty"`
+}
+
+// Publicize creates NodeUpdatePayload from nodeUpdatePayload
+func (ut *nodeUpdatePayload) Publicize() *NodeUpdatePayload {
+	var pub NodeUpdatePayload
+	if ut.APIAddress != nil {
+		pub.APIAddress = ut.APIAddress
+	}
+	return &pub
+}
+
+// NodeUpdatePayload user type.
+type NodeUpdatePayload struct {
+	// The host:port of the node's IPFS API
+	APIAddress *string `form:"api-address,omitempty" json:"api-address,omitempty" xml:"api-address,omitempty"`
+}
+
 // partyCreatePayload user type.
 type partyCreatePayload struct {
 	// A helpful description of the party
 	Description *string `form:"description,omitempty" json:"description,omitempty" xml:"description,omitempty"`
 	// The hash of the object describing the party
 	Hash *string `form:"hash,omitempty" json:"hash,omitempty" xml:"hash,omitempty"`
+	// Most bytes the party's wanted pins may add up to, 0 for no limit
+	MaxBytes *int `form:"max-bytes,omitempty" json:"max-bytes,omitempty" xml:"max-bytes,omitempty"`
+	// Most pins the party may want pinned at once, 0 for no limit
+	MaxPins *int `form:"max-pins,omitempty" json:"max-pins,omitempty" xml:"max-pins,omitempty"`
+	// Base64 ed25519 public key the party is bound to, requests to its pins must then be signed with the matching private key
+	PublicKey *string `form:"public-key,omitempty" json:"public-key,omitempty" xml:"public-key,omitempty"`
+}
+
+// Validate validates the partyCreatePayload type instance.
+func (ut *partyCreatePayload) Validate() (err error) {
+	if ut.MaxBytes != nil {
+		if *ut.MaxBytes < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`response.max-bytes`, *ut.MaxBytes, 0, true))
+		}
+	}
+	if ut.MaxPins != nil {
+		if *ut.MaxPins < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`response.max-pins`, *ut.MaxPins, 0, true))
+		}
+	}
+	return
 }
 
 // Publicize creates PartyCreatePayload from partyCreatePayload
@@ -27,6 +138,15 @@ func (ut *partyCreatePayload) Publicize() *PartyCreatePayload {
 	if ut.Hash != nil {
 		pub.Hash = ut.Hash
 	}
+	if ut.MaxBytes != nil {
+		pub.MaxBytes = ut.MaxBytes
+	}
+	if ut.MaxPins != nil {
+		pub.MaxPins = ut.MaxPins
+	}
+	if ut.PublicKey != nil {
+		pub.PublicKey = ut.PublicKey
+	}
 	return &pub
 }
 
@@ -36,12 +156,93 @@ type PartyCreatePayload struct {
 	Description *string `form:"description,omitempty" json:"description,omitempty" xml:"description,omitempty"`
 	// The hash of the object describing the party
 	Hash *string `form:"hash,omitempty" json:"hash,omitempty" xml:"hash,omitempty"`
+	// Most bytes the party's wanted pins may add up to, 0 for no limit
+	MaxBytes *int `form:"max-bytes,omitempty" json:"max-bytes,omitempty" xml:"max-bytes,omitempty"`
+	// Most pins the party may want pinned at once, 0 for no limit
+	MaxPins *int `form:"max-pins,omitempty" json:"max-pins,omitempty" xml:"max-pins,omitempty"`
+	// Base64 ed25519 public key the party is bound to, requests to its pins must then be signed with the matching private key
+	PublicKey *string `form:"public-key,omitempty" json:"public-key,omitempty" xml:"public-key,omitempty"`
+}
+
+// Validate validates the PartyCreatePayload type instance.
+func (ut *PartyCreatePayload) Validate() (err error) {
+	if ut.MaxBytes != nil {
+		if *ut.MaxBytes < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`response.max-bytes`, *ut.MaxBytes, 0, true))
+		}
+	}
+	if ut.MaxPins != nil {
+		if *ut.MaxPins < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`response.max-pins`, *ut.MaxPins, 0, true))
+		}
+	}
+	return
+}
+
+// partyGrantPayload user type.
+type partyGrantPayload struct {
+	// What the key may do with the party
+	Role *string `form:"role,omitempty" json:"role,omitempty" xml:"role,omitempty"`
+}
+
+// Validate validates the partyGrantPayload type instance.
+func (ut *partyGrantPayload) Validate() (err error) {
+	if ut.Role != nil {
+		if !(*ut.Role == "none" || *ut.Role == "read-only" || *ut.Role == "party-owner") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(`response.role`, *ut.Role, []interface{}{"none", "read-only", "party-owner"}))
+		}
+	}
+	return
+}
+
+// Publicize creates PartyGrantPayload from partyGrantPayload
+func (ut *partyGrantPayload) Publicize() *PartyGrantPayload {
+	var pub PartyGrantPayload
+	if ut.Role != nil {
+		pub.Role = ut.Role
+	}
+	return &pub
+}
+
+// PartyGrantPayload user type.
+type PartyGrantPayload struct {
+	// What the key may do with the party
+	Role *string `form:"role,omitempty" json:"role,omitempty" xml:"role,omitempty"`
+}
+
+// Validate validates the PartyGrantPayload type instance.
+func (ut *PartyGrantPayload) Validate() (err error) {
+	if ut.Role != nil {
+		if !(*ut.Role == "none" || *ut.Role == "read-only" || *ut.Role == "party-owner") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(`response.role`, *ut.Role, []interface{}{"none", "read-only", "party-owner"}))
+		}
+	}
+	return
 }
 
 // partyUpdatePayload user type.
 type partyUpdatePayload struct {
 	// A helpful description of the party
 	Description *string `form:"description,omitempty" json:"description,omitempty" xml:"description,omitempty"`
+	// Most bytes the party's wanted pins may add up to, 0 for no limit
+	MaxBytes *int `form:"max-bytes,omitempty" json:"max-bytes,omitempty" xml:"max-bytes,omitempty"`
+	// Most pins the party may want pinned at once, 0 for no limit
+	MaxPins *int `form:"max-pins,omitempty" json:"max-pins,omitempty" xml:"max-pins,omitempty"`
+}
+
+// Validate validates the partyUpdatePayload type instance.
+func (ut *partyUpdatePayload) Validate() (err error) {
+	if ut.MaxBytes != nil {
+		if *ut.MaxBytes < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`response.max-bytes`, *ut.MaxBytes, 0, true))
+		}
+	}
+	if ut.MaxPins != nil {
+		if *ut.MaxPins < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`response.max-pins`, *ut.MaxPins, 0, true))
+		}
+	}
+	return
 }
 
 // Publicize creates PartyUpdatePayload from partyUpdatePayload
@@ -50,6 +251,12 @@ func (ut *partyUpdatePayload) Publicize() *PartyUpdatePayload {
 	if ut.Description != nil {
 		pub.Description = ut.Description
 	}
+	if ut.MaxBytes != nil {
+		pub.MaxBytes = ut.MaxBytes
+	}
+	if ut.MaxPins != nil {
+		pub.MaxPins = ut.MaxPins
+	}
 	return &pub
 }
 
@@ -57,27 +264,113 @@ func (ut *partyUpdatePayload) Publicize() *PartyUpdatePayload {
 type PartyUpdatePayload struct {
 	// A helpful description of the party
 	Description *string `form:"description,omitempty" json:"description,omitempty" xml:"description,omitempty"`
+	// Most bytes the party's wanted pins may add up to, 0 for no limit
+	MaxBytes *int `form:"max-bytes,omitempty" json:"max-bytes,omitempty" xml:"max-bytes,omitempty"`
+	// Most pins the party may want pinned at once, 0 for no limit
+	MaxPins *int `form:"max-pins,omitempty" json:"max-pins,omitempty" xml:"max-pins,omitempty"`
+}
+
+// Validate validates the PartyUpdatePayload type instance.
+func (ut *PartyUpdatePayload) Validate() (err error) {
+	if ut.MaxBytes != nil {
+		if *ut.MaxBytes < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`response.max-bytes`, *ut.MaxBytes, 0, true))
+		}
+	}
+	if ut.MaxPins != nil {
+		if *ut.MaxPins < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`response.max-pins`, *ut.MaxPins, 0, true))
+		}
+	}
+	return
 }
 
 // pinCreatePayload user type.
 type pinCreatePayload struct {
 	// Aliases for the pinned object
 	Aliases []string `form:"aliases,omitempty" json:"aliases,omitempty" xml:"aliases,omitempty"`
-	// The hash of the object to be pinned
+	// When the pin stops being wanted, never if left out
+	ExpiresAt *time.Time `form:"expires-at,omitempty" json:"expires-at,omitempty" xml:"expires-at,omitempty"`
+	// The hash of the object to be pinned, which is kept as a CIDv1 in base32, an IPFS path such as /ipfs/<hash>/sub/path to pin the object it points at, or the IPNS name or DNSLink domain of a name pin
 	Hash *string `form:"hash,omitempty" json:"hash,omitempty" xml:"hash,omitempty"`
+	// What the hash is: the hash to pin, or an IPNS name or DNSLink domain whose target gets pinned; hash if left out
+	Kind *string `form:"kind,omitempty" json:"kind,omitempty" xml:"kind,omitempty"`
+	// Pin everything the object links to (recursive) or just its root block (direct)
+	Mode *string `form:"mode,omitempty" json:"mode,omitempty" xml:"mode,omitempty"`
+	// When the pin's window closes, it stays open if left out
+	NotAfter *time.Time `form:"not-after,omitempty" json:"not-after,omitempty" xml:"not-after,omitempty"`
+	// When the pin's window opens, it is wanted from the start if left out
+	NotBefore *time.Time `form:"not-before,omitempty" json:"not-before,omitempty" xml:"not-before,omitempty"`
+	// Number of IPFS nodes the object should be pinned on
+	Replication *int `form:"replication,omitempty" json:"replication,omitempty" xml:"replication,omitempty"`
+	// How long from now the pin stays wanted, as in "720h" or "30d", instead of an expires-at
+	TTL *string `form:"ttl,omitempty" json:"ttl,omitempty" xml:"ttl,omitempty"`
 	// Indicates that the party wants to actually pin the object
 	WantPinned *bool `form:"want-pinned,omitempty" json:"want-pinned,omitempty" xml:"want-pinned,omitempty"`
 }
 
+// Finalize sets the default values for pinCreatePayload type instance.
+func (ut *pinCreatePayload) Finalize() {
+	var defaultMode = "recursive"
+	if ut.Mode == nil {
+		ut.Mode = &defaultMode
+	}
+	var defaultReplication = 1
+	if ut.Replication == nil {
+		ut.Replication = &defaultReplication
+	}
+}
+
+// Validate validates the pinCreatePayload type instance.
+func (ut *pinCreatePayload) Validate() (err error) {
+	if ut.Kind != nil {
+		if !(*ut.Kind == "hash" || *ut.Kind == "name") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(`response.kind`, *ut.Kind, []interface{}{"hash", "name"}))
+		}
+	}
+	if ut.Mode != nil {
+		if !(*ut.Mode == "recursive" || *ut.Mode == "direct") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(`response.mode`, *ut.Mode, []interface{}{"recursive", "direct"}))
+		}
+	}
+	if ut.Replication != nil {
+		if *ut.Replication < 1 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`response.replication`, *ut.Replication, 1, true))
+		}
+	}
+	return
+}
+
 // Publicize creates PinCreatePayload from pinCreatePayload
 func (ut *pinCreatePayload) Publicize() *PinCreatePayload {
 	var pub PinCreatePayload
 	if ut.Aliases != nil {
 		pub.Aliases = ut.Aliases
 	}
+	if ut.ExpiresAt != nil {
+		pub.ExpiresAt = ut.ExpiresAt
+	}
 	if ut.Hash != nil {
 		pub.Hash = ut.Hash
 	}
+	if ut.Kind != nil {
+		pub.Kind = ut.Kind
+	}
+	if ut.Mode != nil {
+		pub.Mode = *ut.Mode
+	}
+	if ut.NotAfter != nil {
+		pub.NotAfter = ut.NotAfter
+	}
+	if ut.NotBefore != nil {
+		pub.NotBefore = ut.NotBefore
+	}
+	if ut.Replication != nil {
+		pub.Replication = *ut.Replication
+	}
+	if ut.TTL != nil {
+		pub.TTL = ut.TTL
+	}
 	if ut.WantPinned != nil {
 		pub.WantPinned = ut.WantPinned
 	}
@@ -88,26 +381,253 @@ func (ut *pinCreatePayload) Publicize() *PinCreatePayload {
 type PinCreatePayload struct {
 	// Aliases for the pinned object
 	Aliases []string `form:"aliases,omitempty" json:"aliases,omitempty" xml:"aliases,omitempty"`
-	// The hash of the object to be pinned
+	// When the pin stops being wanted, never if left out
+	ExpiresAt *time.Time `form:"expires-at,omitempty" json:"expires-at,omitempty" xml:"expires-at,omitempty"`
+	// The hash of the object to be pinned, which is kept as a CIDv1 in base32, an IPFS path such as /ipfs/<hash>/sub/path to pin the object it points at, or the IPNS name or DNSLink domain of a name pin
 	Hash *string `form:"hash,omitempty" json:"hash,omitempty" xml:"hash,omitempty"`
+	// What the hash is: the hash to pin, or an IPNS name or DNSLink domain whose target gets pinned; hash if left out
+	Kind *string `form:"kind,omitempty" json:"kind,omitempty" xml:"kind,omitempty"`
+	// Pin everything the object links to (recursive) or just its root block (direct)
+	Mode string `form:"mode" json:"mode" xml:"mode"`
+	// When the pin's window closes, it stays open if left out
+	NotAfter *time.Time `form:"not-after,omitempty" json:"not-after,omitempty" xml:"not-after,omitempty"`
+	// When the pin's window opens, it is wanted from the start if left out
+	NotBefore *time.Time `form:"not-before,omitempty" json:"not-before,omitempty" xml:"not-before,omitempty"`
+	// Number of IPFS nodes the object should be pinned on
+	Replication int `form:"replication" json:"replication" xml:"replication"`
+	// How long from now the pin stays wanted, as in "720h" or "30d", instead of an expires-at
+	TTL *string `form:"ttl,omitempty" json:"ttl,omitempty" xml:"ttl,omitempty"`
 	// Indicates that the party wants to actually pin the object
 	WantPinned *bool `form:"want-pinned,omitempty" json:"want-pinned,omitempty" xml:"want-pinned,omitempty"`
 }
 
+// Validate validates the PinCreatePayload type instance.
+func (ut *PinCreatePayload) Validate() (err error) {
+	if ut.Kind != nil {
+		if !(*ut.Kind == "hash" || *ut.Kind == "name") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(`response.kind`, *ut.Kind, []interface{}{"hash", "name"}))
+		}
+	}
+	if !(ut.Mode == "recursive" || ut.Mode == "direct") {
+		err = goa.MergeErrors(err, goa.InvalidEnumValueError(`response.mode`, ut.Mode, []interface{}{"recursive", "direct"}))
+	}
+	if ut.Replication < 1 {
+		err = goa.MergeErrors(err, goa.InvalidRangeError(`response.replication`, ut.Replication, 1, true))
+	}
+	return
+}
+
+// How a pin is doing on a single IPFS node
+type pinNode struct {
+	// Last pin error message from the node
+	LastError *string `form:"last-error,omitempty" json:"last-error,omitempty" xml:"last-error,omitempty"`
+	// The name of the node
+	Node *string `form:"node,omitempty" json:"node,omitempty" xml:"node,omitempty"`
+	// The status of the pin on the node
+	Status *string `form:"status,omitempty" json:"status,omitempty" xml:"status,omitempty"`
+}
+
+// Validate validates the pinNode type instance.
+func (ut *pinNode) Validate() (err error) {
+	if ut.Node == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "node"))
+	}
+	if ut.Status == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "status"))
+	}
+	if ut.LastError == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "last-error"))
+	}
+	return
+}
+
+// Publicize creates PinNode from pinNode
+func (ut *pinNode) Publicize() *PinNode {
+	var pub PinNode
+	if ut.LastError != nil {
+		pub.LastError = *ut.LastError
+	}
+	if ut.Node != nil {
+		pub.Node = *ut.Node
+	}
+	if ut.Status != nil {
+		pub.Status = *ut.Status
+	}
+	return &pub
+}
+
+// How a pin is doing on a single IPFS node
+type PinNode struct {
+	// Last pin error message from the node
+	LastError string `form:"last-error" json:"last-error" xml:"last-error"`
+	// The name of the node
+	Node string `form:"node" json:"node" xml:"node"`
+	// The status of the pin on the node
+	Status string `form:"status" json:"status" xml:"status"`
+}
+
+// Validate validates the PinNode type instance.
+func (ut *PinNode) Validate() (err error) {
+	if ut.Node == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "node"))
+	}
+	if ut.Status == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "status"))
+	}
+	if ut.LastError == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "last-error"))
+	}
+	return
+}
+
+// pinRenewPayload user type.
+type pinRenewPayload struct {
+	// When the pin stops being wanted, never if left out
+	ExpiresAt *time.Time `form:"expires-at,omitempty" json:"expires-at,omitempty" xml:"expires-at,omitempty"`
+	// How long from now the pin stays wanted, as in "720h" or "30d", instead of an expires-at
+	TTL *string `form:"ttl,omitempty" json:"ttl,omitempty" xml:"ttl,omitempty"`
+}
+
+// Publicize creates PinRenewPayload from pinRenewPayload
+func (ut *pinRenewPayload) Publicize() *PinRenewPayload {
+	var pub PinRenewPayload
+	if ut.ExpiresAt != nil {
+		pub.ExpiresAt = ut.ExpiresAt
+	}
+	if ut.TTL != nil {
+		pub.TTL = ut.TTL
+	}
+	return &pub
+}
+
+// PinRenewPayload user type.
+type PinRenewPayload struct {
+	// When the pin stops being wanted, never if left out
+	ExpiresAt *time.Time `form:"expires-at,omitempty" json:"expires-at,omitempty" xml:"expires-at,omitempty"`
+	// How long from now the pin stays wanted, as in "720h" or "30d", instead of an expires-at
+	TTL *string `form:"ttl,omitempty" json:"ttl,omitempty" xml:"ttl,omitempty"`
+}
+
+// The outcome of resolving the name of a name pin
+type pinResolution struct {
+	// Why resolving the name failed, empty if it did not
+	Error *string `form:"error,omitempty" json:"error,omitempty" xml:"error,omitempty"`
+	// The hash the name pointed at, empty if resolving it failed
+	Target *string `form:"target,omitempty" json:"target,omitempty" xml:"target,omitempty"`
+	// When the name was resolved
+	Time *time.Time `form:"time,omitempty" json:"time,omitempty" xml:"time,omitempty"`
+}
+
+// Validate validates the pinResolution type instance.
+func (ut *pinResolution) Validate() (err error) {
+	if ut.Time == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "time"))
+	}
+	if ut.Target == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "target"))
+	}
+	if ut.Error == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "error"))
+	}
+	return
+}
+
+// Publicize creates PinResolution from pinResolution
+func (ut *pinResolution) Publicize() *PinResolution {
+	var pub PinResolution
+	if ut.Error != nil {
+		pub.Error = *ut.Error
+	}
+	if ut.Target != nil {
+		pub.Target = *ut.Target
+	}
+	if ut.Time != nil {
+		pub.Time = *ut.Time
+	}
+	return &pub
+}
+
+// The outcome of resolving the name of a name pin
+type PinResolution struct {
+	// Why resolving the name failed, empty if it did not
+	Error string `form:"error" json:"error" xml:"error"`
+	// The hash the name pointed at, empty if resolving it failed
+	Target string `form:"target" json:"target" xml:"target"`
+	// When the name was resolved
+	Time time.Time `form:"time" json:"time" xml:"time"`
+}
+
+// Validate validates the PinResolution type instance.
+func (ut *PinResolution) Validate() (err error) {
+
+	if ut.Target == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "target"))
+	}
+	if ut.Error == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "error"))
+	}
+	return
+}
+
 // pinUpdatePayload user type.
 type pinUpdatePayload struct {
 	// Aliases for the pinned object
 	Aliases []string `form:"aliases,omitempty" json:"aliases,omitempty" xml:"aliases,omitempty"`
+	// When the pin stops being wanted, left as it is if left out
+	ExpiresAt *time.Time `form:"expires-at,omitempty" json:"expires-at,omitempty" xml:"expires-at,omitempty"`
+	// Pin everything the object links to (recursive) or just its root block (direct), left as it is if left out
+	Mode *string `form:"mode,omitempty" json:"mode,omitempty" xml:"mode,omitempty"`
+	// When the pin's window closes, left as it is if left out and open if zero
+	NotAfter *time.Time `form:"not-after,omitempty" json:"not-after,omitempty" xml:"not-after,omitempty"`
+	// When the pin's window opens, left as it is if left out and wanted from the start if zero
+	NotBefore *time.Time `form:"not-before,omitempty" json:"not-before,omitempty" xml:"not-before,omitempty"`
+	// Number of IPFS nodes the object should be pinned on, left as it is if left out
+	Replication *int `form:"replication,omitempty" json:"replication,omitempty" xml:"replication,omitempty"`
+	// How long from now the pin stays wanted, as in "720h" or "30d", or "never" for no expiry, instead of an expires-at
+	TTL *string `form:"ttl,omitempty" json:"ttl,omitempty" xml:"ttl,omitempty"`
 	// Indicates that the party wants to actually pin the object
 	WantPinned *bool `form:"want-pinned,omitempty" json:"want-pinned,omitempty" xml:"want-pinned,omitempty"`
 }
 
+// Validate validates the pinUpdatePayload type instance.
+func (ut *pinUpdatePayload) Validate() (err error) {
+	if ut.Mode != nil {
+		if !(*ut.Mode == "recursive" || *ut.Mode == "direct") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(`response.mode`, *ut.Mode, []interface{}{"recursive", "direct"}))
+		}
+	}
+	if ut.Replication != nil {
+		if *ut.Replication < 1 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`response.replication`, *ut.Replication, 1, true))
+		}
+	}
+	return
+}
+
 // Publicize creates PinUpdatePayload from pinUpdatePayload
 func (ut *pinUpdatePayload) Publicize() *PinUpdatePayload {
 	var pub PinUpdatePayload
 	if ut.Aliases != nil {
 		pub.Aliases = ut.Aliases
 	}
+	if ut.ExpiresAt != nil {
+		pub.ExpiresAt = ut.ExpiresAt
+	}
+	if ut.Mode != nil {
+		pub.Mode = ut.Mode
+	}
+	if ut.NotAfter != nil {
+		pub.NotAfter = ut.NotAfter
+	}
+	if ut.NotBefore != nil {
+		pub.NotBefore = ut.NotBefore
+	}
+	if ut.Replication != nil {
+		pub.Replication = ut.Replication
+	}
+	if ut.TTL != nil {
+		pub.TTL = ut.TTL
+	}
 	if ut.WantPinned != nil {
 		pub.WantPinned = ut.WantPinned
 	}
@@ -118,6 +638,74 @@ func (ut *pinUpdatePayload) Publicize() *PinUpdatePayload {
 type PinUpdatePayload struct {
 	// Aliases for the pinned object
 	Aliases []string `form:"aliases,omitempty" json:"aliases,omitempty" xml:"aliases,omitempty"`
+	// When the pin stops being wanted, left as it is if left out
+	ExpiresAt *time.Time `form:"expires-at,omitempty" json:"expires-at,omitempty" xml:"expires-at,omitempty"`
+	// Pin everything the object links to (recursive) or just its root block (direct), left as it is if left out
+	Mode *string `form:"mode,omitempty" json:"mode,omitempty" xml:"mode,omitempty"`
+	// When the pin's window closes, left as it is if left out and open if zero
+	NotAfter *time.Time `form:"not-after,omitempty" json:"not-after,omitempty" xml:"not-after,omitempty"`
+	// When the pin's window opens, left as it is if left out and wanted from the start if zero
+	NotBefore *time.Time `form:"not-before,omitempty" json:"not-before,omitempty" xml:"not-before,omitempty"`
+	// Number of IPFS nodes the object should be pinned on, left as it is if left out
+	Replication *int `form:"replication,omitempty" json:"replication,omitempty" xml:"replication,omitempty"`
+	// How long from now the pin stays wanted, as in "720h" or "30d", or "never" for no expiry, instead of an expires-at
+	TTL *string `form:"ttl,omitempty" json:"ttl,omitempty" xml:"ttl,omitempty"`
 	// Indicates that the party wants to actually pin the object
 	WantPinned *bool `form:"want-pinned,omitempty" json:"want-pinned,omitempty" xml:"want-pinned,omitempty"`
 }
+
+// Validate validates the PinUpdatePayload type instance.
+func (ut *PinUpdatePayload) Validate() (err error) {
+	if ut.Mode != nil {
+		if !(*ut.Mode == "recursive" || *ut.Mode == "direct") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(`response.mode`, *ut.Mode, []interface{}{"recursive", "direct"}))
+		}
+	}
+	if ut.Replication != nil {
+		if *ut.Replication < 1 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(`response.replication`, *ut.Replication, 1, true))
+		}
+	}
+	return
+}
+
+// webhookCreatePayload user type.
+type webhookCreatePayload struct {
+	// The http or https URL pin status changes are posted to
+	URL *string `form:"url,omitempty" json:"url,omitempty" xml:"url,omitempty"`
+}
+
+// Validate validates the webhookCreatePayload type instance.
+func (ut *webhookCreatePayload) Validate() (err error) {
+	if ut.URL != nil {
+		if err2 := goa.ValidateFormat(goa.FormatURI, *ut.URL); err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidFormatError(`response.url`, *ut.URL, goa.FormatURI, err2))
+		}
+	}
+	return
+}
+
+// Publicize creates WebhookCreatePayload from webhookCreatePayload
+func (ut *webhookCreatePayload) Publicize() *WebhookCreatePayload {
+	var pub WebhookCreatePayload
+	if ut.URL != nil {
+		pub.URL = ut.URL
+	}
+	return &pub
+}
+
+// WebhookCreatePayload user type.
+type WebhookCreatePayload struct {
+	// The http or https URL pin status changes are posted to
+	URL *string `form:"url,omitempty" json:"url,omitempty" xml:"url,omitempty"`
+}
+
+// Validate validates the WebhookCreatePayload type instance.
+func (ut *WebhookCreatePayload) Validate() (err error) {
+	if ut.URL != nil {
+		if err2 := goa.ValidateFormat(goa.FormatURI, *ut.URL); err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidFormatError(`response.url`, *ut.URL, goa.FormatURI, err2))
+		}
+	}
+	return
+}
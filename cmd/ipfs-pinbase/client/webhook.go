@@ -0,0 +1,164 @@
+// Code generated by goagen v1.1.0-dirty, command line:
+// $ goagen
+// --design=github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/design
+// --out=$(GOPATH)/src/github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase
+// --version=v1.1.0-dirty
+//
+// API "pinbase": webhook Resource Client
+//
+// The content of this file is auto-generated, DO NOT MODIFY
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"golang.org/x/net/context"
+	"net/http"
+	"net/url"
+)
+
+// CreateWebhookPayload is the webhook create action payload.
+type CreateWebhookPayload struct {
+	// The http or https URL pin status changes are posted to
+	URL string `form:"url" json:"url" xml:"url"`
+}
+
+// CreateWebhookPath computes a request path to the create action of webhook.
+func CreateWebhookPath(partyHash string) string {
+	param0 := partyHash
+
+	return fmt.Sprintf("/api/parties/%s/webhooks", param0)
+}
+
+// Register a webhook for the party. The secret is only ever shown in this response
+func (c *Client) CreateWebhook(ctx context.Context, path string, payload *CreateWebhookPayload) (*http.Response, error) {
+	req, err := c.NewCreateWebhookRequest(ctx, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewCreateWebhookRequest create the request corresponding to the create action endpoint of the webhook resource.
+func (c *Client) NewCreateWebhookRequest(ctx context.Context, path string, payload *CreateWebhookPayload) (*http.Request, error) {
+	var body bytes.Buffer
+	err := c.Encoder.Encode(payload, &body, "*/*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode body: %s", err)
+	}
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("POST", u.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
+
+// DeleteWebhookPath computes a request path to the delete action of webhook.
+func DeleteWebhookPath(partyHash string, webhookID string) string {
+	param0 := partyHash
+	param1 := webhookID
+
+	return fmt.Sprintf("/api/parties/%s/webhooks/%s", param0, param1)
+}
+
+// Delete a webhook of the party, dropping its pending deliveries
+func (c *Client) DeleteWebhook(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewDeleteWebhookRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewDeleteWebhookRequest create the request corresponding to the delete action endpoint of the webhook resource.
+func (c *Client) NewDeleteWebhookRequest(ctx context.Context, path string) (*http.Request, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
+
+// ListWebhookPath computes a request path to the list action of webhook.
+func ListWebhookPath(partyHash string) string {
+	param0 := partyHash
+
+	return fmt.Sprintf("/api/parties/%s/webhooks", param0)
+}
+
+// List the webhooks of the party
+func (c *Client) ListWebhook(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewListWebhookRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewListWebhookRequest create the request corresponding to the list action endpoint of the webhook resource.
+func (c *Client) NewListWebhookRequest(ctx context.Context, path string) (*http.Request, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
+
+// ShowWebhookPath computes a request path to the show action of webhook.
+func ShowWebhookPath(partyHash string, webhookID string) string {
+	param0 := partyHash
+	param1 := webhookID
+
+	return fmt.Sprintf("/api/parties/%s/webhooks/%s", param0, param1)
+}
+
+// Get the webhook of the party by ID
+func (c *Client) ShowWebhook(ctx context.Context, path string) (*http.Response, error) {
+	req, err := c.NewShowWebhookRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(ctx, req)
+}
+
+// NewShowWebhookRequest create the request corresponding to the show action endpoint of the webhook resource.
+func (c *Client) NewShowWebhookRequest(ctx context.Context, path string) (*http.Request, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKeySigner != nil {
+		c.APIKeySigner.Sign(req)
+	}
+	return req, nil
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Config is what the daemon runs with. Each option is read from the config
+// file, then from its PINBASE_ environment variable and then from its flag,
+// each overriding the ones before.
+type Config struct {
+	Database        string            `yaml:"database"`
+	Listen          string            `yaml:"listen"`
+	Nodes           map[string]string `yaml:"nodes"`
+	NodeInterval    Duration          `yaml:"node-interval"`
+	SweepInterval   Duration          `yaml:"sweep-interval"`
+	PinWorkers      int               `yaml:"pin-workers"`
+	PinTimeout      Duration          `yaml:"pin-timeout"`
+	ShutdownTimeout Duration          `yaml:"shutdown-timeout"`
+	WebhookInterval Duration          `yaml:"webhook-interval"`
+	WebhookTimeout  Duration          `yaml:"webhook-timeout"`
+	ResolveInterval Duration          `yaml:"resolve-interval"`
+	NameGrace       Duration          `yaml:"name-grace"`
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Database:        "pinbase.db",
+		Listen:          ":3000",
+		Nodes:           map[string]string{"local": "127.0.0.1:5001"},
+		NodeInterval:    Duration(3 * time.Second),
+		SweepInterval:   Duration(5 * time.Second),
+		PinWorkers:      4,
+		PinTimeout:      Duration(10 * time.Minute),
+		ShutdownTimeout: Duration(30 * time.Second),
+		WebhookInterval: Duration(5 * time.Second),
+		WebhookTimeout:  Duration(10 * time.Second),
+		ResolveInterval: Duration(time.Minute),
+		NameGrace:       Duration(time.Hour),
+	}
+}
+
+// Validate returns an error listing everything wrong with the config.
+func (c *Config) Validate() error {
+	var problems []string
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, errors.Errorf(format, args...).Error())
+	}
+
+	if c.Database == "" {
+		addf("database: no file given")
+	}
+	if _, _, err := net.SplitHostPort(c.Listen); err != nil {
+		addf("listen: %s", err)
+	}
+	if len(c.Nodes) == 0 {
+		addf("nodes: no nodes given")
+	}
+	for _, name := range sortedNodes(c.Nodes) {
+		if name == "" {
+			addf("nodes: a node has no name")
+		}
+		if _, _, err := net.SplitHostPort(c.Nodes[name]); err != nil {
+			addf("nodes: %s: %s", name, err)
+		}
+	}
+	if c.NodeInterval <= 0 {
+		addf("node-interval: must be positive")
+	}
+	if c.SweepInterval <= 0 {
+		addf("sweep-interval: must be positive")
+	}
+	if c.PinWorkers < 1 {
+		addf("pin-workers: must be at least 1")
+	}
+	if c.PinTimeout < 0 {
+		addf("pin-timeout: must not be negative")
+	}
+	if c.ShutdownTimeout <= 0 {
+		addf("shutdown-timeout: must be positive")
+	}
+	if c.WebhookInterval <= 0 {
+		addf("webhook-interval: must be positive")
+	}
+	if c.WebhookTimeout <= 0 {
+		addf("webhook-timeout: must be positive")
+	}
+	if c.ResolveInterval <= 0 {
+		addf("resolve-interval: must be positive")
+	}
+	if c.NameGrace < 0 {
+		addf("name-grace: must not be negative")
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Duration is a time.Duration written the way time.ParseDuration reads it.
+type Duration time.Duration
+
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	err := unmarshal(&s)
+	if err != nil {
+		return err
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+	return nil
+}
+
+// configOption is a Config field that can also be set from an environment
+// variable or a flag, both of which set reads.
+type configOption struct {
+	name  string
+	usage string
+	get   func(c *Config) string
+	set   func(c *Config, s string) error
+}
+
+var configOptions = []configOption{
+	{
+		name:  "database",
+		usage: "bolt database file",
+		get:   func(c *Config) string { return c.Database },
+		set:   func(c *Config, s string) error { c.Database = s; return nil },
+	},
+	{
+		name:  "listen",
+		usage: "address the API listens on",
+		get:   func(c *Config) string { return c.Listen },
+		set:   func(c *Config, s string) error { c.Listen = s; return nil },
+	},
+	{
+		name:  "nodes",
+		usage: "comma separated name=address list of the IPFS API endpoints to register when no nodes are registered yet",
+		get:   func(c *Config) string { return formatNodes(c.Nodes) },
+		set: func(c *Config, s string) error {
+			nodes, err := parseNodes(s)
+			if err != nil {
+				return err
+			}
+
+			c.Nodes = nodes
+			return nil
+		},
+	},
+	durationOption("node-interval", "how often the IPFS nodes are checked", func(c *Config) *Duration { return &c.NodeInterval }),
+	durationOption("sweep-interval", "longest time between two full passes over the pins", func(c *Config) *Duration { return &c.SweepInterval }),
+	{
+		name:  "pin-workers",
+		usage: "number of pins worked on at once",
+		get:   func(c *Config) string { return strconv.Itoa(c.PinWorkers) },
+		set: func(c *Config, s string) error {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				return err
+			}
+
+			c.PinWorkers = v
+			return nil
+		},
+	},
+	durationOption("pin-timeout", "longest a single pin or unpin may take on a node, 0 for no limit", func(c *Config) *Duration { return &c.PinTimeout }),
+	durationOption("shutdown-timeout", "longest the requests and pins in flight are waited for on shutdown", func(c *Config) *Duration { return &c.ShutdownTimeout }),
+	durationOption("webhook-interval", "how often the webhook deliveries that are due are sent", func(c *Config) *Duration { return &c.WebhookInterval }),
+	durationOption("webhook-timeout", "longest a webhook may take to answer a delivery", func(c *Config) *Duration { return &c.WebhookTimeout }),
+	durationOption("resolve-interval", "how often the names of name pins are resolved", func(c *Config) *Duration { return &c.ResolveInterval }),
+	durationOption("name-grace", "how long name pins keep their previous target pinned after their name moves on, 0 to unpin it right away", func(c *Config) *Duration { return &c.NameGrace }),
+}
+
+func durationOption(name, usage string, field func(c *Config) *Duration) configOption {
+	return configOption{
+		name:  name,
+		usage: usage,
+		get:   func(c *Config) string { return time.Duration(*field(c)).String() },
+		set: func(c *Config, s string) error {
+			v, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+
+			*field(c) = Duration(v)
+			return nil
+		},
+	}
+}
+
+func (o configOption) env() string {
+	return "PINBASE_" + strings.ToUpper(strings.Replace(o.name, "-", "_", -1))
+}
+
+// ConfigFlags are the flags of the config options on a flag set.
+type ConfigFlags struct {
+	fs     *flag.FlagSet
+	values map[string]*string
+}
+
+// NewConfigFlags adds a flag for each config option to fs.
+func NewConfigFlags(fs *flag.FlagSet) *ConfigFlags {
+	cf := &ConfigFlags{
+		fs:     fs,
+		values: make(map[string]*string),
+	}
+
+	defaults := defaultConfig()
+	for _, o := range configOptions {
+		cf.values[o.name] = fs.String(o.name, o.get(defaults), o.usage+", also $"+o.env())
+	}
+
+	return cf
+}
+
+// Load builds the config from the defaults, the file, if any, the environment
+// and the flags that were set, and validates it.
+func (cf *ConfigFlags) Load(file string, getenv func(string) string) (*Config, error) {
+	c := defaultConfig()
+
+	if file != "" {
+		err := readConfigFile(c, file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range configOptions {
+		if v := getenv(o.env()); v != "" {
+			err := o.set(c, v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parse $%s", o.env())
+			}
+		}
+	}
+
+	var err error
+	cf.fs.Visit(func(f *flag.Flag) {
+		for _, o := range configOptions {
+			if err == nil && o.name == f.Name {
+				err = errors.Wrapf(o.set(c, *cf.values[o.name]), "parse -%s", o.name)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid config")
+	}
+
+	return c, nil
+}
+
+func readConfigFile(c *Config, file string) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return errors.Wrap(err, "read config file")
+	}
+
+	// the file replaces the default nodes rather than adding to them
+	nodes := c.Nodes
+	c.Nodes = nil
+
+	err = yaml.UnmarshalStrict(b, c)
+	if err != nil {
+		return errors.Wrapf(err, "parse config file %s", file)
+	}
+
+	if c.Nodes == nil {
+		c.Nodes = nodes
+	}
+
+	return nil
+}
+
+func parseNodes(s string) (map[string]string, error) {
+	nodes := make(map[string]string)
+
+	for _, n := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(n), "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("node %q is not a name=address pair", n)
+		}
+
+		if _, exists := nodes[parts[0]]; exists {
+			return nil, errors.Errorf("node %s is listed more than once", parts[0])
+		}
+
+		nodes[parts[0]] = parts[1]
+	}
+
+	return nodes, nil
+}
+
+func formatNodes(nodes map[string]string) string {
+	var pairs []string
+	for _, name := range sortedNodes(nodes) {
+		pairs = append(pairs, name+"="+nodes[name])
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func sortedNodes(nodes map[string]string) []string {
+	var names []string
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "pinbase-config-")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %+v", err)
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(content)
+	if err != nil {
+		t.Fatalf("failed to write config: %+v", err)
+	}
+
+	return f.Name()
+}
+
+func TestConfigLoad(t *testing.T) {
+	file := writeConfig(t, `
+database: /var/lib/pinbase.db
+listen: 127.0.0.1:4000
+nodes:
+  a: 10.0.0.1:5001
+  b: 10.0.0.2:5001
+node-interval: 10s
+pin-workers: 8
+`)
+	defer os.Remove(file)
+
+	env := map[string]string{
+		"PINBASE_LISTEN":      ":5000",
+		"PINBASE_PIN_WORKERS": "2",
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cf := NewConfigFlags(fs)
+	err := fs.Parse([]string{"-pin-workers", "16", "-pin-timeout", "1m"})
+	if err != nil {
+		t.Fatalf("failed to parse flags: %+v", err)
+	}
+
+	c, err := cf.Load(file, func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("failed to load config: %+v", err)
+	}
+
+	expected := &Config{
+		Database:        "/var/lib/pinbase.db",
+		Listen:          ":5000",
+		Nodes:           map[string]string{"a": "10.0.0.1:5001", "b": "10.0.0.2:5001"},
+		NodeInterval:    Duration(10 * time.Second),
+		SweepInterval:   Duration(5 * time.Second),
+		PinWorkers:      16,
+		PinTimeout:      Duration(time.Minute),
+		ShutdownTimeout: Duration(30 * time.Second),
+		WebhookInterval: Duration(5 * time.Second),
+		WebhookTimeout:  Duration(10 * time.Second),
+		ResolveInterval: Duration(time.Minute),
+		NameGrace:       Duration(time.Hour),
+	}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("got config %+v, expected %+v", c, expected)
+	}
+
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		t.Fatalf("failed to marshal config: %+v", err)
+	}
+
+	printed := writeConfig(t, string(b))
+	defer os.Remove(printed)
+
+	c, err = NewConfigFlags(flag.NewFlagSet("test", flag.ContinueOnError)).Load(printed, func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("failed to load printed config: %+v", err)
+	}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("got printed config %+v, expected %+v", c, expected)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	noEnv := func(string) string { return "" }
+
+	for _, tc := range []struct {
+		tag      string
+		file     string
+		env      map[string]string
+		args     []string
+		expected string
+	}{
+		{"unknown key", "databse: x.db\n", nil, nil, "databse"},
+		{"bad duration", "node-interval: often\n", nil, nil, "often"},
+		{"bad env", "", map[string]string{"PINBASE_PIN_WORKERS": "many"}, nil, "PINBASE_PIN_WORKERS"},
+		{"bad flag", "", nil, []string{"-nodes", "local"}, "-nodes"},
+		{"invalid", "listen: nowhere\npin-workers: 0\n", nil, nil, "; pin-workers: must be at least 1"},
+		{"no nodes", "nodes: {}\n", nil, nil, "nodes: no nodes given"},
+	} {
+		file := ""
+		if tc.file != "" {
+			file = writeConfig(t, tc.file)
+			defer os.Remove(file)
+		}
+
+		getenv := noEnv
+		if tc.env != nil {
+			getenv = func(k string) string { return tc.env[k] }
+		}
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		cf := NewConfigFlags(fs)
+		err := fs.Parse(tc.args)
+		if err != nil {
+			t.Fatalf("%s: failed to parse flags: %+v", tc.tag, err)
+		}
+
+		_, err = cf.Load(file, getenv)
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%s: got error %v, expected it to mention %q", tc.tag, err, tc.expected)
+		}
+	}
+}
@@ -28,6 +28,8 @@ var _ = API("pinbase", func() {
 	Consumes("application/json")
 	Produces("application/json")
 
+	Security(APIKey)
+
 	ResponseTemplate(Created, func(pattern string) {
 		Description("Resource created")
 		Status(201)
@@ -39,10 +41,17 @@ var _ = API("pinbase", func() {
 	})
 })
 
+var APIKey = APIKeySecurity("api_key", func() {
+	Description("A key handed out by the key resource, sent with every request")
+	Header("X-Pinbase-Key")
+})
+
 var _ = Resource("party", func() {
 	Description("The Pinbase Party resource")
 	BasePath("/parties")
 
+	Response(Forbidden, ErrorMedia)
+
 	Action("list", func() {
 		Description("List the parties available in this pinbase")
 		Routing(GET(""))
@@ -93,6 +102,21 @@ var _ = Resource("party", func() {
 		Response(NotFound)
 		Response(BadRequest, ErrorMedia)
 	})
+
+	Action("grant", func() {
+		Description("Give an API key a role on the party, or take it away with the none role")
+		Routing(PUT("/:partyHash/grants/:keyID"))
+		Params(func() {
+			PartyHashParam()
+			KeyIDParam()
+		})
+		Payload(PartyGrantPayload, func() {
+			Required("role")
+		})
+		Response(NoContent)
+		Response(NotFound)
+		Response(BadRequest, ErrorMedia)
+	})
 })
 
 func PartyHashParam() {
@@ -107,13 +131,40 @@ func PartyDescription() {
 	Attribute("description", String, "A helpful description of the party")
 }
 
+func PartyMaxPins() {
+	Attribute("max-pins", Integer, "Most pins the party may want pinned at once, 0 for no limit", func() {
+		Minimum(0)
+	})
+}
+
+func PartyMaxBytes() {
+	Attribute("max-bytes", Integer, "Most bytes the party's wanted pins may add up to, 0 for no limit", func() {
+		Minimum(0)
+	})
+}
+
+func PartyPublicKey() {
+	Attribute("public-key", String, "Base64 ed25519 public key the party is bound to, requests to its pins must then be signed with the matching private key")
+}
+
 var PartyCreatePayload = Type("party-create-payload", func() {
 	PartyHash()
 	PartyDescription()
+	PartyMaxPins()
+	PartyMaxBytes()
+	PartyPublicKey()
 })
 
 var PartyUpdatePayload = Type("party-update-payload", func() {
 	PartyDescription()
+	PartyMaxPins()
+	PartyMaxBytes()
+})
+
+var PartyGrantPayload = Type("party-grant-payload", func() {
+	Attribute("role", String, "What the key may do with the party", func() {
+		Enum("none", "read-only", "party-owner")
+	})
 })
 
 var PartyMedia = MediaType("application/vnd.pinbase.party+json", func() {
@@ -121,11 +172,25 @@ var PartyMedia = MediaType("application/vnd.pinbase.party+json", func() {
 	Attributes(func() {
 		PartyHash()
 		PartyDescription()
-		Required("hash", "description")
+		PartyMaxPins()
+		PartyMaxBytes()
+		Attribute("used-pins", Integer, "Number of pins the party wants pinned")
+		Attribute("used-bytes", Integer, "Bytes the party's wanted pins add up to, as far as they are known")
+		Attribute("pinned-pins", Integer, "Number of the party's pins the nodes confirmed as pinned")
+		Attribute("pinned-bytes", Integer, "Bytes the party's confirmed pins add up to, as far as they are known")
+		PartyPublicKey()
+		Required("hash", "description", "max-pins", "max-bytes", "used-pins", "used-bytes", "pinned-pins", "pinned-bytes")
 	})
 	View("default", func() {
 		PartyHash()
 		PartyDescription()
+		Attribute("max-pins")
+		Attribute("max-bytes")
+		Attribute("used-pins")
+		Attribute("used-bytes")
+		Attribute("pinned-pins")
+		Attribute("pinned-bytes")
+		Attribute("public-key")
 	})
 })
 
@@ -133,6 +198,8 @@ var _ = Resource("pin", func() {
 	Description("A thing to pin in IPFS")
 	BasePath("/parties/:partyHash/pins")
 
+	Response(Forbidden, ErrorMedia)
+
 	Action("list", func() {
 		Description("List the pins under the party")
 		Routing(GET(""))
@@ -153,6 +220,7 @@ var _ = Resource("pin", func() {
 		})
 		Response(OK, PinMedia)
 		Response(NotFound)
+		Response(BadRequest, ErrorMedia)
 	})
 
 	Action("create", func() {
@@ -192,14 +260,58 @@ var _ = Resource("pin", func() {
 		Response(NotFound)
 		Response(BadRequest, ErrorMedia)
 	})
+	Action("reset", func() {
+		Description("Clear the failed attempts of a pin under the party and try it again")
+		Routing(POST("/:pinHash/reset"))
+		Params(func() {
+			PartyHashParam()
+			PinHashParam()
+		})
+		Response(OK, PinMedia)
+		Response(NotFound)
+		Response(BadRequest, ErrorMedia)
+	})
+
+	Action("renew", func() {
+		Description("Want a pin under the party again until the new expiry, or for good without one")
+		Routing(POST("/:pinHash/renew"))
+		Params(func() {
+			PartyHashParam()
+			PinHashParam()
+		})
+		OptionalPayload(PinRenewPayload)
+		Response(OK, PinMedia)
+		Response(NotFound)
+		Response(BadRequest, ErrorMedia)
+	})
+
+	Action("history", func() {
+		Description("List the status changes and edits of a pin under the party, oldest first. The history of a deleted pin is kept")
+		Routing(GET("/:pinHash/history"))
+		Params(func() {
+			PartyHashParam()
+			PinHashParam()
+		})
+		Response(OK, func() {
+			Media(CollectionOf(PinHistoryMedia))
+		})
+		Response(NotFound)
+		Response(BadRequest, ErrorMedia)
+	})
 })
 
 func PinHashParam() {
-	Param("pinHash", String, "Pin Hash")
+	Param("pinHash", String, "The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin")
 }
 
 func PinHash() {
-	Attribute("hash", String, "The hash of the object to be pinned")
+	Attribute("hash", String, "The hash of the object to be pinned, which is kept as a CIDv1 in base32, an IPFS path such as /ipfs/<hash>/sub/path to pin the object it points at, or the IPNS name or DNSLink domain of a name pin")
+}
+
+func PinKind() {
+	Attribute("kind", String, "What the hash is: the hash to pin, or an IPNS name or DNSLink domain whose target gets pinned; hash if left out", func() {
+		Enum("hash", "name")
+	})
 }
 
 func PinAliases() {
@@ -210,15 +322,95 @@ func PinWantPinned() {
 	Attribute("want-pinned", Boolean, "Indicates that the party wants to actually pin the object")
 }
 
+func PinMode() {
+	Attribute("mode", String, "Pin everything the object links to (recursive) or just its root block (direct)", func() {
+		Enum("recursive", "direct")
+		Default("recursive")
+	})
+}
+
+func PinUpdateMode() {
+	Attribute("mode", String, "Pin everything the object links to (recursive) or just its root block (direct), left as it is if left out", func() {
+		Enum("recursive", "direct")
+	})
+}
+
+func PinReplication() {
+	Attribute("replication", Integer, "Number of IPFS nodes the object should be pinned on", func() {
+		Minimum(1)
+		Default(1)
+	})
+}
+
+func PinUpdateReplication() {
+	Attribute("replication", Integer, "Number of IPFS nodes the object should be pinned on, left as it is if left out", func() {
+		Minimum(1)
+	})
+}
+
+func PinExpiresAt() {
+	Attribute("expires-at", DateTime, "When the pin stops being wanted, never if left out")
+}
+
+func PinTTL() {
+	Attribute("ttl", String, "How long from now the pin stays wanted, as in \"720h\" or \"30d\", instead of an expires-at")
+}
+
+func PinUpdateExpiry() {
+	Attribute("expires-at", DateTime, "When the pin stops being wanted, left as it is if left out")
+	Attribute("ttl", String, "How long from now the pin stays wanted, as in \"720h\" or \"30d\", or \"never\" for no expiry, instead of an expires-at")
+}
+
+func PinWindow() {
+	Attribute("not-before", DateTime, "When the pin's window opens, it is wanted from the start if left out")
+	Attribute("not-after", DateTime, "When the pin's window closes, it stays open if left out")
+}
+
+func PinUpdateWindow() {
+	Attribute("not-before", DateTime, "When the pin's window opens, left as it is if left out and wanted from the start if zero")
+	Attribute("not-after", DateTime, "When the pin's window closes, left as it is if left out and open if zero")
+}
+
 var PinCreatePayload = Type("pin-create-payload", func() {
 	PinHash()
+	PinKind()
 	PinAliases()
 	PinWantPinned()
+	PinMode()
+	PinReplication()
+	PinExpiresAt()
+	PinTTL()
+	PinWindow()
 })
 
 var PinUpdatePayload = Type("pin-update-payload", func() {
 	PinAliases()
 	PinWantPinned()
+	PinUpdateMode()
+	PinUpdateReplication()
+	PinUpdateExpiry()
+	PinUpdateWindow()
+})
+
+var PinRenewPayload = Type("pin-renew-payload", func() {
+	PinExpiresAt()
+	PinTTL()
+})
+
+var PinNode = Type("pin-node", func() {
+	Description("How a pin is doing on a single IPFS node")
+	Attribute("node", String, "The name of the node")
+	Attribute("status", String, "The status of the pin on the node")
+	Attribute("last-error", String, "Last pin error message from the node")
+	Required("node", "status", "last-error")
+})
+
+var PinResolution = Type("pin-resolution", func() {
+	Description("The outcome of resolving the name of a name pin")
+	Attribute("time", DateTime, "When the name was resolved")
+	Attribute("target", String, "The hash the name pointed at, empty if resolving it failed")
+	Attribute("error", String, "Why resolving the name failed, empty if it did not")
+	Required("time", "target", "error")
 })
 
 var PinMedia = MediaType("application/vnd.pinbase.pin+json", func() {
@@ -227,15 +419,447 @@ var PinMedia = MediaType("application/vnd.pinbase.pin+json", func() {
 		PinHash()
 		PinAliases()
 		PinWantPinned()
+		PinMode()
+		PinReplication()
 		Attribute("status", String, "The status of the pin")
 		Attribute("last-error", String, "Last pin error message")
-		Required("hash", "aliases", "want-pinned", "status", "last-error")
+		Attribute("blocks-fetched", Integer, "Number of blocks fetched by the latest pinning")
+		Attribute("bytes-fetched", Integer, "Number of bytes fetched by the latest pinning, if known")
+		Attribute("nodes", ArrayOf(PinNode), "The nodes holding the pin or failing to")
+		Attribute("size", Integer, "Cumulative size of the pinned object in bytes, or of its root block for direct pins, 0 until known")
+		PinExpiresAt()
+		Attribute("expired", Boolean, "Whether the pin stopped being wanted because its expiry passed")
+		PinWindow()
+		Attribute("in-window", Boolean, "Whether the pin's window is open, the pin is left off the nodes otherwise")
+		PinKind()
+		Attribute("path", String, "The IPFS path the hash was resolved from when the pin was created, empty for pins of a plain hash")
+		Attribute("target", String, "The hash the name of a name pin last resolved to, empty until it first resolves")
+		Attribute("resolutions", ArrayOf(PinResolution), "The latest outcomes of resolving the name of a name pin, oldest first")
+		Required("hash", "aliases", "want-pinned", "mode", "replication", "status", "last-error", "blocks-fetched", "bytes-fetched", "nodes", "size", "expired", "in-window", "kind")
 	})
 	View("default", func() {
 		PinHash()
 		PinAliases()
 		PinWantPinned()
+		Attribute("mode")
+		Attribute("replication")
+		Attribute("status")
+		Attribute("last-error")
+		Attribute("blocks-fetched")
+		Attribute("bytes-fetched")
+		Attribute("nodes")
+		Attribute("size")
+		Attribute("expires-at")
+		Attribute("expired")
+		Attribute("not-before")
+		Attribute("not-after")
+		Attribute("in-window")
+		Attribute("kind")
+		Attribute("path")
+		Attribute("target")
+		Attribute("resolutions")
+	})
+})
+
+var PinHistoryMedia = MediaType("application/vnd.pinbase.pin-history+json", func() {
+	Description("A change of a pin")
+	Attributes(func() {
+		Attribute("time", DateTime, "When the change happened")
+		Attribute("change", String, "What happened to the pin", func() {
+			Enum("status", "created", "updated", "reset", "deleted", "expired", "renewed", "resolved")
+		})
+		Attribute("by", String, "The ID of the API key that made the change, empty for status changes")
+		Attribute("status", String, "The status the pin was left with")
+		Attribute("last-error", String, "The error of the pin, if the change left it with one")
+		Attribute("changes", ArrayOf(String), "What the change set or changed, as in \"want-pinned: true -> false\"")
+		Required("time", "change", "by", "status", "last-error", "changes")
+	})
+	View("default", func() {
+		Attribute("time")
+		Attribute("change")
+		Attribute("by")
+		Attribute("status")
+		Attribute("last-error")
+		Attribute("changes")
+	})
+})
+
+var _ = Resource("event", func() {
+	Description("Pin status changes streamed as Server-Sent Events, one pin event object of JSON per event")
+	BasePath("/events")
+
+	Response(Forbidden, ErrorMedia)
+
+	Action("stream", func() {
+		Description("Stream the pin status changes of every party, for admin keys")
+		Routing(GET(""))
+		Response(OK, "text/event-stream")
+	})
+
+	Action("party", func() {
+		Description("Stream the pin status changes of a party")
+		Routing(GET("/:partyHash"))
+		Params(func() {
+			PartyHashParam()
+		})
+		Response(OK, "text/event-stream")
+		Response(NotFound)
+	})
+})
+
+var PinEventMedia = MediaType("application/vnd.pinbase.pin-event+json", func() {
+	Description("A change of a pin's status, sent as the data of an event stream's pin events")
+	Attributes(func() {
+		Attribute("party", String, "The hash of the party the pin belongs to")
+		PinHash()
+		Attribute("status", String, "The new status of the pin")
+		Attribute("last-error", String, "Last pin error message")
+		Attribute("time", DateTime, "When the status changed")
+		Required("party", "hash", "status", "last-error", "time")
+	})
+	View("default", func() {
+		Attribute("party")
+		PinHash()
+		Attribute("status")
+		Attribute("last-error")
+		Attribute("time")
+	})
+})
+
+var _ = Resource("webhook", func() {
+	Description("URLs the party's pin status changes are posted to as JSON, signed with the webhook's secret")
+	BasePath("/parties/:partyHash/webhooks")
+
+	Response(Forbidden, ErrorMedia)
+
+	Action("list", func() {
+		Description("List the webhooks of the party")
+		Routing(GET(""))
+		Params(func() {
+			PartyHashParam()
+		})
+		Response(OK, func() {
+			Media(CollectionOf(WebhookMedia))
+		})
+		Response(NotFound)
+	})
+
+	Action("show", func() {
+		Description("Get the webhook of the party by ID")
+		Routing(GET("/:webhookID"))
+		Params(func() {
+			PartyHashParam()
+			WebhookIDParam()
+		})
+		Response(OK, WebhookMedia)
+		Response(NotFound)
+	})
+
+	Action("create", func() {
+		Description("Register a webhook for the party. The secret is only ever shown in this response")
+		Routing(POST(""))
+		Params(func() {
+			PartyHashParam()
+		})
+		Payload(WebhookCreatePayload, func() {
+			Required("url")
+		})
+		Response(Created, func() {
+			Media(WebhookMedia, "secret")
+			Headers(func() {
+				Header("Location", String, "href to the created resource", func() {
+					Pattern("/parties/.+/webhooks/.+")
+				})
+			})
+		})
+		Response(NotFound)
+		Response(BadRequest, ErrorMedia)
+	})
+
+	Action("delete", func() {
+		Description("Delete a webhook of the party, dropping its pending deliveries")
+		Routing(DELETE("/:webhookID"))
+		Params(func() {
+			PartyHashParam()
+			WebhookIDParam()
+		})
+		Response(NoContent)
+		Response(NotFound)
+		Response(BadRequest, ErrorMedia)
+	})
+})
+
+func WebhookIDParam() {
+	Param("webhookID", String, "Webhook ID")
+}
+
+func WebhookID() {
+	Attribute("id", String, "The ID of the webhook")
+}
+
+func WebhookURL() {
+	Attribute("url", String, "The http or https URL pin status changes are posted to", func() {
+		Format("uri")
+	})
+}
+
+var WebhookCreatePayload = Type("webhook-create-payload", func() {
+	WebhookURL()
+})
+
+var WebhookMedia = MediaType("application/vnd.pinbase.webhook+json", func() {
+	Description("A webhook of a party")
+	Attributes(func() {
+		WebhookID()
+		WebhookURL()
+		Attribute("created", DateTime, "When the webhook was registered")
+		Attribute("secret", String, "The key of the HMAC-SHA256 in the X-Pinbase-Webhook-Signature header of each post")
+		Required("id", "url", "created")
+	})
+	View("default", func() {
+		WebhookID()
+		WebhookURL()
+		Attribute("created")
+	})
+	View("secret", func() {
+		WebhookID()
+		WebhookURL()
+		Attribute("created")
+		Attribute("secret")
+	})
+})
+
+var _ = Resource("archive", func() {
+	Description("Hashes no party holds anymore, waiting to be unpinned")
+	BasePath("/archive")
+
+	Response(Forbidden, ErrorMedia)
+
+	Action("list", func() {
+		Description("List the archived hashes and how their unpinning is going")
+		Routing(GET(""))
+		Response(OK, func() {
+			Media(CollectionOf(ArchivedPinMedia))
+		})
+	})
+})
+
+var ArchivedPinMedia = MediaType("application/vnd.pinbase.archived-pin+json", func() {
+	Description("An archived Pin")
+	Attributes(func() {
+		PinHash()
+		Attribute("status", String, "The status of the unpinning")
+		Attribute("last-error", String, "Last unpin error message")
+		Required("hash", "status", "last-error")
+	})
+	View("default", func() {
+		PinHash()
 		Attribute("status")
 		Attribute("last-error")
 	})
 })
+
+var _ = Resource("node", func() {
+	Description("An IPFS node to pin on")
+	BasePath("/nodes")
+
+	Response(Forbidden, ErrorMedia)
+
+	Action("list", func() {
+		Description("List the registered IPFS nodes")
+		Routing(GET(""))
+		Response(OK, func() {
+			Media(CollectionOf(NodeMedia))
+		})
+	})
+
+	Action("show", func() {
+		Description("Get the node by name")
+		Routing(GET("/:nodeName"))
+		Params(func() {
+			NodeNameParam()
+		})
+		Response(OK, NodeMedia)
+		Response(NotFound)
+	})
+
+	Action("create", func() {
+		Description("Register a node")
+		Routing(POST(""))
+		Payload(NodeCreatePayload, func() {
+			Required("name", "api-address")
+		})
+		Response(Created, "/nodes/.+")
+		Response(BadRequest, ErrorMedia)
+	})
+
+	Action("update", func() {
+		Description("Change a node's API address")
+		Routing(PATCH("/:nodeName"))
+		Params(func() {
+			NodeNameParam()
+		})
+		Payload(NodeUpdatePayload, func() {
+			Required("api-address")
+		})
+		Response(OK, NodeMedia)
+		Response(NotFound)
+		Response(BadRequest, ErrorMedia)
+	})
+
+	Action("delete", func() {
+		Description("Stop pinning on a node. Whatever it has pinned stays there")
+		Routing(DELETE("/:nodeName"))
+		Params(func() {
+			NodeNameParam()
+		})
+		Response(NoContent)
+		Response(NotFound)
+		Response(BadRequest, ErrorMedia)
+	})
+})
+
+func NodeNameParam() {
+	Param("nodeName", String, "Node Name")
+}
+
+func NodeName() {
+	Attribute("name", String, "The name pins refer to the node by")
+}
+
+func NodeAPIAddress() {
+	Attribute("api-address", String, "The host:port of the node's IPFS API", func() {
+		Example("127.0.0.1:5001")
+	})
+}
+
+var NodeCreatePayload = Type("node-create-payload", func() {
+	NodeName()
+	NodeAPIAddress()
+})
+
+var NodeUpdatePayload = Type("node-update-payload", func() {
+	NodeAPIAddress()
+})
+
+var NodeMedia = MediaType("application/vnd.pinbase.node+json", func() {
+	Description("An IPFS node pins are spread over")
+	Attributes(func() {
+		NodeName()
+		NodeAPIAddress()
+		Attribute("reachable", Boolean, "Whether the node answered the last check")
+		Attribute("last-seen", DateTime, "When the node last answered a check, if ever")
+		Attribute("pin-count", Integer, "Number of pins on the node as of the last answered check")
+		Attribute("repo-size", Integer, "Bytes used by the node's repo as of the last answered check")
+		Required("name", "api-address", "reachable", "pin-count", "repo-size")
+	})
+	View("default", func() {
+		NodeName()
+		NodeAPIAddress()
+		Attribute("reachable")
+		Attribute("last-seen")
+		Attribute("pin-count")
+		Attribute("repo-size")
+	})
+})
+
+var _ = Resource("key", func() {
+	Description("The API keys that may use this pinbase")
+	BasePath("/keys")
+
+	Response(Forbidden, ErrorMedia)
+
+	Action("list", func() {
+		Description("List the API keys")
+		Routing(GET(""))
+		Response(OK, func() {
+			Media(CollectionOf(KeyMedia))
+		})
+	})
+
+	Action("show", func() {
+		Description("Get the API key by ID")
+		Routing(GET("/:keyID"))
+		Params(func() {
+			KeyIDParam()
+		})
+		Response(OK, KeyMedia)
+		Response(NotFound)
+	})
+
+	Action("create", func() {
+		Description("Create an API key. The key itself is only ever shown in this response")
+		Routing(POST(""))
+		Payload(KeyCreatePayload, func() {
+			Required("description")
+		})
+		Response(Created, func() {
+			Media(KeyMedia, "secret")
+			Headers(func() {
+				Header("Location", String, "href to the created resource", func() {
+					Pattern("/keys/.+")
+				})
+			})
+		})
+		Response(BadRequest, ErrorMedia)
+	})
+
+	Action("delete", func() {
+		Description("Revoke an API key")
+		Routing(DELETE("/:keyID"))
+		Params(func() {
+			KeyIDParam()
+		})
+		Response(NoContent)
+		Response(NotFound)
+		Response(BadRequest, ErrorMedia)
+	})
+})
+
+func KeyIDParam() {
+	Param("keyID", String, "Key ID")
+}
+
+func KeyID() {
+	Attribute("id", String, "The public part of the key that identifies it")
+}
+
+func KeyDescription() {
+	Attribute("description", String, "What or who the key is for")
+}
+
+func KeyAdmin() {
+	Attribute("admin", Boolean, "Admin keys may do anything, others only what they are granted on each party", func() {
+		Default(false)
+	})
+}
+
+var KeyCreatePayload = Type("key-create-payload", func() {
+	KeyDescription()
+	KeyAdmin()
+})
+
+var KeyMedia = MediaType("application/vnd.pinbase.key+json", func() {
+	Description("An API key")
+	Attributes(func() {
+		KeyID()
+		KeyDescription()
+		KeyAdmin()
+		Attribute("created", DateTime, "When the key was created")
+		Attribute("key", String, "The key to send in the X-Pinbase-Key header")
+		Required("id", "description", "admin", "created")
+	})
+	View("default", func() {
+		KeyID()
+		KeyDescription()
+		Attribute("admin")
+		Attribute("created")
+	})
+	View("secret", func() {
+		KeyID()
+		KeyDescription()
+		Attribute("admin")
+		Attribute("created")
+		Attribute("key")
+	})
+})
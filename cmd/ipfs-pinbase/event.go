@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/app"
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/goadesign/goa"
+	"github.com/pkg/errors"
+)
+
+// keepAliveInterval is how often an idle event stream gets a comment, which is
+// what notices clients that went away.
+var keepAliveInterval = 30 * time.Second
+
+// EventController implements the event resource.
+type EventController struct {
+	*goa.Controller
+	P   pinbase.PinProvider
+	Hub *pinbase.EventHub
+}
+
+// NewEventController creates a event controller.
+func NewEventController(service *goa.Service, P pinbase.PinProvider, Hub *pinbase.EventHub) *EventController {
+	return &EventController{Controller: service.NewController("EventController"), P: P, Hub: Hub}
+}
+
+// Party runs the party action.
+func (c *EventController) Party(ctx *app.PartyEventContext) error {
+	// EventController_Party: start_implement
+
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RoleReadOnly {
+		return ctx.Forbidden(forbidden(pinbase.RoleReadOnly))
+	}
+
+	p, err := ps.Party(pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ctx.NotFound()
+	}
+
+	sub := c.Hub.Subscribe(p.ID)
+
+	// EventController_Party: end_implement
+	return streamEvents(ctx.ResponseData, ctx.Request, sub)
+}
+
+// Stream runs the stream action.
+func (c *EventController) Stream(ctx *app.StreamEventContext) error {
+	// EventController_Stream: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	sub := c.Hub.Subscribe("")
+
+	// EventController_Stream: end_implement
+	return streamEvents(ctx.ResponseData, ctx.Request, sub)
+}
+
+// streamEvents sends the subscription's events as pin events until the
+// subscription or the request ends.
+func streamEvents(rd *goa.ResponseData, req *http.Request, sub *pinbase.Subscription) error {
+	defer sub.Close()
+
+	f, ok := rd.ResponseWriter.(http.Flusher)
+	if !ok {
+		return errors.New("the response can not be streamed")
+	}
+
+	rd.Header().Set("Content-Type", "text/event-stream")
+	rd.Header().Set("Cache-Control", "no-cache")
+	rd.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	t := time.NewTicker(keepAliveInterval)
+	defer t.Stop()
+
+	for {
+		var err error
+
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+
+			var b []byte
+			b, err = json.Marshal(pinbasePinEvent(e))
+			if err != nil {
+				return errors.Wrap(err, "encode pin event")
+			}
+
+			_, err = fmt.Fprintf(rd, "event: pin\ndata: %s\n\n", b)
+
+		case <-t.C:
+			_, err = fmt.Fprint(rd, ": keep-alive\n\n")
+
+		case <-req.Context().Done():
+			return nil
+		}
+
+		if err != nil {
+			// the client went away, there is nobody left to tell
+			return nil
+		}
+
+		f.Flush()
+	}
+}
+
+func pinbasePinEvent(e *pinbase.PinEvent) *app.PinbasePinEvent {
+	return &app.PinbasePinEvent{
+		Party:     string(e.Party),
+		Hash:      string(e.Pin),
+		Status:    e.Status.String(),
+		LastError: e.LastError,
+		Time:      e.Time,
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/goadesign/goa"
+)
+
+func TestStreamEvents(t *testing.T) {
+	hub := pinbase.NewEventHub()
+	sub := hub.Subscribe("foo")
+
+	at := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	hub.PublishPinEvent(&pinbase.PinEvent{Party: "foo", Pin: "a", Status: pinbase.PinPinned, Time: at})
+	hub.PublishPinEvent(&pinbase.PinEvent{Party: "bar", Pin: "b", Status: pinbase.PinPinned, Time: at})
+	hub.PublishPinEvent(&pinbase.PinEvent{Party: "foo", Pin: "c", Status: pinbase.PinError, LastError: "oops", Time: at})
+	hub.Close()
+
+	rw := httptest.NewRecorder()
+	rd := &goa.ResponseData{ResponseWriter: rw}
+
+	err := streamEvents(rd, httptest.NewRequest("GET", "/api/events/foo", nil), sub)
+	if err != nil {
+		t.Fatalf("failed to stream events: %+v", err)
+	}
+
+	if ct := rw.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("got content type %q", ct)
+	}
+
+	expected := strings.Join([]string{
+		`event: pin`,
+		`data: {"hash":"a","last-error":"","party":"foo","status":"pinned","time":"2017-06-01T12:00:00Z"}`,
+		``,
+		`event: pin`,
+		`data: {"hash":"c","last-error":"oops","party":"foo","status":"error","time":"2017-06-01T12:00:00Z"}`,
+		``,
+		``,
+	}, "\n")
+	if got := rw.Body.String(); got != expected {
+		t.Errorf("got stream\n%s\nexpected\n%s", got, expected)
+	}
+}
+
+// goneWriter is a response writer whose client went away.
+type goneWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (gw goneWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+var _ http.Flusher = goneWriter{}
+
+func TestStreamEventsClientGone(t *testing.T) {
+	hub := pinbase.NewEventHub()
+	defer hub.Close()
+
+	sub := hub.Subscribe("foo")
+	hub.PublishPinEvent(&pinbase.PinEvent{Party: "foo", Pin: "a", Status: pinbase.PinPinned})
+
+	rd := &goa.ResponseData{ResponseWriter: goneWriter{httptest.NewRecorder()}}
+
+	done := make(chan error)
+	go func() {
+		done <- streamEvents(rd, httptest.NewRequest("GET", "/api/events/foo", nil), sub)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("got error %+v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("kept streaming to a client that went away")
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/app"
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/goadesign/goa"
+)
+
+// KeyController implements the key resource.
+type KeyController struct {
+	*goa.Controller
+	K pinbase.KeyProvider
+}
+
+// NewKeyController creates a key controller.
+func NewKeyController(service *goa.Service, K pinbase.KeyProvider) *KeyController {
+	return &KeyController{Controller: service.NewController("KeyController"), K: K}
+}
+
+// Create runs the create action.
+func (c *KeyController) Create(ctx *app.CreateKeyContext) error {
+	// KeyController_Create: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	k, key, err := c.K.KeyService().CreateKey(&pinbase.KeyCreate{
+		Description: ctx.Payload.Description,
+		Admin:       ctx.Payload.Admin,
+	})
+	if err != nil {
+		return err
+	}
+
+	res := &app.PinbaseKeySecret{
+		ID:          k.ID,
+		Description: k.Description,
+		Created:     k.Created,
+		Admin:       k.Admin,
+		Key:         &key,
+	}
+
+	// KeyController_Create: end_implement
+	ctx.ResponseData.Header().Set("Location", app.KeyHref(k.ID))
+	return ctx.CreatedSecret(res)
+}
+
+// Delete runs the delete action.
+func (c *KeyController) Delete(ctx *app.DeleteKeyContext) error {
+	// KeyController_Delete: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	ks := c.K.KeyService()
+
+	k, err := ks.Key(ctx.KeyID)
+	if err != nil {
+		return err
+	}
+	if k == nil {
+		return ctx.NotFound()
+	}
+
+	err = ks.DeleteKey(ctx.KeyID)
+	if err != nil {
+		return err
+	}
+
+	// KeyController_Delete: end_implement
+	return nil
+}
+
+// List runs the list action.
+func (c *KeyController) List(ctx *app.ListKeyContext) error {
+	// KeyController_List: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	ks, err := c.K.KeyService().Keys()
+	if err != nil {
+		return err
+	}
+
+	res := app.PinbaseKeyCollection{}
+	for _, k := range ks {
+		res = append(res, pinbaseKey(k))
+	}
+
+	// KeyController_List: end_implement
+	return ctx.OK(res)
+}
+
+// Show runs the show action.
+func (c *KeyController) Show(ctx *app.ShowKeyContext) error {
+	// KeyController_Show: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	k, err := c.K.KeyService().Key(ctx.KeyID)
+	if err != nil {
+		return err
+	}
+	if k == nil {
+		return ctx.NotFound()
+	}
+
+	res := pinbaseKey(k)
+
+	// KeyController_Show: end_implement
+	return ctx.OK(res)
+}
+
+func pinbaseKey(k *pinbase.KeyView) *app.PinbaseKey {
+	return &app.PinbaseKey{
+		ID:          k.ID,
+		Description: k.Description,
+		Created:     k.Created,
+		Admin:       k.Admin,
+	}
+}
@@ -3,40 +3,156 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/app"
 	"github.com/apiarian/ipfs-pinbase/pinbase"
 	"github.com/apiarian/ipfs-pinbase/pinbase/bolt"
 	"github.com/apiarian/ipfs-pinbase/pinbase/ipfs"
+	"github.com/apiarian/ipfs-pinbase/pinbase/webhook"
 	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/middleware"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+var configFlags = NewConfigFlags(flag.CommandLine)
+
+var configFlag = flag.String(
+	"config",
+	"",
+	"YAML config file, also $PINBASE_CONFIG; the environment and flags override it",
+)
+
+var printConfigFlag = flag.Bool(
+	"print-config",
+	false,
+	"print the config the daemon would run with and exit",
+)
+
+var newKeyFlag = flag.String(
+	"new-key",
+	"",
+	"create an admin API key with the given description, print it and exit",
 )
 
 func main() {
-	P := bolt.NewClient("pinbase.db")
-	if err := P.Open(); err != nil {
-		log.Fatal("failed to open database connection:", err)
+	flag.Parse()
+
+	file := *configFlag
+	if file == "" {
+		file = os.Getenv("PINBASE_CONFIG")
 	}
-	defer P.Close()
 
-	I, err := ipfs.NewIPFSClient("127.0.0.1:5001")
+	config, err := configFlags.Load(file, os.Getenv)
 	if err != nil {
-		log.Fatalf("failed to create IPFS client")
-	}
-	go func(i *ipfs.IPFSClient) {
-		for {
-			if !i.Ping() {
-				log.Print("failed to ping ipfs node")
-			}
-			time.Sleep(3 * time.Second)
+		log.Fatal("failed to load the config: ", err)
+	}
+
+	if *printConfigFlag {
+		b, err := yaml.Marshal(config)
+		if err != nil {
+			log.Fatal("failed to print the config: ", err)
 		}
-	}(I)
+
+		fmt.Print(string(b))
+		return
+	}
+
+	err = run(config)
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+}
+
+// run serves the API until it gets SIGINT or SIGTERM. It then drains the
+// requests in flight, lets the pin pass and webhook deliveries in progress
+// finish and stops checking the nodes, cutting things short once the shutdown
+// timeout is up, and closes the database.
+func run(config *Config) error {
+	P := bolt.NewClient(config.Database)
+	err := P.Open()
+	if err != nil {
+		return errors.Wrap(err, "open database connection")
+	}
+	defer func() {
+		err := P.Close()
+		if err != nil {
+			log.Print("failed to close the database: ", err)
+		}
+	}()
+
+	if *newKeyFlag != "" {
+		_, key, err := P.KeyService().CreateKey(&pinbase.KeyCreate{
+			Description: *newKeyFlag,
+			Admin:       true,
+		})
+		if err != nil {
+			return errors.Wrap(err, "create the key")
+		}
+
+		fmt.Println(key)
+		return nil
+	}
+
+	NS := P.NodeService()
+	err = seedNodes(NS, config.Nodes)
+	if err != nil {
+		return errors.Wrap(err, "register the nodes")
+	}
+
+	N := ipfs.NewRegistry(NS)
+	P.Sizer = N
+	P.ResolveInterval = time.Duration(config.ResolveInterval)
+	P.NameGrace = time.Duration(config.NameGrace)
+
+	hub := pinbase.NewEventHub()
+	P.Events = hub
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	done := make(chan struct{})
+	var wg sync.WaitGroup
 
-	go pinbase.ManagePins(done, P.PinBackend(), I, 5*time.Second)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		N.Monitor(done, time.Duration(config.NodeInterval))
+	}()
+	go func() {
+		defer wg.Done()
+		pinbase.ManagePins(
+			ctx,
+			done,
+			P.PinBackend(),
+			N,
+			time.Duration(config.SweepInterval),
+			config.PinWorkers,
+			time.Duration(config.PinTimeout),
+		)
+	}()
+	go func() {
+		defer wg.Done()
+		pinbase.DeliverWebhooks(
+			ctx,
+			done,
+			P.WebhookQueue(),
+			&webhook.Sender{
+				Client: &http.Client{Timeout: time.Duration(config.WebhookTimeout)},
+			},
+			time.Duration(config.WebhookInterval),
+		)
+	}()
 
 	// Create service
 	service := goa.New("pinbase")
@@ -47,17 +163,106 @@ func main() {
 	service.Use(middleware.ErrorHandler(service, true))
 	service.Use(middleware.Recover())
 
+	app.UseAPIKeyMiddleware(service, NewAPIKeyMiddleware(P))
+
+	// Mount "archive" controller
+	c := NewArchiveController(service, P)
+	app.MountArchiveController(service, c)
+	// Mount "event" controller
+	c2 := NewEventController(service, P, hub)
+	app.MountEventController(service, c2)
+	// Mount "key" controller
+	c3 := NewKeyController(service, P)
+	app.MountKeyController(service, c3)
+	// Mount "node" controller
+	c4 := NewNodeController(service, P)
+	app.MountNodeController(service, c4)
 	// Mount "party" controller
-	c := NewPartyController(service, P)
-	app.MountPartyController(service, c)
+	c5 := NewPartyController(service, P)
+	app.MountPartyController(service, c5)
 	// Mount "pin" controller
-	c2 := NewPinController(service, P)
-	app.MountPinController(service, c2)
+	c6 := NewPinController(service, P, N)
+	app.MountPinController(service, c6)
+	// Mount "webhook" controller
+	c7 := NewWebhookController(service, P, P)
+	app.MountWebhookController(service, c7)
+
+	// Check the signatures of requests to the pins of parties bound to a
+	// public key before handing them to the service
+	server := &http.Server{
+		Addr:    config.Listen,
+		Handler: NewSignatureHandler(P, service.Mux),
+	}
+
+	// event streams only end when their subscriptions do, so they would hold
+	// up the shutdown otherwise
+	server.RegisterOnShutdown(hub.Close)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
 
 	// Start service
-	if err := service.ListenAndServe(":3000"); err != nil {
-		service.LogError("startup", "err", err)
+	served := make(chan error, 1)
+	go func() {
+		service.LogInfo("listen", "transport", "http", "addr", config.Listen)
+		served <- server.ListenAndServe()
+	}()
+
+	var serveErr error
+	select {
+	case s := <-signals:
+		service.LogInfo("shutdown", "signal", s.String())
+	case err := <-served:
+		serveErr = errors.Wrap(err, "serve")
+	}
+
+	sctx, scancel := context.WithTimeout(context.Background(), time.Duration(config.ShutdownTimeout))
+	defer scancel()
+
+	err = server.Shutdown(sctx)
+	if err != nil {
+		service.LogError("shutdown", "err", err)
 	}
 
 	close(done)
+
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-sctx.Done():
+		service.LogError("shutdown", "err", "timed out waiting for the pins, cancelling them")
+		cancel()
+		<-stopped
+	}
+
+	return serveErr
+}
+
+func seedNodes(ns pinbase.NodeService, nodes map[string]string) error {
+	registered, err := ns.Nodes()
+	if err != nil {
+		return errors.Wrap(err, "get registered nodes")
+	}
+
+	if len(registered) > 0 {
+		return nil
+	}
+
+	for name, addr := range nodes {
+		err := ns.CreateNode(&pinbase.NodeCreate{
+			ID:         name,
+			APIAddress: addr,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "register node %s", name)
+		}
+	}
+
+	return nil
 }
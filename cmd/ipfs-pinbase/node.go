@@ -0,0 +1,166 @@
+package main
+
+import (
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/app"
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/goadesign/goa"
+)
+
+// NodeController implements the node resource.
+type NodeController struct {
+	*goa.Controller
+	N pinbase.NodeProvider
+}
+
+// NewNodeController creates a node controller.
+func NewNodeController(service *goa.Service, N pinbase.NodeProvider) *NodeController {
+	return &NodeController{Controller: service.NewController("NodeController"), N: N}
+}
+
+// Create runs the create action.
+func (c *NodeController) Create(ctx *app.CreateNodeContext) error {
+	// NodeController_Create: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	err := c.N.NodeService().CreateNode(&pinbase.NodeCreate{
+		ID:         ctx.Payload.Name,
+		APIAddress: ctx.Payload.APIAddress,
+	})
+	if err != nil {
+		return err
+	}
+
+	// NodeController_Create: end_implement
+	ctx.ResponseData.Header().Set("Location", app.NodeHref(ctx.Payload.Name))
+	return ctx.Created()
+}
+
+// Delete runs the delete action.
+func (c *NodeController) Delete(ctx *app.DeleteNodeContext) error {
+	// NodeController_Delete: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	ns := c.N.NodeService()
+
+	n, err := ns.Node(ctx.NodeName)
+	if err != nil {
+		return err
+	}
+	if n == nil {
+		return ctx.NotFound()
+	}
+
+	err = ns.DeleteNode(ctx.NodeName)
+	if err != nil {
+		return err
+	}
+
+	// NodeController_Delete: end_implement
+	return nil
+}
+
+// List runs the list action.
+func (c *NodeController) List(ctx *app.ListNodeContext) error {
+	// NodeController_List: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	ns, err := c.N.NodeService().Nodes()
+	if err != nil {
+		return err
+	}
+
+	res := app.PinbaseNodeCollection{}
+	for _, n := range ns {
+		res = append(res, pinbaseNode(n))
+	}
+
+	// NodeController_List: end_implement
+	return ctx.OK(res)
+}
+
+// Show runs the show action.
+func (c *NodeController) Show(ctx *app.ShowNodeContext) error {
+	// NodeController_Show: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	n, err := c.N.NodeService().Node(ctx.NodeName)
+	if err != nil {
+		return err
+	}
+	if n == nil {
+		return ctx.NotFound()
+	}
+
+	res := pinbaseNode(n)
+
+	// NodeController_Show: end_implement
+	return ctx.OK(res)
+}
+
+// Update runs the update action.
+func (c *NodeController) Update(ctx *app.UpdateNodeContext) error {
+	// NodeController_Update: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	ns := c.N.NodeService()
+
+	n, err := ns.Node(ctx.NodeName)
+	if err != nil {
+		return err
+	}
+	if n == nil {
+		return ctx.NotFound()
+	}
+
+	err = ns.UpdateNode(
+		ctx.NodeName,
+		&pinbase.NodeEdit{
+			APIAddress: ctx.Payload.APIAddress,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err = ns.Node(ctx.NodeName)
+	if err != nil {
+		return err
+	}
+
+	res := pinbaseNode(n)
+
+	// NodeController_Update: end_implement
+	return ctx.OK(res)
+}
+
+func pinbaseNode(n *pinbase.NodeView) *app.PinbaseNode {
+	res := &app.PinbaseNode{
+		Name:       n.ID,
+		APIAddress: n.APIAddress,
+		Reachable:  n.Reachable,
+		PinCount:   n.PinCount,
+		RepoSize:   int(n.RepoSize),
+	}
+
+	if !n.LastSeen.IsZero() {
+		lastSeen := n.LastSeen
+		res.LastSeen = &lastSeen
+	}
+
+	return res
+}
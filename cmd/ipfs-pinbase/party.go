@@ -1,6 +1,9 @@
 package main
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
+
 	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/app"
 	"github.com/apiarian/ipfs-pinbase/pinbase"
 	"github.com/goadesign/goa"
@@ -21,10 +24,29 @@ func NewPartyController(service *goa.Service, P pinbase.PinProvider) *PartyContr
 func (c *PartyController) Create(ctx *app.CreatePartyContext) error {
 	// PartyController_Create: start_implement
 
-	err := c.P.PinService().CreateParty(&pinbase.PartyCreate{
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	pc := &pinbase.PartyCreate{
 		ID:          pinbase.Hash(ctx.Payload.Hash),
 		Description: ctx.Payload.Description,
-	})
+	}
+	if ctx.Payload.MaxPins != nil {
+		pc.Quota.MaxPins = *ctx.Payload.MaxPins
+	}
+	if ctx.Payload.MaxBytes != nil {
+		pc.Quota.MaxBytes = int64(*ctx.Payload.MaxBytes)
+	}
+	if ctx.Payload.PublicKey != nil {
+		k, err := base64.StdEncoding.DecodeString(*ctx.Payload.PublicKey)
+		if err != nil || len(k) != ed25519.PublicKeySize {
+			return ctx.BadRequest(goa.ErrBadRequest("public-key is not a base64 ed25519 public key"))
+		}
+		pc.PublicKey = k
+	}
+
+	err := c.P.PinService().CreateParty(pc)
 	if err != nil {
 		return err
 	}
@@ -38,6 +60,10 @@ func (c *PartyController) Create(ctx *app.CreatePartyContext) error {
 func (c *PartyController) Delete(ctx *app.DeletePartyContext) error {
 	// PartyController_Delete: start_implement
 
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
 	err := c.P.PinService().DeleteParty(pinbase.Hash(ctx.PartyHash))
 	if err != nil {
 		return err
@@ -56,12 +82,16 @@ func (c *PartyController) List(ctx *app.ListPartyContext) error {
 		return err
 	}
 
+	k := ContextKey(ctx)
+
 	res := app.PinbasePartyCollection{}
 	for _, p := range ps {
-		res = append(res, &app.PinbaseParty{
-			Hash:        string(p.ID),
-			Description: p.Description,
-		})
+		// keys only get to see the parties they have a role on
+		if k == nil || (!k.Admin && p.Grants[k.ID] == pinbase.RoleNone) {
+			continue
+		}
+
+		res = append(res, pinbaseParty(p))
 	}
 
 	// PartyController_List: end_implement
@@ -72,46 +102,136 @@ func (c *PartyController) List(ctx *app.ListPartyContext) error {
 func (c *PartyController) Show(ctx *app.ShowPartyContext) error {
 	// PartyController_Show: start_implement
 
-	p, err := c.P.PinService().Party(pinbase.Hash(ctx.PartyHash))
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
 	if err != nil {
 		return err
 	}
+	if r < pinbase.RoleReadOnly {
+		return ctx.Forbidden(forbidden(pinbase.RoleReadOnly))
+	}
 
-	res := &app.PinbaseParty{
-		Hash:        string(p.ID),
-		Description: p.Description,
+	p, err := ps.Party(pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
 	}
+	if p == nil {
+		return ctx.NotFound()
+	}
+
+	res := pinbaseParty(p)
 
 	// PartyController_Show: end_implement
 	return ctx.OK(res)
 }
 
+// Grant runs the grant action.
+func (c *PartyController) Grant(ctx *app.GrantPartyContext) error {
+	// PartyController_Grant: start_implement
+
+	if !isAdmin(ctx) {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
+
+	r, err := pinbase.ParseRole(ctx.Payload.Role)
+	if err != nil {
+		return ctx.BadRequest(goa.ErrBadRequest(err))
+	}
+
+	ps := c.P.PinService()
+
+	p, err := ps.Party(pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ctx.NotFound()
+	}
+
+	err = ps.GrantParty(pinbase.Hash(ctx.PartyHash), ctx.KeyID, r)
+	if err != nil {
+		return err
+	}
+
+	// PartyController_Grant: end_implement
+	return nil
+}
+
 // Update runs the update action.
 func (c *PartyController) Update(ctx *app.UpdatePartyContext) error {
 	// PartyController_Update: start_implement
 
 	ps := c.P.PinService()
 
-	err := ps.UpdateParty(
-		pinbase.Hash(ctx.PartyHash),
-		&pinbase.PartyEdit{
-			Description: *ctx.Payload.Description,
-		},
-	)
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
 	if err != nil {
 		return err
 	}
+	if r < pinbase.RolePartyOwner {
+		return ctx.Forbidden(forbidden(pinbase.RolePartyOwner))
+	}
+
+	// owners do not get to raise their own quotas
+	if (ctx.Payload.MaxPins != nil || ctx.Payload.MaxBytes != nil) && r < pinbase.RoleAdmin {
+		return ctx.Forbidden(forbidden(pinbase.RoleAdmin))
+	}
 
 	p, err := ps.Party(pinbase.Hash(ctx.PartyHash))
 	if err != nil {
 		return err
 	}
+	if p == nil {
+		return ctx.NotFound()
+	}
 
-	res := &app.PinbaseParty{
-		Hash:        string(p.ID),
+	// leave out whatever the payload does not mention
+	pe := &pinbase.PartyEdit{
 		Description: p.Description,
+		Quota:       p.Quota,
+	}
+	if ctx.Payload.Description != nil {
+		pe.Description = *ctx.Payload.Description
+	}
+	if ctx.Payload.MaxPins != nil {
+		pe.Quota.MaxPins = *ctx.Payload.MaxPins
+	}
+	if ctx.Payload.MaxBytes != nil {
+		pe.Quota.MaxBytes = int64(*ctx.Payload.MaxBytes)
 	}
 
+	err = ps.UpdateParty(pinbase.Hash(ctx.PartyHash), pe)
+	if err != nil {
+		return err
+	}
+
+	p, err = ps.Party(pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+
+	res := pinbaseParty(p)
+
 	// PartyController_Update: end_implement
 	return ctx.OK(res)
 }
+
+func pinbaseParty(p *pinbase.PartyView) *app.PinbaseParty {
+	var k *string
+	if p.PublicKey != nil {
+		s := base64.StdEncoding.EncodeToString(p.PublicKey)
+		k = &s
+	}
+
+	return &app.PinbaseParty{
+		Hash:        string(p.ID),
+		Description: p.Description,
+		MaxPins:     p.Quota.MaxPins,
+		MaxBytes:    int(p.Quota.MaxBytes),
+		UsedPins:    p.Usage.Pins,
+		UsedBytes:   int(p.Usage.Bytes),
+		PinnedPins:  p.Usage.PinnedPins,
+		PinnedBytes: int(p.Usage.PinnedBytes),
+		PublicKey:   k,
+	}
+}
@@ -1,40 +1,125 @@
 package main
 
 import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/app"
 	"github.com/apiarian/ipfs-pinbase/pinbase"
 	"github.com/goadesign/goa"
+	"github.com/pkg/errors"
 )
 
-// PinController implements the pin resource.
+// ErrQuotaExceeded is sent back for pins that would take a party over its
+// quota.
+var ErrQuotaExceeded = goa.NewErrorClass("quota_exceeded", 403)
+
+// PinController implements the pin resource. Pins of IPFS paths are resolved
+// to the hash they point at with R when they are created.
 type PinController struct {
 	*goa.Controller
 	P pinbase.PinProvider
+	R pinbase.PathResolver
 }
 
 // NewPinController creates a pin controller.
-func NewPinController(service *goa.Service, P pinbase.PinProvider) *PinController {
-	return &PinController{Controller: service.NewController("PinController"), P: P}
+func NewPinController(service *goa.Service, P pinbase.PinProvider, R pinbase.PathResolver) *PinController {
+	return &PinController{Controller: service.NewController("PinController"), P: P, R: R}
 }
 
 // Create runs the create action.
 func (c *PinController) Create(ctx *app.CreatePinContext) error {
 	// PinController_Create: start_implement
 
-	err := c.P.PinService().CreatePin(
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RolePartyOwner {
+		return ctx.Forbidden(forbidden(pinbase.RolePartyOwner))
+	}
+
+	m, err := pinbase.ParsePinMode(ctx.Payload.Mode)
+	if err != nil {
+		return err
+	}
+
+	k := pinbase.PinKindHash
+	if ctx.Payload.Kind != nil {
+		k, err = pinbase.ParsePinKind(*ctx.Payload.Kind)
+		if err != nil {
+			return err
+		}
+	}
+
+	id := ctx.Payload.Hash
+	var path string
+	switch k {
+	case pinbase.PinKindName:
+		id, err = pinName(id)
+		if err != nil {
+			return ctx.BadRequest(goa.ErrBadRequest(err))
+		}
+
+	case pinbase.PinKindHash:
+		var h pinbase.Hash
+		h, path, err = pinPath(id)
+		if err != nil {
+			return ctx.BadRequest(goa.ErrBadRequest(err))
+		}
+
+		if path != "" {
+			if c.R == nil {
+				return errors.New("no path resolver available")
+			}
+
+			h, err = c.R.ResolvePath(ctx, path)
+			if err != nil {
+				return ctx.BadRequest(goa.ErrBadRequest(errors.Wrapf(err, "resolve %s", path)))
+			}
+		}
+		id = string(h.Canonical())
+	}
+
+	expiresAt, err := pinExpiry(ctx.Payload.ExpiresAt, ctx.Payload.TTL, time.Now())
+	if err != nil {
+		return ctx.BadRequest(goa.ErrBadRequest(err))
+	}
+
+	notBefore, notAfter, err := pinWindow(ctx.Payload.NotBefore, ctx.Payload.NotAfter)
+	if err != nil {
+		return ctx.BadRequest(goa.ErrBadRequest(err))
+	}
+
+	err = ps.CreatePin(
 		pinbase.Hash(ctx.PartyHash),
 		&pinbase.PinCreate{
-			ID:         pinbase.Hash(ctx.Payload.Hash),
-			Aliases:    ctx.Payload.Aliases,
-			WantPinned: ctx.Payload.WantPinned,
+			ID:          pinbase.Hash(id),
+			Kind:        k,
+			Path:        path,
+			Aliases:     ctx.Payload.Aliases,
+			WantPinned:  ctx.Payload.WantPinned,
+			Mode:        m,
+			Replication: ctx.Payload.Replication,
+			ExpiresAt:   expiresAt,
+			NotBefore:   notBefore,
+			NotAfter:    notAfter,
+			By:          keyID(ctx),
 		},
 	)
+	if errors.Cause(err) == pinbase.ErrQuotaExceeded {
+		return ctx.Forbidden(ErrQuotaExceeded(err))
+	}
 	if err != nil {
 		return err
 	}
 
 	// PinController_Create: end_implement
-	ctx.ResponseData.Header().Set("Location", app.PinHref(ctx.PartyHash, ctx.Payload.Hash))
+	ctx.ResponseData.Header().Set("Location", app.PinHref(ctx.PartyHash, id))
 	return ctx.Created()
 }
 
@@ -42,9 +127,25 @@ func (c *PinController) Create(ctx *app.CreatePinContext) error {
 func (c *PinController) Delete(ctx *app.DeletePinContext) error {
 	// PinController_Delete: start_implement
 
-	err := c.P.PinService().DeletePin(
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RolePartyOwner {
+		return ctx.Forbidden(forbidden(pinbase.RolePartyOwner))
+	}
+
+	err = checkPinHash(ctx.PinHash)
+	if err != nil {
+		return ctx.BadRequest(goa.ErrBadRequest(err))
+	}
+
+	err = ps.DeletePin(
 		pinbase.Hash(ctx.PartyHash),
 		pinbase.Hash(ctx.PinHash),
+		keyID(ctx),
 	)
 	if err != nil {
 		return err
@@ -54,10 +155,62 @@ func (c *PinController) Delete(ctx *app.DeletePinContext) error {
 	return nil
 }
 
+// History runs the history action.
+func (c *PinController) History(ctx *app.HistoryPinContext) error {
+	// PinController_History: start_implement
+
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RoleReadOnly {
+		return ctx.Forbidden(forbidden(pinbase.RoleReadOnly))
+	}
+
+	err = checkPinHash(ctx.PinHash)
+	if err != nil {
+		return ctx.BadRequest(goa.ErrBadRequest(err))
+	}
+
+	p, err := ps.Party(pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ctx.NotFound()
+	}
+
+	hs, err := ps.PinHistory(p.ID, pinbase.Hash(ctx.PinHash))
+	if err != nil {
+		return err
+	}
+	if len(hs) == 0 {
+		return ctx.NotFound()
+	}
+
+	res := app.PinbasePinHistoryCollection{}
+	for _, h := range hs {
+		res = append(res, pinbasePinHistory(h))
+	}
+
+	// PinController_History: end_implement
+	return ctx.OK(res)
+}
+
 // List runs the list action.
 func (c *PinController) List(ctx *app.ListPinContext) error {
 	// PinController_List: start_implement
 
+	r, err := partyRole(ctx, c.P.PinService(), pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RoleReadOnly {
+		return ctx.Forbidden(forbidden(pinbase.RoleReadOnly))
+	}
+
 	ps, err := c.P.PinService().Pins(pinbase.Hash(ctx.PartyHash))
 	if err != nil {
 		return err
@@ -65,21 +218,128 @@ func (c *PinController) List(ctx *app.ListPinContext) error {
 
 	res := app.PinbasePinCollection{}
 	for _, p := range ps {
-		var e string
-		if p.LastError != nil {
-			e = p.LastError.Error()
+		res = append(res, pinbasePin(p))
+	}
+
+	// PinController_List: end_implement
+	return ctx.OK(res)
+}
+
+// Reset runs the reset action.
+func (c *PinController) Reset(ctx *app.ResetPinContext) error {
+	// PinController_Reset: start_implement
+
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RolePartyOwner {
+		return ctx.Forbidden(forbidden(pinbase.RolePartyOwner))
+	}
+
+	err = checkPinHash(ctx.PinHash)
+	if err != nil {
+		return ctx.BadRequest(goa.ErrBadRequest(err))
+	}
+
+	p, err := ps.Pin(
+		pinbase.Hash(ctx.PartyHash),
+		pinbase.Hash(ctx.PinHash),
+	)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ctx.NotFound()
+	}
+
+	err = ps.ResetPin(
+		pinbase.Hash(ctx.PartyHash),
+		pinbase.Hash(ctx.PinHash),
+		keyID(ctx),
+	)
+	if err != nil {
+		return err
+	}
+
+	p, err = ps.Pin(
+		pinbase.Hash(ctx.PartyHash),
+		pinbase.Hash(ctx.PinHash),
+	)
+	if err != nil {
+		return err
+	}
+
+	res := pinbasePin(p)
+
+	// PinController_Reset: end_implement
+	return ctx.OK(res)
+}
+
+// Renew runs the renew action.
+func (c *PinController) Renew(ctx *app.RenewPinContext) error {
+	// PinController_Renew: start_implement
+
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RolePartyOwner {
+		return ctx.Forbidden(forbidden(pinbase.RolePartyOwner))
+	}
+
+	err = checkPinHash(ctx.PinHash)
+	if err != nil {
+		return ctx.BadRequest(goa.ErrBadRequest(err))
+	}
+
+	var expiresAt time.Time
+	if ctx.Payload != nil {
+		expiresAt, err = pinExpiry(ctx.Payload.ExpiresAt, ctx.Payload.TTL, time.Now())
+		if err != nil {
+			return ctx.BadRequest(goa.ErrBadRequest(err))
 		}
+	}
 
-		res = append(res, &app.PinbasePin{
-			Hash:       string(p.ID),
-			Aliases:    p.Aliases,
-			WantPinned: p.WantPinned,
-			Status:     p.Status.String(),
-			LastError:  e,
-		})
+	p, err := ps.Pin(
+		pinbase.Hash(ctx.PartyHash),
+		pinbase.Hash(ctx.PinHash),
+	)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ctx.NotFound()
 	}
 
-	// PinController_List: end_implement
+	err = ps.RenewPin(
+		pinbase.Hash(ctx.PartyHash),
+		pinbase.Hash(ctx.PinHash),
+		expiresAt,
+		keyID(ctx),
+	)
+	if errors.Cause(err) == pinbase.ErrQuotaExceeded {
+		return ctx.Forbidden(ErrQuotaExceeded(err))
+	}
+	if err != nil {
+		return err
+	}
+
+	p, err = ps.Pin(
+		pinbase.Hash(ctx.PartyHash),
+		pinbase.Hash(ctx.PinHash),
+	)
+	if err != nil {
+		return err
+	}
+
+	res := pinbasePin(p)
+
+	// PinController_Renew: end_implement
 	return ctx.OK(res)
 }
 
@@ -87,26 +347,33 @@ func (c *PinController) List(ctx *app.ListPinContext) error {
 func (c *PinController) Show(ctx *app.ShowPinContext) error {
 	// PinController_Show: start_implement
 
-	p, err := c.P.PinService().Pin(
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RoleReadOnly {
+		return ctx.Forbidden(forbidden(pinbase.RoleReadOnly))
+	}
+
+	err = checkPinHash(ctx.PinHash)
+	if err != nil {
+		return ctx.BadRequest(goa.ErrBadRequest(err))
+	}
+
+	p, err := ps.Pin(
 		pinbase.Hash(ctx.PartyHash),
 		pinbase.Hash(ctx.PinHash),
 	)
 	if err != nil {
 		return err
 	}
-
-	var e string
-	if p.LastError != nil {
-		e = p.LastError.Error()
+	if p == nil {
+		return ctx.NotFound()
 	}
 
-	res := &app.PinbasePin{
-		Hash:       string(p.ID),
-		Aliases:    p.Aliases,
-		WantPinned: p.WantPinned,
-		Status:     p.Status.String(),
-		LastError:  e,
-	}
+	res := pinbasePin(p)
 
 	// PinController_Show: end_implement
 	return ctx.OK(res)
@@ -118,19 +385,49 @@ func (c *PinController) Update(ctx *app.UpdatePinContext) error {
 
 	ps := c.P.PinService()
 
-	err := ps.UpdatePin(
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RolePartyOwner {
+		return ctx.Forbidden(forbidden(pinbase.RolePartyOwner))
+	}
+
+	err = checkPinHash(ctx.PinHash)
+	if err != nil {
+		return ctx.BadRequest(goa.ErrBadRequest(err))
+	}
+
+	p, err := ps.Pin(
 		pinbase.Hash(ctx.PartyHash),
 		pinbase.Hash(ctx.PinHash),
-		&pinbase.PinEdit{
-			Aliases:    ctx.Payload.Aliases,
-			WantPinned: *ctx.Payload.WantPinned,
-		},
 	)
 	if err != nil {
 		return err
 	}
+	if p == nil {
+		return ctx.NotFound()
+	}
 
-	p, err := ps.Pin(
+	pe, err := pinEdit(p, ctx.Payload, time.Now())
+	if err != nil {
+		return ctx.BadRequest(goa.ErrBadRequest(err))
+	}
+	pe.By = keyID(ctx)
+
+	err = ps.UpdatePin(
+		pinbase.Hash(ctx.PartyHash),
+		pinbase.Hash(ctx.PinHash),
+		pe,
+	)
+	if errors.Cause(err) == pinbase.ErrQuotaExceeded {
+		return ctx.Forbidden(ErrQuotaExceeded(err))
+	}
+	if err != nil {
+		return err
+	}
+
+	p, err = ps.Pin(
 		pinbase.Hash(ctx.PartyHash),
 		pinbase.Hash(ctx.PinHash),
 	)
@@ -138,19 +435,266 @@ func (c *PinController) Update(ctx *app.UpdatePinContext) error {
 		return err
 	}
 
+	res := pinbasePin(p)
+
+	// PinController_Update: end_implement
+	return ctx.OK(res)
+}
+
+func pinbasePin(p *pinbase.PinView) *app.PinbasePin {
 	var e string
 	if p.LastError != nil {
 		e = p.LastError.Error()
 	}
 
-	res := &app.PinbasePin{
-		Hash:       string(p.ID),
-		Aliases:    p.Aliases,
-		WantPinned: p.WantPinned,
-		Status:     p.Status.String(),
-		LastError:  e,
+	replication := p.Replication
+	if replication < 1 {
+		replication = 1
 	}
 
-	// PinController_Update: end_implement
-	return ctx.OK(res)
+	var path *string
+	if p.Path != "" {
+		path = &p.Path
+	}
+
+	var target *string
+	if p.Target != "" {
+		t := string(p.Target)
+		target = &t
+	}
+
+	var resolutions []*app.PinResolution
+	for _, r := range p.Resolutions {
+		resolutions = append(resolutions, &app.PinResolution{
+			Time:   r.Time,
+			Target: string(r.Target),
+			Error:  r.Error,
+		})
+	}
+
+	var expiresAt, notBefore, notAfter *time.Time
+	if !p.ExpiresAt.IsZero() {
+		expiresAt = &p.ExpiresAt
+	}
+	if !p.NotBefore.IsZero() {
+		notBefore = &p.NotBefore
+	}
+	if !p.NotAfter.IsZero() {
+		notAfter = &p.NotAfter
+	}
+
+	now := time.Now()
+
+	nodes := []*app.PinNode{}
+	for name, n := range p.Nodes {
+		var ne string
+		if n.LastError != nil {
+			ne = n.LastError.Error()
+		}
+
+		nodes = append(nodes, &app.PinNode{
+			Node:      name,
+			Status:    n.Status.String(),
+			LastError: ne,
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Node < nodes[j].Node })
+
+	return &app.PinbasePin{
+		Hash:          string(p.ID),
+		Aliases:       p.Aliases,
+		WantPinned:    p.WantPinned,
+		Mode:          p.Mode.String(),
+		Replication:   replication,
+		Status:        p.Status.String(),
+		LastError:     e,
+		BlocksFetched: int(p.Progress.Blocks),
+		BytesFetched:  int(p.Progress.Bytes),
+		Nodes:         nodes,
+		Size:          int(p.Size),
+		ExpiresAt:     expiresAt,
+		Expired:       !p.WantPinned && p.Expired(now),
+		NotBefore:     notBefore,
+		NotAfter:      notAfter,
+		InWindow:      p.InWindow(now),
+		Kind:          p.Kind.String(),
+		Path:          path,
+		Target:        target,
+		Resolutions:   resolutions,
+	}
+}
+
+// pinEdit works out the edit an update payload makes to the stored pin p,
+// leaving out whatever the payload does not mention.
+func pinEdit(p *pinbase.PinView, pl *app.PinUpdatePayload, now time.Time) (*pinbase.PinEdit, error) {
+	pe := &pinbase.PinEdit{
+		Aliases:     p.Aliases,
+		WantPinned:  p.WantPinned,
+		Mode:        p.Mode,
+		Replication: p.Replication,
+		ExpiresAt:   p.ExpiresAt,
+	}
+	if pl.Aliases != nil {
+		pe.Aliases = pl.Aliases
+	}
+	if pl.WantPinned != nil {
+		pe.WantPinned = *pl.WantPinned
+	}
+	if pl.Mode != nil {
+		m, err := pinbase.ParsePinMode(*pl.Mode)
+		if err != nil {
+			return nil, err
+		}
+		pe.Mode = m
+	}
+	if pl.Replication != nil {
+		pe.Replication = *pl.Replication
+	}
+	if pl.ExpiresAt != nil || pl.TTL != nil {
+		expiresAt, err := pinExpiry(pl.ExpiresAt, pl.TTL, now)
+		if err != nil {
+			return nil, err
+		}
+		pe.ExpiresAt = expiresAt
+	}
+
+	notBefore, notAfter := pl.NotBefore, pl.NotAfter
+	if notBefore == nil {
+		notBefore = &p.NotBefore
+	}
+	if notAfter == nil {
+		notAfter = &p.NotAfter
+	}
+	var err error
+	pe.NotBefore, pe.NotAfter, err = pinWindow(notBefore, notAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	return pe, nil
+}
+
+// pinExpiry works out when a pin given the expires-at or ttl of a payload
+// expires, a zero time meaning never. A ttl is a duration such as "720h", a
+// number of days such as "30d" or "never".
+func pinExpiry(expiresAt *time.Time, ttl *string, now time.Time) (time.Time, error) {
+	switch {
+	case expiresAt != nil && ttl != nil:
+		return time.Time{}, errors.New("give either expires-at or ttl, not both")
+
+	case expiresAt != nil:
+		if !expiresAt.After(now) {
+			return time.Time{}, errors.New("expires-at is not in the future")
+		}
+		return expiresAt.UTC(), nil
+
+	case ttl != nil && *ttl == "never":
+		return time.Time{}, nil
+
+	case ttl != nil:
+		var d time.Duration
+		var err error
+		if days := strings.TrimSuffix(*ttl, "d"); days != *ttl {
+			var n int
+			n, err = strconv.Atoi(days)
+			d = time.Duration(n) * 24 * time.Hour
+		} else {
+			d, err = time.ParseDuration(*ttl)
+		}
+		if err != nil || d <= 0 {
+			return time.Time{}, errors.Errorf("ttl %q is not a positive duration", *ttl)
+		}
+		return now.Add(d).UTC(), nil
+	}
+
+	return time.Time{}, nil
+}
+
+// pinName checks the IPNS name or DNSLink domain of a name pin, which may be
+// given as an /ipns/ path, and returns it in canonical form without the
+// prefix. IPNS names are the CIDs of keys, domains need at least one dot.
+func pinName(s string) (string, error) {
+	name := strings.TrimPrefix(s, "/ipns/")
+	if strings.Contains(name, "/") {
+		return "", errors.Errorf("%q is not an IPNS name or DNSLink domain", s)
+	}
+
+	if _, err := pinbase.ParseHash(name); err != nil && !strings.Contains(name, ".") {
+		return "", errors.Errorf("%q is not an IPNS name or DNSLink domain", s)
+	}
+
+	return pinbase.CanonicalName(name), nil
+}
+
+// checkPinHash checks the pinHash param of a request, which is the hash of a
+// hash pin or the IPNS name or DNSLink domain of a name pin.
+func checkPinHash(s string) error {
+	_, err := pinbase.ParseHash(s)
+	if err != nil && !strings.Contains(s, ".") {
+		return errors.Errorf("%q is not a hash, IPNS name or DNSLink domain", s)
+	}
+
+	return nil
+}
+
+// pinPath checks the hash of a hash pin, which may be given as an /ipfs/ path
+// and may go on to a path below the hash. It returns the hash in canonical
+// form, and the path in the form /ipfs/<hash>/sub/path if there is one.
+func pinPath(s string) (pinbase.Hash, string, error) {
+	segments := strings.Split(strings.TrimPrefix(s, "/ipfs/"), "/")
+	h, err := pinbase.ParseHash(segments[0])
+	if err != nil {
+		return "", "", errors.Errorf("%q is not a hash or an IPFS path", s)
+	}
+
+	var rest []string
+	for _, seg := range segments[1:] {
+		switch seg {
+		case "":
+			continue
+		case ".", "..":
+			return "", "", errors.Errorf("%q is not a hash or an IPFS path", s)
+		}
+		rest = append(rest, seg)
+	}
+
+	if len(rest) == 0 {
+		return h, "", nil
+	}
+
+	return h, "/ipfs/" + string(h) + "/" + strings.Join(rest, "/"), nil
+}
+
+// pinWindow works out the window of a pin given the not-before and not-after
+// of a payload, zero times leaving it open.
+func pinWindow(notBefore, notAfter *time.Time) (time.Time, time.Time, error) {
+	var nb, na time.Time
+	if notBefore != nil {
+		nb = notBefore.UTC()
+	}
+	if notAfter != nil {
+		na = notAfter.UTC()
+	}
+
+	if !nb.IsZero() && !na.IsZero() && !na.After(nb) {
+		return time.Time{}, time.Time{}, errors.New("not-after is not after not-before")
+	}
+
+	return nb, na, nil
+}
+
+func pinbasePinHistory(h *pinbase.PinHistoryEntry) *app.PinbasePinHistory {
+	changes := h.Changes
+	if changes == nil {
+		changes = []string{}
+	}
+
+	return &app.PinbasePinHistory{
+		Time:      h.Time,
+		Change:    h.Change.String(),
+		By:        h.By,
+		Status:    h.Status.String(),
+		LastError: h.LastError,
+		Changes:   changes,
+	}
 }
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/app"
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+)
+
+func TestPinExpiry(t *testing.T) {
+	now := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+	str := func(s string) *string { return &s }
+
+	for _, tc := range []struct {
+		name      string
+		expiresAt *time.Time
+		ttl       *string
+		expected  time.Time
+		err       bool
+	}{
+		{"never", nil, nil, time.Time{}, false},
+		{"expires-at", &at, nil, at, false},
+		{"expires-at in the past", &past, nil, time.Time{}, true},
+		{"ttl", nil, str("90m"), now.Add(90 * time.Minute), false},
+		{"ttl in days", nil, str("30d"), now.Add(30 * 24 * time.Hour), false},
+		{"no ttl", nil, str("never"), time.Time{}, false},
+		{"negative ttl", nil, str("-1h"), time.Time{}, true},
+		{"bad ttl", nil, str("a while"), time.Time{}, true},
+		{"bad days", nil, str("xd"), time.Time{}, true},
+		{"both", &at, str("1h"), time.Time{}, true},
+	} {
+		got, err := pinExpiry(tc.expiresAt, tc.ttl, now)
+		if (err != nil) != tc.err {
+			t.Errorf("%s: got error %v", tc.name, err)
+			continue
+		}
+		if !got.Equal(tc.expected) {
+			t.Errorf("%s: got %s, expected %s", tc.name, got, tc.expected)
+		}
+	}
+}
+
+func TestPinWindow(t *testing.T) {
+	start := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	for _, tc := range []struct {
+		name                string
+		notBefore, notAfter *time.Time
+		err                 bool
+	}{
+		{"open", nil, nil, false},
+		{"not-before", &start, nil, false},
+		{"not-after", nil, &end, false},
+		{"both", &start, &end, false},
+		{"backwards", &end, &start, true},
+		{"empty", &start, &start, true},
+	} {
+		nb, na, err := pinWindow(tc.notBefore, tc.notAfter)
+		if (err != nil) != tc.err {
+			t.Errorf("%s: got error %v", tc.name, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if (tc.notBefore == nil) != nb.IsZero() || (tc.notAfter == nil) != na.IsZero() {
+			t.Errorf("%s: got window %s to %s", tc.name, nb, na)
+		}
+	}
+}
+
+func TestPinEdit(t *testing.T) {
+	now := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	stored := &pinbase.PinView{
+		Aliases:     []string{"old"},
+		WantPinned:  true,
+		Mode:        pinbase.PinDirect,
+		Replication: 3,
+		ExpiresAt:   now.Add(time.Hour),
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(2 * time.Hour),
+	}
+
+	pe, err := pinEdit(stored, &app.PinUpdatePayload{Aliases: []string{"new"}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pe.Aliases) != 1 || pe.Aliases[0] != "new" {
+		t.Errorf("got aliases %v, expected [new]", pe.Aliases)
+	}
+	if !pe.WantPinned || pe.Mode != pinbase.PinDirect || pe.Replication != 3 {
+		t.Errorf("got want-pinned %t, mode %s and replication %d, expected them left as they were", pe.WantPinned, pe.Mode, pe.Replication)
+	}
+	if !pe.ExpiresAt.Equal(stored.ExpiresAt) {
+		t.Errorf("got expiry %s, expected %s", pe.ExpiresAt, stored.ExpiresAt)
+	}
+	if !pe.NotBefore.Equal(stored.NotBefore) || !pe.NotAfter.Equal(stored.NotAfter) {
+		t.Errorf("got window %s to %s, expected %s to %s", pe.NotBefore, pe.NotAfter, stored.NotBefore, stored.NotAfter)
+	}
+
+	never := "never"
+	var zero time.Time
+	pe, err = pinEdit(stored, &app.PinUpdatePayload{TTL: &never, NotAfter: &zero}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pe.ExpiresAt.IsZero() {
+		t.Errorf("got expiry %s, expected none", pe.ExpiresAt)
+	}
+	if !pe.NotBefore.Equal(stored.NotBefore) || !pe.NotAfter.IsZero() {
+		t.Errorf("got window %s to %s, expected %s onwards", pe.NotBefore, pe.NotAfter, stored.NotBefore)
+	}
+
+	early := now.Add(-2 * time.Hour)
+	_, err = pinEdit(stored, &app.PinUpdatePayload{NotAfter: &early}, now)
+	if err == nil {
+		t.Error("expected a not-after before the stored not-before to be refused")
+	}
+}
+
+func TestPinName(t *testing.T) {
+	for _, tc := range []struct {
+		name, expected string
+		err            bool
+	}{
+		{"example.com", "example.com", false},
+		{"/ipns/example.com", "example.com", false},
+		{"k51qzi5uqu5dlvj2baxnqndepeb86cbk3ng7n3i46uzyxzyqj2xjonzllnv0v8", "bafzaajaiaejcbzdibmxyzdjbbehgvizh6g5tikvy47mshdy6gwbruvgwvd24seje", false},
+		{"", "", true},
+		{"foo", "", true},
+		{"/ipns/", "", true},
+		{"/ipfs/QmHash", "", true},
+		{"example.com/path", "", true},
+	} {
+		got, err := pinName(tc.name)
+		if (err != nil) != tc.err {
+			t.Errorf("%q: got error %v", tc.name, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("%q: got %q, expected %q", tc.name, got, tc.expected)
+		}
+	}
+}
+
+func TestPinPath(t *testing.T) {
+	const (
+		v0 = "QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn"
+		h  = "bafybeiczsscdsbs7ffqz55asqdf3smv6klcw3gofszvwlyarci47bgf354"
+	)
+
+	for _, tc := range []struct {
+		s, hash, path string
+		err           bool
+	}{
+		{v0, h, "", false},
+		{h, h, "", false},
+		{"/ipfs/" + v0, h, "", false},
+		{"/ipfs/" + v0 + "/", h, "", false},
+		{"/ipfs/" + v0 + "/a/b", h, "/ipfs/" + h + "/a/b", false},
+		{v0 + "/a//b/", h, "/ipfs/" + h + "/a/b", false},
+		{"", "", "", true},
+		{"foo", "", "", true},
+		{"/ipfs/", "", "", true},
+		{"/ipfs/QmHash/a", "", "", true},
+		{"/ipns/example.com/a", "", "", true},
+		{"/ipfs/" + v0 + "/../a", "", "", true},
+	} {
+		got, path, err := pinPath(tc.s)
+		if (err != nil) != tc.err {
+			t.Errorf("%q: got error %v", tc.s, err)
+			continue
+		}
+		if string(got) != tc.hash || path != tc.path {
+			t.Errorf("%q: got %q and %q, expected %q and %q", tc.s, got, path, tc.hash, tc.path)
+		}
+	}
+}
+
+func TestCheckPinHash(t *testing.T) {
+	for s, valid := range map[string]bool{
+		"QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn":              true,
+		"bafybeiczsscdsbs7ffqz55asqdf3smv6klcw3gofszvwlyarci47bgf354": true,
+		"example.com": true,
+		"foo":         false,
+		"QmHash":      false,
+		"":            false,
+	} {
+		if err := checkPinHash(s); (err == nil) != valid {
+			t.Errorf("%q: got error %v", s, err)
+		}
+	}
+}
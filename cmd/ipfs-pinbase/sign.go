@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/goadesign/goa"
+)
+
+// signatureWindow is how far the date of a signed request may be off from
+// ours, which is what keeps signed requests from being replayed for long.
+var signatureWindow = 5 * time.Minute
+
+// maxSignedBody is the most of a request body read to check its signature.
+const maxSignedBody = 1 << 20
+
+// NewSignatureHandler only lets requests to the pins of parties bound to a
+// public key through to h when they are signed with the matching private key.
+// The signature covers pinbase.SigningString and is sent base64 encoded in the
+// pinbase.SignatureHeader, the date it covers in the
+// pinbase.SignatureDateHeader. Requests to other parties go through as they
+// are.
+//
+// It wraps the service's mux rather than being a goa middleware because goa
+// has read the request body by the time its middleware runs.
+func NewSignatureHandler(P pinbase.PinProvider, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		partyID, ok := pinsParty(req.URL.Path)
+		if !ok {
+			h.ServeHTTP(rw, req)
+			return
+		}
+
+		p, err := P.PinService().Party(partyID)
+		if err != nil {
+			sendError(rw, goa.ErrInternal(err))
+			return
+		}
+		if p == nil || p.PublicKey == nil {
+			h.ServeHTTP(rw, req)
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(rw, req.Body, maxSignedBody))
+		if err != nil {
+			sendError(rw, goa.ErrBadRequest(err))
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		err = checkSignature(p.PublicKey, req, body, time.Now())
+		if err != nil {
+			sendError(rw, err)
+			return
+		}
+
+		h.ServeHTTP(rw, req)
+	})
+}
+
+func checkSignature(k ed25519.PublicKey, req *http.Request, body []byte, now time.Time) error {
+	date := req.Header.Get(pinbase.SignatureDateHeader)
+	sig := req.Header.Get(pinbase.SignatureHeader)
+	if date == "" || sig == "" {
+		return goa.ErrUnauthorized("the party only takes signed requests")
+	}
+
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return goa.ErrUnauthorized("the signature date is not an RFC 3339 time")
+	}
+	if d := now.Sub(t); d > signatureWindow || d < -signatureWindow {
+		return goa.ErrUnauthorized("the signature date is too far off")
+	}
+
+	s, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return goa.ErrUnauthorized("the signature is not base64 encoded")
+	}
+
+	m := pinbase.SigningString(req.Method, req.URL.RequestURI(), date, body)
+	if !ed25519.Verify(k, m, s) {
+		return goa.ErrUnauthorized("invalid signature")
+	}
+
+	return nil
+}
+
+// pinsParty returns the party of paths under /api/parties/:partyHash/pins.
+func pinsParty(path string) (pinbase.Hash, bool) {
+	if !strings.HasPrefix(path, "/api/parties/") {
+		return "", false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path, "/api/parties/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] != "pins" {
+		return "", false
+	}
+
+	return pinbase.Hash(parts[0]), true
+}
+
+func sendError(rw http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if se, ok := err.(goa.ServiceError); ok {
+		status = se.ResponseStatus()
+	}
+
+	rw.Header().Set("Content-Type", goa.ErrorMediaIdentifier)
+	rw.WriteHeader(status)
+	json.NewEncoder(rw).Encode(err)
+}
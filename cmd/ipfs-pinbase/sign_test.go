@@ -0,0 +1,103 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/apiarian/ipfs-pinbase/pinbase/bolt"
+)
+
+func TestSignatureHandler(t *testing.T) {
+	f, err := ioutil.TempFile("", "pinbase-sign-")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %+v", err)
+	}
+	f.Close()
+	os.Remove(f.Name())
+	defer os.Remove(f.Name())
+
+	P := bolt.NewClient(f.Name())
+	err = P.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+	defer P.Close()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %+v", err)
+	}
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %+v", err)
+	}
+
+	ps := P.PinService()
+	err = ps.CreateParty(&pinbase.PartyCreate{ID: "bound", PublicKey: pub})
+	if err != nil {
+		t.Fatalf("failed to create party: %+v", err)
+	}
+	err = ps.CreateParty(&pinbase.PartyCreate{ID: "open"})
+	if err != nil {
+		t.Fatalf("failed to create party: %+v", err)
+	}
+
+	var seen string
+	h := NewSignatureHandler(P, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		b, _ := ioutil.ReadAll(req.Body)
+		seen = string(b)
+	}))
+
+	now := time.Now().UTC()
+	sign := func(req *http.Request, k ed25519.PrivateKey, date time.Time, body string) {
+		d := date.Format(time.RFC3339)
+		req.Header.Set(pinbase.SignatureDateHeader, d)
+		req.Header.Set(pinbase.SignatureHeader, base64.StdEncoding.EncodeToString(
+			ed25519.Sign(k, pinbase.SigningString(req.Method, req.URL.RequestURI(), d, []byte(body))),
+		))
+	}
+
+	for _, tc := range []struct {
+		tag      string
+		path     string
+		body     string
+		signer   ed25519.PrivateKey
+		signed   string
+		date     time.Time
+		expected int
+	}{
+		{"open party", "/api/parties/open/pins", `{"hash":"a"}`, nil, "", now, http.StatusOK},
+		{"bound party show", "/api/parties/bound", "", nil, "", now, http.StatusOK},
+		{"unsigned", "/api/parties/bound/pins", `{"hash":"a"}`, nil, "", now, http.StatusUnauthorized},
+		{"signed", "/api/parties/bound/pins", `{"hash":"a"}`, priv, `{"hash":"a"}`, now, http.StatusOK},
+		{"signed pin", "/api/parties/bound/pins/a?x=1", "", priv, "", now, http.StatusOK},
+		{"other key", "/api/parties/bound/pins", `{"hash":"a"}`, other, `{"hash":"a"}`, now, http.StatusUnauthorized},
+		{"tampered body", "/api/parties/bound/pins", `{"hash":"b"}`, priv, `{"hash":"a"}`, now, http.StatusUnauthorized},
+		{"stale", "/api/parties/bound/pins", `{"hash":"a"}`, priv, `{"hash":"a"}`, now.Add(-time.Hour), http.StatusUnauthorized},
+	} {
+		seen = ""
+		req := httptest.NewRequest("POST", tc.path, strings.NewReader(tc.body))
+		if tc.signer != nil {
+			sign(req, tc.signer, tc.date, tc.signed)
+		}
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		if rw.Code != tc.expected {
+			t.Errorf("%s: got status %d, expected %d: %s", tc.tag, rw.Code, tc.expected, rw.Body)
+		}
+		if rw.Code == http.StatusOK && seen != tc.body {
+			t.Errorf("%s: handler got body %q, expected %q", tc.tag, seen, tc.body)
+		}
+	}
+}
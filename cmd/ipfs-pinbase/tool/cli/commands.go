@@ -28,6 +28,84 @@ import (
 )
 
 type (
+	// ListArchiveCommand is the command line data structure for the list action of archive
+	ListArchiveCommand struct {
+		PrettyPrint bool
+	}
+
+	// PartyEventCommand is the command line data structure for the party action of event
+	PartyEventCommand struct {
+		// Party Hash
+		PartyHash   string
+		PrettyPrint bool
+	}
+
+	// StreamEventCommand is the command line data structure for the stream action of event
+	StreamEventCommand struct {
+		PrettyPrint bool
+	}
+
+	// CreateKeyCommand is the command line data structure for the create action of key
+	CreateKeyCommand struct {
+		Payload     string
+		ContentType string
+		PrettyPrint bool
+	}
+
+	// DeleteKeyCommand is the command line data structure for the delete action of key
+	DeleteKeyCommand struct {
+		// Key ID
+		KeyID       string
+		PrettyPrint bool
+	}
+
+	// ListKeyCommand is the command line data structure for the list action of key
+	ListKeyCommand struct {
+		PrettyPrint bool
+	}
+
+	// ShowKeyCommand is the command line data structure for the show action of key
+	ShowKeyCommand struct {
+		// Key ID
+		KeyID       string
+		PrettyPrint bool
+	}
+
+	// CreateNodeCommand is the command line data structure for the create action of node
+	CreateNodeCommand struct {
+		Payload     string
+		ContentType string
+		PrettyPrint bool
+	}
+
+	// DeleteNodeCommand is the command line data structure for the delete action of node
+	DeleteNodeCommand struct {
+		// Node Name
+		NodeName    string
+		PrettyPrint bool
+	}
+
+	// ListNodeCommand is the command line data structure for the list action of node
+	ListNodeCommand struct {
+		PrettyPrint bool
+	}
+
+	// ShowNodeCommand is the command line data structure for the show action of node
+	ShowNodeCommand struct {
+		// Node Name
+		NodeName    string
+		PrettyPrint bool
+	}
+
+	// UpdateNodeCommand is the command line data structure for the update action of node
+	UpdateNodeCommand struct {
+		Payload     string
+		ContentType string
+		// Node Name
+		NodeName    string
+		PrettyPrint bool
+	}
+
 	// CreatePartyCommand is the command line data structure for the create action of party
 	CreatePartyCommand struct {
 		Payload     string
@@ -42,6 +120,17 @@ type (
 		PrettyPrint bool
 	}
 
+	// GrantPartyCommand is the command line data structure for the grant action of party
+	GrantPartyCommand struct {
+		Payload     string
+		ContentType string
+		// Key ID
+		KeyID string
+		// Party Hash
+		PartyHash   string
+		PrettyPrint bool
+	}
+
 	// ListPartyCommand is the command line data structure for the list action of party
 	ListPartyCommand struct {
 		PrettyPrint bool
@@ -76,7 +165,16 @@ type (
 	DeletePinCommand struct {
 		// Party Hash
 		PartyHash string
-		// Pin Hash
+		// The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin
+		PinHash     string
+		PrettyPrint bool
+	}
+
+	// HistoryPinCommand is the command line data structure for the history action of pin
+	HistoryPinCommand struct {
+		// Party Hash
+		PartyHash string
+		// The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin
 		PinHash     string
 		PrettyPrint bool
 	}
@@ -88,11 +186,31 @@ type (
 		PrettyPrint bool
 	}
 
+	// RenewPinCommand is the command line data structure for the renew action of pin
+	RenewPinCommand struct {
+		Payload     string
+		ContentType string
+		// Party Hash
+		PartyHash string
+		// The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin
+		PinHash     string
+		PrettyPrint bool
+	}
+
+	// ResetPinCommand is the command line data structure for the reset action of pin
+	ResetPinCommand struct {
+		// Party Hash
+		PartyHash string
+		// The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin
+		PinHash     string
+		PrettyPrint bool
+	}
+
 	// ShowPinCommand is the command line data structure for the show action of pin
 	ShowPinCommand struct {
 		// Party Hash
 		PartyHash string
-		// Pin Hash
+		// The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin
 		PinHash     string
 		PrettyPrint bool
 	}
@@ -103,10 +221,44 @@ type (
 		ContentType string
 		// Party Hash
 		PartyHash string
-		// Pin Hash
+		// The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin
 		PinHash     string
 		PrettyPrint bool
 	}
+
+	// CreateWebhookCommand is the command line data structure for the create action of webhook
+	CreateWebhookCommand struct {
+		Payload     string
+		ContentType string
+		// Party Hash
+		PartyHash   string
+		PrettyPrint bool
+	}
+
+	// DeleteWebhookCommand is the command line data structure for the delete action of webhook
+	DeleteWebhookCommand struct {
+		// Party Hash
+		PartyHash string
+		// Webhook ID
+		WebhookID   string
+		PrettyPrint bool
+	}
+
+	// ListWebhookCommand is the command line data structure for the list action of webhook
+	ListWebhookCommand struct {
+		// Party Hash
+		PartyHash   string
+		PrettyPrint bool
+	}
+
+	// ShowWebhookCommand is the command line data structure for the show action of webhook
+	ShowWebhookCommand struct {
+		// Party Hash
+		PartyHash string
+		// Webhook ID
+		WebhookID   string
+		PrettyPrint bool
+	}
 )
 
 // RegisterCommands registers the resource action CLI commands.
@@ -116,183 +268,458 @@ func RegisterCommands(app *cobra.Command, c *client.Client) {
 		Use:   "create",
 		Short: `create action`,
 	}
-	tmp1 := new(CreatePartyCommand)
+	tmp1 := new(CreateKeyCommand)
 	sub = &cobra.Command{
-		Use:   `party ["/api/parties"]`,
-		Short: `The Pinbase Party resource`,
-		Long: `The Pinbase Party resource
+		Use:   `key ["/api/keys"]`,
+		Short: `The API keys that may use this pinbase`,
+		Long: `The API keys that may use this pinbase
 
 Payload example:
 
 {
-   "description": "Commodi ea magni mollitia dicta.",
-   "hash": "Magni ullam id dolorem sunt consequatur incidunt."
+   "admin": true,
+   "description": "Sed ab et ut."
 }`,
 		RunE: func(cmd *cobra.Command, args []string) error { return tmp1.Run(c, args) },
 	}
 	tmp1.RegisterFlags(sub, c)
 	sub.PersistentFlags().BoolVar(&tmp1.PrettyPrint, "pp", false, "Pretty print response body")
 	command.AddCommand(sub)
-	tmp2 := new(CreatePinCommand)
+	tmp2 := new(CreateNodeCommand)
 	sub = &cobra.Command{
-		Use:   `pin ["/api/parties/PARTYHASH/pins"]`,
-		Short: `A thing to pin in IPFS`,
-		Long: `A thing to pin in IPFS
+		Use:   `node ["/api/nodes"]`,
+		Short: `An IPFS node to pin on`,
+		Long: `An IPFS node to pin on
 
 Payload example:
 
 {
-   "aliases": [
-      "Ipsa architecto.",
-      "Ipsa architecto."
-   ],
-   "hash": "Ut velit.",
-   "want-pinned": true
+   "api-address": "127.0.0.1:5001",
+   "name": "Non earum in consequuntur."
 }`,
 		RunE: func(cmd *cobra.Command, args []string) error { return tmp2.Run(c, args) },
 	}
 	tmp2.RegisterFlags(sub, c)
 	sub.PersistentFlags().BoolVar(&tmp2.PrettyPrint, "pp", false, "Pretty print response body")
 	command.AddCommand(sub)
-	app.AddCommand(command)
-	command = &cobra.Command{
-		Use:   "delete",
-		Short: `delete action`,
-	}
-	tmp3 := new(DeletePartyCommand)
+	tmp3 := new(CreatePartyCommand)
 	sub = &cobra.Command{
-		Use:   `party ["/api/parties/PARTYHASH"]`,
+		Use:   `party ["/api/parties"]`,
 		Short: `The Pinbase Party resource`,
-		RunE:  func(cmd *cobra.Command, args []string) error { return tmp3.Run(c, args) },
+		Long: `The Pinbase Party resource
+
+Payload example:
+
+{
+   "description": "Dolore vero nam nisi et ea sapiente.",
+   "hash": "Qui quibusdam totam cum vitae soluta.",
+   "max-bytes": 0,
+   "max-pins": 2,
+   "public-key": "Enim quas."
+}`,
+		RunE: func(cmd *cobra.Command, args []string) error { return tmp3.Run(c, args) },
 	}
 	tmp3.RegisterFlags(sub, c)
 	sub.PersistentFlags().BoolVar(&tmp3.PrettyPrint, "pp", false, "Pretty print response body")
 	command.AddCommand(sub)
-	tmp4 := new(DeletePinCommand)
+	tmp4 := new(CreatePinCommand)
 	sub = &cobra.Command{
-		Use:   `pin ["/api/parties/PARTYHASH/pins/PINHASH"]`,
+		Use:   `pin ["/api/parties/PARTYHASH/pins"]`,
 		Short: `A thing to pin in IPFS`,
-		RunE:  func(cmd *cobra.Command, args []string) error { return tmp4.Run(c, args) },
+		Long: `A thing to pin in IPFS
+
+Payload example:
+
+{
+   "aliases": [
+      "Voluptas et eaque neque sapiente quos.",
+      "Voluptas et eaque neque sapiente quos.",
+      "Voluptas et eaque neque sapiente quos."
+   ],
+   "expires-at": "1976-03-21T22:25:15Z",
+   "hash": "Doloremque non explicabo qui earum qui.",
+   "kind": "hash",
+   "mode": "recursive",
+   "not-after": "2010-03-17T11:48:49Z",
+   "not-before": "1993-04-12T14:28:22Z",
+   "replication": 1,
+   "ttl": "Minus perferendis.",
+   "want-pinned": false
+}`,
+		RunE: func(cmd *cobra.Command, args []string) error { return tmp4.Run(c, args) },
 	}
 	tmp4.RegisterFlags(sub, c)
 	sub.PersistentFlags().BoolVar(&tmp4.PrettyPrint, "pp", false, "Pretty print response body")
 	command.AddCommand(sub)
-	app.AddCommand(command)
-	command = &cobra.Command{
-		Use:   "list",
-		Short: `list action`,
-	}
-	tmp5 := new(ListPartyCommand)
+	tmp5 := new(CreateWebhookCommand)
 	sub = &cobra.Command{
-		Use:   `party ["/api/parties"]`,
-		Short: `The Pinbase Party resource`,
-		RunE:  func(cmd *cobra.Command, args []string) error { return tmp5.Run(c, args) },
+		Use:   `webhook ["/api/parties/PARTYHASH/webhooks"]`,
+		Short: `URLs the party's pin status changes are posted to as JSON, signed with the webhook's secret`,
+		Long: `URLs the party's pin status changes are posted to as JSON, signed with the webhook's secret
+
+Payload example:
+
+{
+   "url": "http://hilll.biz/abel"
+}`,
+		RunE: func(cmd *cobra.Command, args []string) error { return tmp5.Run(c, args) },
 	}
 	tmp5.RegisterFlags(sub, c)
 	sub.PersistentFlags().BoolVar(&tmp5.PrettyPrint, "pp", false, "Pretty print response body")
 	command.AddCommand(sub)
-	tmp6 := new(ListPinCommand)
+	app.AddCommand(command)
+	command = &cobra.Command{
+		Use:   "delete",
+		Short: `delete action`,
+	}
+	tmp6 := new(DeleteKeyCommand)
 	sub = &cobra.Command{
-		Use:   `pin ["/api/parties/PARTYHASH/pins"]`,
-		Short: `A thing to pin in IPFS`,
+		Use:   `key ["/api/keys/KEYID"]`,
+		Short: `The API keys that may use this pinbase`,
 		RunE:  func(cmd *cobra.Command, args []string) error { return tmp6.Run(c, args) },
 	}
 	tmp6.RegisterFlags(sub, c)
 	sub.PersistentFlags().BoolVar(&tmp6.PrettyPrint, "pp", false, "Pretty print response body")
 	command.AddCommand(sub)
-	app.AddCommand(command)
-	command = &cobra.Command{
-		Use:   "show",
-		Short: `show action`,
-	}
-	tmp7 := new(ShowPartyCommand)
+	tmp7 := new(DeleteNodeCommand)
 	sub = &cobra.Command{
-		Use:   `party ["/api/parties/PARTYHASH"]`,
-		Short: `The Pinbase Party resource`,
+		Use:   `node ["/api/nodes/NODENAME"]`,
+		Short: `An IPFS node to pin on`,
 		RunE:  func(cmd *cobra.Command, args []string) error { return tmp7.Run(c, args) },
 	}
 	tmp7.RegisterFlags(sub, c)
 	sub.PersistentFlags().BoolVar(&tmp7.PrettyPrint, "pp", false, "Pretty print response body")
 	command.AddCommand(sub)
-	tmp8 := new(ShowPinCommand)
+	tmp8 := new(DeletePartyCommand)
 	sub = &cobra.Command{
-		Use:   `pin ["/api/parties/PARTYHASH/pins/PINHASH"]`,
-		Short: `A thing to pin in IPFS`,
+		Use:   `party ["/api/parties/PARTYHASH"]`,
+		Short: `The Pinbase Party resource`,
 		RunE:  func(cmd *cobra.Command, args []string) error { return tmp8.Run(c, args) },
 	}
 	tmp8.RegisterFlags(sub, c)
 	sub.PersistentFlags().BoolVar(&tmp8.PrettyPrint, "pp", false, "Pretty print response body")
 	command.AddCommand(sub)
+	tmp9 := new(DeletePinCommand)
+	sub = &cobra.Command{
+		Use:   `pin ["/api/parties/PARTYHASH/pins/PINHASH"]`,
+		Short: `A thing to pin in IPFS`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp9.Run(c, args) },
+	}
+	tmp9.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp9.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	tmp10 := new(DeleteWebhookCommand)
+	sub = &cobra.Command{
+		Use:   `webhook ["/api/parties/PARTYHASH/webhooks/WEBHOOKID"]`,
+		Short: `URLs the party's pin status changes are posted to as JSON, signed with the webhook's secret`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp10.Run(c, args) },
+	}
+	tmp10.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp10.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
 	app.AddCommand(command)
 	command = &cobra.Command{
-		Use:   "update",
-		Short: `update action`,
+		Use:   "grant",
+		Short: `Give an API key a role on the party, or take it away with the none role`,
 	}
-	tmp9 := new(UpdatePartyCommand)
+	tmp11 := new(GrantPartyCommand)
 	sub = &cobra.Command{
-		Use:   `party ["/api/parties/PARTYHASH"]`,
+		Use:   `party ["/api/parties/PARTYHASH/grants/KEYID"]`,
 		Short: `The Pinbase Party resource`,
 		Long: `The Pinbase Party resource
 
 Payload example:
 
 {
-   "description": "Doloremque modi et quae."
+   "role": "none"
 }`,
-		RunE: func(cmd *cobra.Command, args []string) error { return tmp9.Run(c, args) },
+		RunE: func(cmd *cobra.Command, args []string) error { return tmp11.Run(c, args) },
 	}
-	tmp9.RegisterFlags(sub, c)
-	sub.PersistentFlags().BoolVar(&tmp9.PrettyPrint, "pp", false, "Pretty print response body")
+	tmp11.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp11.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	app.AddCommand(command)
+	command = &cobra.Command{
+		Use:   "history",
+		Short: `List the status changes and edits of a pin under the party, oldest first. The history of a deleted pin is kept`,
+	}
+	tmp12 := new(HistoryPinCommand)
+	sub = &cobra.Command{
+		Use:   `pin ["/api/parties/PARTYHASH/pins/PINHASH/history"]`,
+		Short: `A thing to pin in IPFS`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp12.Run(c, args) },
+	}
+	tmp12.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp12.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	app.AddCommand(command)
+	command = &cobra.Command{
+		Use:   "list",
+		Short: `list action`,
+	}
+	tmp13 := new(ListArchiveCommand)
+	sub = &cobra.Command{
+		Use:   `archive ["/api/archive"]`,
+		Short: `Hashes no party holds anymore, waiting to be unpinned`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp13.Run(c, args) },
+	}
+	tmp13.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp13.PrettyPrint, "pp", false, "Pretty print response body")
 	command.AddCommand(sub)
-	tmp10 := new(UpdatePinCommand)
+	tmp14 := new(ListKeyCommand)
 	sub = &cobra.Command{
-		Use:   `pin ["/api/parties/PARTYHASH/pins/PINHASH"]`,
+		Use:   `key ["/api/keys"]`,
+		Short: `The API keys that may use this pinbase`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp14.Run(c, args) },
+	}
+	tmp14.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp14.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	tmp15 := new(ListNodeCommand)
+	sub = &cobra.Command{
+		Use:   `node ["/api/nodes"]`,
+		Short: `An IPFS node to pin on`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp15.Run(c, args) },
+	}
+	tmp15.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp15.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	tmp16 := new(ListPartyCommand)
+	sub = &cobra.Command{
+		Use:   `party ["/api/parties"]`,
+		Short: `The Pinbase Party resource`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp16.Run(c, args) },
+	}
+	tmp16.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp16.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	tmp17 := new(ListPinCommand)
+	sub = &cobra.Command{
+		Use:   `pin ["/api/parties/PARTYHASH/pins"]`,
+		Short: `A thing to pin in IPFS`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp17.Run(c, args) },
+	}
+	tmp17.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp17.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	tmp18 := new(ListWebhookCommand)
+	sub = &cobra.Command{
+		Use:   `webhook ["/api/parties/PARTYHASH/webhooks"]`,
+		Short: `URLs the party's pin status changes are posted to as JSON, signed with the webhook's secret`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp18.Run(c, args) },
+	}
+	tmp18.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp18.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	app.AddCommand(command)
+	command = &cobra.Command{
+		Use:   "party",
+		Short: `Stream the pin status changes of a party`,
+	}
+	tmp19 := new(PartyEventCommand)
+	sub = &cobra.Command{
+		Use:   `event ["/api/events/PARTYHASH"]`,
+		Short: `Pin status changes streamed as Server-Sent Events, one pin event object of JSON per event`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp19.Run(c, args) },
+	}
+	tmp19.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp19.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	app.AddCommand(command)
+	command = &cobra.Command{
+		Use:   "renew",
+		Short: `Want a pin under the party again until the new expiry, or for good without one`,
+	}
+	tmp20 := new(RenewPinCommand)
+	sub = &cobra.Command{
+		Use:   `pin ["/api/parties/PARTYHASH/pins/PINHASH/renew"]`,
 		Short: `A thing to pin in IPFS`,
 		Long: `A thing to pin in IPFS
 
 Payload example:
 
 {
-   "aliases": [
-      "Provident est eum quis rem ut."
-   ],
-   "want-pinned": false
+   "expires-at": "1971-09-22T09:18:25Z",
+   "ttl": "Voluptas quasi et minima quis perferendis atque."
 }`,
-		RunE: func(cmd *cobra.Command, args []string) error { return tmp10.Run(c, args) },
+		RunE: func(cmd *cobra.Command, args []string) error { return tmp20.Run(c, args) },
 	}
-	tmp10.RegisterFlags(sub, c)
-	sub.PersistentFlags().BoolVar(&tmp10.PrettyPrint, "pp", false, "Pretty print response body")
+	tmp20.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp20.PrettyPrint, "pp", false, "Pretty print response body")
 	command.AddCommand(sub)
 	app.AddCommand(command)
-}
-
-func intFlagVal(name string, parsed int) *int {
-	if hasFlag(name) {
-		return &parsed
+	command = &cobra.Command{
+		Use:   "reset",
+		Short: `Clear the failed attempts of a pin under the party and try it again`,
 	}
-	return nil
-}
-
-func float64FlagVal(name string, parsed float64) *float64 {
-	if hasFlag(name) {
-		return &parsed
+	tmp21 := new(ResetPinCommand)
+	sub = &cobra.Command{
+		Use:   `pin ["/api/parties/PARTYHASH/pins/PINHASH/reset"]`,
+		Short: `A thing to pin in IPFS`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp21.Run(c, args) },
 	}
-	return nil
-}
-
-func boolFlagVal(name string, parsed bool) *bool {
-	if hasFlag(name) {
-		return &parsed
+	tmp21.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp21.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	app.AddCommand(command)
+	command = &cobra.Command{
+		Use:   "show",
+		Short: `show action`,
 	}
-	return nil
-}
-
-func stringFlagVal(name string, parsed string) *string {
-	if hasFlag(name) {
-		return &parsed
+	tmp22 := new(ShowKeyCommand)
+	sub = &cobra.Command{
+		Use:   `key ["/api/keys/KEYID"]`,
+		Short: `The API keys that may use this pinbase`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp22.Run(c, args) },
 	}
-	return nil
-}
+	tmp22.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp22.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	tmp23 := new(ShowNodeCommand)
+	sub = &cobra.Command{
+		Use:   `node ["/api/nodes/NODENAME"]`,
+		Short: `An IPFS node to pin on`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp23.Run(c, args) },
+	}
+	tmp23.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp23.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	tmp24 := new(ShowPartyCommand)
+	sub = &cobra.Command{
+		Use:   `party ["/api/parties/PARTYHASH"]`,
+		Short: `The Pinbase Party resource`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp24.Run(c, args) },
+	}
+	tmp24.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp24.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	tmp25 := new(ShowPinCommand)
+	sub = &cobra.Command{
+		Use:   `pin ["/api/parties/PARTYHASH/pins/PINHASH"]`,
+		Short: `A thing to pin in IPFS`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp25.Run(c, args) },
+	}
+	tmp25.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp25.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	tmp26 := new(ShowWebhookCommand)
+	sub = &cobra.Command{
+		Use:   `webhook ["/api/parties/PARTYHASH/webhooks/WEBHOOKID"]`,
+		Short: `URLs the party's pin status changes are posted to as JSON, signed with the webhook's secret`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp26.Run(c, args) },
+	}
+	tmp26.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp26.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	app.AddCommand(command)
+	command = &cobra.Command{
+		Use:   "stream",
+		Short: `Stream the pin status changes of every party, for admin keys`,
+	}
+	tmp27 := new(StreamEventCommand)
+	sub = &cobra.Command{
+		Use:   `event ["/api/events"]`,
+		Short: `Pin status changes streamed as Server-Sent Events, one pin event object of JSON per event`,
+		RunE:  func(cmd *cobra.Command, args []string) error { return tmp27.Run(c, args) },
+	}
+	tmp27.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp27.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	app.AddCommand(command)
+	command = &cobra.Command{
+		Use:   "update",
+		Short: `update action`,
+	}
+	tmp28 := new(UpdateNodeCommand)
+	sub = &cobra.Command{
+		Use:   `node ["/api/nodes/NODENAME"]`,
+		Short: `An IPFS node to pin on`,
+		Long: `An IPFS node to pin on
+
+Payload example:
+
+{
+   "api-address": "127.0.0.1:5001"
+}`,
+		RunE: func(cmd *cobra.Command, args []string) error { return tmp28.Run(c, args) },
+	}
+	tmp28.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp28.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	tmp29 := new(UpdatePartyCommand)
+	sub = &cobra.Command{
+		Use:   `party ["/api/parties/PARTYHASH"]`,
+		Short: `The Pinbase Party resource`,
+		Long: `The Pinbase Party resource
+
+Payload example:
+
+{
+   "description": "Illo optio nemo modi voluptas quisquam.",
+   "max-bytes": 1,
+   "max-pins": 0
+}`,
+		RunE: func(cmd *cobra.Command, args []string) error { return tmp29.Run(c, args) },
+	}
+	tmp29.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp29.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	tmp30 := new(UpdatePinCommand)
+	sub = &cobra.Command{
+		Use:   `pin ["/api/parties/PARTYHASH/pins/PINHASH"]`,
+		Short: `A thing to pin in IPFS`,
+		Long: `A thing to pin in IPFS
+
+Payload example:
+
+{
+   "aliases": [
+      "Quidem atque praesentium iste eum.",
+      "Quidem atque praesentium iste eum."
+   ],
+   "expires-at": "1996-11-14T10:07:05Z",
+   "mode": "recursive",
+   "not-after": "1993-01-25T13:21:01Z",
+   "not-before": "2010-11-01T00:27:50Z",
+   "replication": 1,
+   "ttl": "Quod unde fugit minus velit velit qui.",
+   "want-pinned": true
+}`,
+		RunE: func(cmd *cobra.Command, args []string) error { return tmp30.Run(c, args) },
+	}
+	tmp30.RegisterFlags(sub, c)
+	sub.PersistentFlags().BoolVar(&tmp30.PrettyPrint, "pp", false, "Pretty print response body")
+	command.AddCommand(sub)
+	app.AddCommand(command)
+}
+
+func intFlagVal(name string, parsed int) *int {
+	if hasFlag(name) {
+		return &parsed
+	}
+	return nil
+}
+
+func float64FlagVal(name string, parsed float64) *float64 {
+	if hasFlag(name) {
+		return &parsed
+	}
+	return nil
+}
+
+func boolFlagVal(name string, parsed bool) *bool {
+	if hasFlag(name) {
+		return &parsed
+	}
+	return nil
+}
+
+func stringFlagVal(name string, parsed string) *string {
+	if hasFlag(name) {
+		return &parsed
+	}
+	return nil
+}
 
 func hasFlag(name string) bool {
 	for _, arg := range os.Args[1:] {
@@ -370,53 +797,380 @@ func uuidArray(ins []string) ([]uuid.UUID, error) {
 		}
 		vals = append(vals, *val)
 	}
-	return vals, nil
+	return vals, nil
+}
+
+func float64Val(val string) (*float64, error) {
+	t, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func float64Array(ins []string) ([]float64, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []float64
+	for _, id := range ins {
+		val, err := float64Val(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+func boolVal(val string) (*bool, error) {
+	t, err := strconv.ParseBool(val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func boolArray(ins []string) ([]bool, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []bool
+	for _, id := range ins {
+		val, err := boolVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
+// Run makes the HTTP request corresponding to the ListArchiveCommand command.
+func (cmd *ListArchiveCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = "/api/archive"
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.ListArchive(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *ListArchiveCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+}
+
+// Run makes the HTTP request corresponding to the PartyEventCommand command.
+func (cmd *PartyEventCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/events/%v", url.QueryEscape(cmd.PartyHash))
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.PartyEvent(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *PartyEventCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	var partyHash string
+	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
+}
+
+// Run makes the HTTP request corresponding to the StreamEventCommand command.
+func (cmd *StreamEventCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = "/api/events"
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.StreamEvent(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *StreamEventCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+}
+
+// Run makes the HTTP request corresponding to the CreateKeyCommand command.
+func (cmd *CreateKeyCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = "/api/keys"
+	}
+	var payload client.CreateKeyPayload
+	if cmd.Payload != "" {
+		err := json.Unmarshal([]byte(cmd.Payload), &payload)
+		if err != nil {
+			return fmt.Errorf("failed to deserialize payload: %s", err)
+		}
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.CreateKey(ctx, path, &payload)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *CreateKeyCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	cc.Flags().StringVar(&cmd.Payload, "payload", "", "Request body encoded in JSON")
+	cc.Flags().StringVar(&cmd.ContentType, "content", "", "Request content type override, e.g. 'application/x-www-form-urlencoded'")
+}
+
+// Run makes the HTTP request corresponding to the DeleteKeyCommand command.
+func (cmd *DeleteKeyCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/keys/%v", url.QueryEscape(cmd.KeyID))
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.DeleteKey(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *DeleteKeyCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	var keyID string
+	cc.Flags().StringVar(&cmd.KeyID, "keyID", keyID, `Key ID`)
+}
+
+// Run makes the HTTP request corresponding to the ListKeyCommand command.
+func (cmd *ListKeyCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = "/api/keys"
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.ListKey(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *ListKeyCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+}
+
+// Run makes the HTTP request corresponding to the ShowKeyCommand command.
+func (cmd *ShowKeyCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/keys/%v", url.QueryEscape(cmd.KeyID))
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.ShowKey(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *ShowKeyCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	var keyID string
+	cc.Flags().StringVar(&cmd.KeyID, "keyID", keyID, `Key ID`)
+}
+
+// Run makes the HTTP request corresponding to the CreateNodeCommand command.
+func (cmd *CreateNodeCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = "/api/nodes"
+	}
+	var payload client.CreateNodePayload
+	if cmd.Payload != "" {
+		err := json.Unmarshal([]byte(cmd.Payload), &payload)
+		if err != nil {
+			return fmt.Errorf("failed to deserialize payload: %s", err)
+		}
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.CreateNode(ctx, path, &payload)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *CreateNodeCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	cc.Flags().StringVar(&cmd.Payload, "payload", "", "Request body encoded in JSON")
+	cc.Flags().StringVar(&cmd.ContentType, "content", "", "Request content type override, e.g. 'application/x-www-form-urlencoded'")
+}
+
+// Run makes the HTTP request corresponding to the DeleteNodeCommand command.
+func (cmd *DeleteNodeCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/nodes/%v", url.QueryEscape(cmd.NodeName))
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.DeleteNode(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *DeleteNodeCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	var nodeName string
+	cc.Flags().StringVar(&cmd.NodeName, "nodeName", nodeName, `Node Name`)
+}
+
+// Run makes the HTTP request corresponding to the ListNodeCommand command.
+func (cmd *ListNodeCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = "/api/nodes"
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.ListNode(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *ListNodeCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+}
+
+// Run makes the HTTP request corresponding to the ShowNodeCommand command.
+func (cmd *ShowNodeCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/nodes/%v", url.QueryEscape(cmd.NodeName))
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.ShowNode(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
 }
 
-func float64Val(val string) (*float64, error) {
-	t, err := strconv.ParseFloat(val, 64)
-	if err != nil {
-		return nil, err
-	}
-	return &t, nil
+// RegisterFlags registers the command flags with the command line.
+func (cmd *ShowNodeCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	var nodeName string
+	cc.Flags().StringVar(&cmd.NodeName, "nodeName", nodeName, `Node Name`)
 }
 
-func float64Array(ins []string) ([]float64, error) {
-	if ins == nil {
-		return nil, nil
+// Run makes the HTTP request corresponding to the UpdateNodeCommand command.
+func (cmd *UpdateNodeCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/nodes/%v", url.QueryEscape(cmd.NodeName))
 	}
-	var vals []float64
-	for _, id := range ins {
-		val, err := float64Val(id)
+	var payload client.UpdateNodePayload
+	if cmd.Payload != "" {
+		err := json.Unmarshal([]byte(cmd.Payload), &payload)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("failed to deserialize payload: %s", err)
 		}
-		vals = append(vals, *val)
 	}
-	return vals, nil
-}
-
-func boolVal(val string) (*bool, error) {
-	t, err := strconv.ParseBool(val)
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.UpdateNode(ctx, path, &payload)
 	if err != nil {
-		return nil, err
+		goa.LogError(ctx, "failed", "err", err)
+		return err
 	}
-	return &t, nil
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
 }
 
-func boolArray(ins []string) ([]bool, error) {
-	if ins == nil {
-		return nil, nil
-	}
-	var vals []bool
-	for _, id := range ins {
-		val, err := boolVal(id)
-		if err != nil {
-			return nil, err
-		}
-		vals = append(vals, *val)
-	}
-	return vals, nil
+// RegisterFlags registers the command flags with the command line.
+func (cmd *UpdateNodeCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	cc.Flags().StringVar(&cmd.Payload, "payload", "", "Request body encoded in JSON")
+	cc.Flags().StringVar(&cmd.ContentType, "content", "", "Request content type override, e.g. 'application/x-www-form-urlencoded'")
+	var nodeName string
+	cc.Flags().StringVar(&cmd.NodeName, "nodeName", nodeName, `Node Name`)
 }
 
 // Run makes the HTTP request corresponding to the CreatePartyCommand command.
@@ -478,6 +1232,43 @@ func (cmd *DeletePartyCommand) RegisterFlags(cc *cobra.Command, c *client.Client
 	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
 }
 
+// Run makes the HTTP request corresponding to the GrantPartyCommand command.
+func (cmd *GrantPartyCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/parties/%v/grants/%v", url.QueryEscape(cmd.PartyHash), url.QueryEscape(cmd.KeyID))
+	}
+	var payload client.GrantPartyPayload
+	if cmd.Payload != "" {
+		err := json.Unmarshal([]byte(cmd.Payload), &payload)
+		if err != nil {
+			return fmt.Errorf("failed to deserialize payload: %s", err)
+		}
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.GrantParty(ctx, path, &payload)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *GrantPartyCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	cc.Flags().StringVar(&cmd.Payload, "payload", "", "Request body encoded in JSON")
+	cc.Flags().StringVar(&cmd.ContentType, "content", "", "Request content type override, e.g. 'application/x-www-form-urlencoded'")
+	var keyID string
+	cc.Flags().StringVar(&cmd.KeyID, "keyID", keyID, `Key ID`)
+	var partyHash string
+	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
+}
+
 // Run makes the HTTP request corresponding to the ListPartyCommand command.
 func (cmd *ListPartyCommand) Run(c *client.Client, args []string) error {
 	var path string
@@ -623,7 +1414,35 @@ func (cmd *DeletePinCommand) RegisterFlags(cc *cobra.Command, c *client.Client)
 	var partyHash string
 	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
 	var pinHash string
-	cc.Flags().StringVar(&cmd.PinHash, "pinHash", pinHash, `Pin Hash`)
+	cc.Flags().StringVar(&cmd.PinHash, "pinHash", pinHash, `The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin`)
+}
+
+// Run makes the HTTP request corresponding to the HistoryPinCommand command.
+func (cmd *HistoryPinCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/parties/%v/pins/%v/history", url.QueryEscape(cmd.PartyHash), url.QueryEscape(cmd.PinHash))
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.HistoryPin(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *HistoryPinCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	var partyHash string
+	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
+	var pinHash string
+	cc.Flags().StringVar(&cmd.PinHash, "pinHash", pinHash, `The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin`)
 }
 
 // Run makes the HTTP request corresponding to the ListPinCommand command.
@@ -652,6 +1471,71 @@ func (cmd *ListPinCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
 	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
 }
 
+// Run makes the HTTP request corresponding to the RenewPinCommand command.
+func (cmd *RenewPinCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/parties/%v/pins/%v/renew", url.QueryEscape(cmd.PartyHash), url.QueryEscape(cmd.PinHash))
+	}
+	var payload client.PinRenewPayload
+	if cmd.Payload != "" {
+		err := json.Unmarshal([]byte(cmd.Payload), &payload)
+		if err != nil {
+			return fmt.Errorf("failed to deserialize payload: %s", err)
+		}
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.RenewPin(ctx, path, &payload)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *RenewPinCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	cc.Flags().StringVar(&cmd.Payload, "payload", "", "Request body encoded in JSON")
+	cc.Flags().StringVar(&cmd.ContentType, "content", "", "Request content type override, e.g. 'application/x-www-form-urlencoded'")
+	var partyHash string
+	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
+	var pinHash string
+	cc.Flags().StringVar(&cmd.PinHash, "pinHash", pinHash, `The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin`)
+}
+
+// Run makes the HTTP request corresponding to the ResetPinCommand command.
+func (cmd *ResetPinCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/parties/%v/pins/%v/reset", url.QueryEscape(cmd.PartyHash), url.QueryEscape(cmd.PinHash))
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.ResetPin(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *ResetPinCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	var partyHash string
+	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
+	var pinHash string
+	cc.Flags().StringVar(&cmd.PinHash, "pinHash", pinHash, `The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin`)
+}
+
 // Run makes the HTTP request corresponding to the ShowPinCommand command.
 func (cmd *ShowPinCommand) Run(c *client.Client, args []string) error {
 	var path string
@@ -677,7 +1561,7 @@ func (cmd *ShowPinCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
 	var partyHash string
 	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
 	var pinHash string
-	cc.Flags().StringVar(&cmd.PinHash, "pinHash", pinHash, `Pin Hash`)
+	cc.Flags().StringVar(&cmd.PinHash, "pinHash", pinHash, `The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin`)
 }
 
 // Run makes the HTTP request corresponding to the UpdatePinCommand command.
@@ -714,5 +1598,122 @@ func (cmd *UpdatePinCommand) RegisterFlags(cc *cobra.Command, c *client.Client)
 	var partyHash string
 	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
 	var pinHash string
-	cc.Flags().StringVar(&cmd.PinHash, "pinHash", pinHash, `Pin Hash`)
+	cc.Flags().StringVar(&cmd.PinHash, "pinHash", pinHash, `The hash of a hash pin, in any form of its CID, or the IPNS name or DNSLink domain of a name pin`)
+}
+
+// Run makes the HTTP request corresponding to the CreateWebhookCommand command.
+func (cmd *CreateWebhookCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/parties/%v/webhooks", url.QueryEscape(cmd.PartyHash))
+	}
+	var payload client.CreateWebhookPayload
+	if cmd.Payload != "" {
+		err := json.Unmarshal([]byte(cmd.Payload), &payload)
+		if err != nil {
+			return fmt.Errorf("failed to deserialize payload: %s", err)
+		}
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.CreateWebhook(ctx, path, &payload)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *CreateWebhookCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	cc.Flags().StringVar(&cmd.Payload, "payload", "", "Request body encoded in JSON")
+	cc.Flags().StringVar(&cmd.ContentType, "content", "", "Request content type override, e.g. 'application/x-www-form-urlencoded'")
+	var partyHash string
+	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
+}
+
+// Run makes the HTTP request corresponding to the DeleteWebhookCommand command.
+func (cmd *DeleteWebhookCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/parties/%v/webhooks/%v", url.QueryEscape(cmd.PartyHash), url.QueryEscape(cmd.WebhookID))
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.DeleteWebhook(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *DeleteWebhookCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	var partyHash string
+	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
+	var webhookID string
+	cc.Flags().StringVar(&cmd.WebhookID, "webhookID", webhookID, `Webhook ID`)
+}
+
+// Run makes the HTTP request corresponding to the ListWebhookCommand command.
+func (cmd *ListWebhookCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/parties/%v/webhooks", url.QueryEscape(cmd.PartyHash))
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.ListWebhook(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *ListWebhookCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	var partyHash string
+	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
+}
+
+// Run makes the HTTP request corresponding to the ShowWebhookCommand command.
+func (cmd *ShowWebhookCommand) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = fmt.Sprintf("/api/parties/%v/webhooks/%v", url.QueryEscape(cmd.PartyHash), url.QueryEscape(cmd.WebhookID))
+	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	resp, err := c.ShowWebhook(ctx, path)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+
+	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	return nil
+}
+
+// RegisterFlags registers the command flags with the command line.
+func (cmd *ShowWebhookCommand) RegisterFlags(cc *cobra.Command, c *client.Client) {
+	var partyHash string
+	cc.Flags().StringVar(&cmd.PartyHash, "partyHash", partyHash, `Party Hash`)
+	var webhookID string
+	cc.Flags().StringVar(&cmd.WebhookID, "webhookID", webhookID, `Webhook ID`)
 }
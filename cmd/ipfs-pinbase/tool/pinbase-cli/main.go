@@ -1,13 +1,23 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/client"
 	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/tool/cli"
+	"github.com/apiarian/ipfs-pinbase/pinbase"
 	goaclient "github.com/goadesign/goa/client"
 	"github.com/spf13/cobra"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"strings"
+	"text/tabwriter"
 	"time"
 )
 
@@ -28,11 +38,45 @@ func main() {
 	app.PersistentFlags().DurationVarP(&httpClient.Timeout, "timeout", "t", time.Duration(20)*time.Second, "Set the request timeout")
 	app.PersistentFlags().BoolVar(&c.Dump, "dump", false, "Dump HTTP request and response.")
 
+	// Register signer flags
+	var key string
+	app.PersistentFlags().StringVar(&key, "key", os.Getenv("PINBASE_KEY"), "API key used for authentication, defaults to $PINBASE_KEY")
+	var partyKey string
+	app.PersistentFlags().StringVar(&partyKey, "party-key", os.Getenv("PINBASE_PARTY_KEY"), "Base64 ed25519 private key signing the requests, needed for parties bound to a public key, defaults to $PINBASE_PARTY_KEY")
+
+	// Parse flags and setup signers
+	app.ParseFlags(os.Args)
+	apiKeySigner := newAPIKeySigner(key)
+	if partyKey != "" {
+		t, err := newSigningTransport(partyKey, http.DefaultTransport)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(-1)
+		}
+		httpClient.Transport = t
+	}
+
 	// Initialize API client
+	c.SetAPIKeySigner(apiKeySigner)
 	c.UserAgent = "pinbase-cli/0.1"
 
 	// Register API commands
 	cli.RegisterCommands(app, c)
+	app.AddCommand(newWatchCommand(c, httpClient))
+	app.AddCommand(newPinHistoryCommand(c))
+	app.AddCommand(&cobra.Command{
+		Use:   "party-keygen",
+		Short: "Print a new key pair for binding a party to a public key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pub, priv, err := ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				return err
+			}
+			fmt.Println("public-key: ", base64.StdEncoding.EncodeToString(pub))
+			fmt.Println("private-key:", base64.StdEncoding.EncodeToString(priv))
+			return nil
+		},
+	})
 
 	// Execute!
 	if err := app.Execute(); err != nil {
@@ -45,5 +89,138 @@ func main() {
 func newHTTPClient() *http.Client {
 	// TBD: Change as needed (e.g. to use a different transport to control redirection policy or
 	// disable cert validation or...)
-	return http.DefaultClient
+	return &http.Client{}
+}
+
+// newAPIKeySigner returns the request signer used for authenticating
+// against the api_key security scheme.
+func newAPIKeySigner(key string) goaclient.Signer {
+	return &goaclient.APIKeySigner{
+		SignQuery: false,
+		KeyName:   "X-Pinbase-Key",
+		KeyValue:  key,
+		Format:    "%s",
+	}
+}
+
+// signingTransport signs every request with a party's private key.
+type signingTransport struct {
+	key  ed25519.PrivateKey
+	next http.RoundTripper
+}
+
+func newSigningTransport(key string, next http.RoundTripper) (*signingTransport, error) {
+	k, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(k) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("the party key is not a base64 ed25519 private key")
+	}
+
+	return &signingTransport{key: k, next: next}, nil
+}
+
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signed := req.Clone(req.Context())
+	signed.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	date := time.Now().UTC().Format(time.RFC3339)
+	sig := ed25519.Sign(t.key, pinbase.SigningString(req.Method, req.URL.RequestURI(), date, body))
+	signed.Header.Set(pinbase.SignatureDateHeader, date)
+	signed.Header.Set(pinbase.SignatureHeader, base64.StdEncoding.EncodeToString(sig))
+
+	return t.next.RoundTrip(signed)
+}
+
+// newWatchCommand prints the pin events streamed by the event resource, one
+// JSON object per line, for as long as the stream lasts.
+func newWatchCommand(c *client.Client, httpClient *http.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "watch [partyHash]",
+		Short: "Print the pin status changes of a party, or of every party, as they happen",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// the stream is meant to outlast any request timeout
+			httpClient.Timeout = 0
+
+			var resp *http.Response
+			var err error
+			if len(args) == 1 {
+				resp, err = c.PartyEvent(context.Background(), client.PartyEventPath(args[0]))
+			} else {
+				resp, err = c.StreamEvent(context.Background(), client.StreamEventPath())
+			}
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				b, _ := ioutil.ReadAll(resp.Body)
+				return fmt.Errorf("%d: %s", resp.StatusCode, b)
+			}
+
+			s := bufio.NewScanner(resp.Body)
+			for s.Scan() {
+				if data := strings.TrimPrefix(s.Text(), "data: "); data != s.Text() {
+					fmt.Println(data)
+				}
+			}
+
+			return s.Err()
+		},
+	}
+}
+
+// newPinHistoryCommand prints the history of a pin as a table, one change per
+// line, oldest first.
+func newPinHistoryCommand(c *client.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pin-history partyHash pinHash",
+		Short: "Print the status changes and edits of a pin, oldest first",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := c.HistoryPin(context.Background(), client.HistoryPinPath(args[0], args[1]))
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				b, _ := ioutil.ReadAll(resp.Body)
+				return fmt.Errorf("%d: %s", resp.StatusCode, b)
+			}
+
+			hs, err := c.DecodePinbasePinHistoryCollection(resp)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "TIME\tCHANGE\tSTATUS\tBY\tDETAILS")
+			for _, h := range hs {
+				details := strings.Join(h.Changes, ", ")
+				if h.LastError != "" {
+					details = h.LastError
+				}
+
+				by := h.By
+				if by == "" {
+					by = "-"
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", h.Time.Local().Format(time.RFC3339), h.Change, h.Status, by, details)
+			}
+
+			return w.Flush()
+		},
+	}
 }
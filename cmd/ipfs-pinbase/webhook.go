@@ -0,0 +1,180 @@
+package main
+
+import (
+	"net/url"
+
+	"github.com/apiarian/ipfs-pinbase/cmd/ipfs-pinbase/app"
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/goadesign/goa"
+)
+
+// WebhookController implements the webhook resource.
+type WebhookController struct {
+	*goa.Controller
+	P pinbase.PinProvider
+	W pinbase.WebhookProvider
+}
+
+// NewWebhookController creates a webhook controller.
+func NewWebhookController(service *goa.Service, P pinbase.PinProvider, W pinbase.WebhookProvider) *WebhookController {
+	return &WebhookController{Controller: service.NewController("WebhookController"), P: P, W: W}
+}
+
+// Create runs the create action.
+func (c *WebhookController) Create(ctx *app.CreateWebhookContext) error {
+	// WebhookController_Create: start_implement
+
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RolePartyOwner {
+		return ctx.Forbidden(forbidden(pinbase.RolePartyOwner))
+	}
+
+	u, err := url.Parse(ctx.Payload.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ctx.BadRequest(goa.ErrBadRequest("url is not an http or https URL"))
+	}
+
+	p, err := ps.Party(pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ctx.NotFound()
+	}
+
+	w, secret, err := c.W.WebhookService().CreateWebhook(p.ID, &pinbase.WebhookCreate{
+		URL: ctx.Payload.URL,
+	})
+	if err != nil {
+		return err
+	}
+
+	res := &app.PinbaseWebhookSecret{
+		ID:      w.ID,
+		URL:     w.URL,
+		Created: w.Created,
+		Secret:  &secret,
+	}
+
+	// WebhookController_Create: end_implement
+	ctx.ResponseData.Header().Set("Location", app.WebhookHref(ctx.PartyHash, w.ID))
+	return ctx.CreatedSecret(res)
+}
+
+// Delete runs the delete action.
+func (c *WebhookController) Delete(ctx *app.DeleteWebhookContext) error {
+	// WebhookController_Delete: start_implement
+
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RolePartyOwner {
+		return ctx.Forbidden(forbidden(pinbase.RolePartyOwner))
+	}
+
+	w, err := c.webhook(ps, ctx.PartyHash, ctx.WebhookID)
+	if err != nil {
+		return err
+	}
+	if w == nil {
+		return ctx.NotFound()
+	}
+
+	err = c.W.WebhookService().DeleteWebhook(w.Party, w.ID)
+	if err != nil {
+		return err
+	}
+
+	// WebhookController_Delete: end_implement
+	return nil
+}
+
+// List runs the list action.
+func (c *WebhookController) List(ctx *app.ListWebhookContext) error {
+	// WebhookController_List: start_implement
+
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RoleReadOnly {
+		return ctx.Forbidden(forbidden(pinbase.RoleReadOnly))
+	}
+
+	p, err := ps.Party(pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ctx.NotFound()
+	}
+
+	ws, err := c.W.WebhookService().Webhooks(p.ID)
+	if err != nil {
+		return err
+	}
+
+	res := app.PinbaseWebhookCollection{}
+	for _, w := range ws {
+		res = append(res, pinbaseWebhook(w))
+	}
+
+	// WebhookController_List: end_implement
+	return ctx.OK(res)
+}
+
+// Show runs the show action.
+func (c *WebhookController) Show(ctx *app.ShowWebhookContext) error {
+	// WebhookController_Show: start_implement
+
+	ps := c.P.PinService()
+
+	r, err := partyRole(ctx, ps, pinbase.Hash(ctx.PartyHash))
+	if err != nil {
+		return err
+	}
+	if r < pinbase.RoleReadOnly {
+		return ctx.Forbidden(forbidden(pinbase.RoleReadOnly))
+	}
+
+	w, err := c.webhook(ps, ctx.PartyHash, ctx.WebhookID)
+	if err != nil {
+		return err
+	}
+	if w == nil {
+		return ctx.NotFound()
+	}
+
+	res := pinbaseWebhook(w)
+
+	// WebhookController_Show: end_implement
+	return ctx.OK(res)
+}
+
+// webhook returns the webhook of the party, or nil if either does not exist.
+func (c *WebhookController) webhook(ps pinbase.PinService, partyHash, id string) (*pinbase.WebhookView, error) {
+	p, err := ps.Party(pinbase.Hash(partyHash))
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	return c.W.WebhookService().Webhook(p.ID, id)
+}
+
+func pinbaseWebhook(w *pinbase.WebhookView) *app.PinbaseWebhook {
+	return &app.PinbaseWebhook{
+		ID:      w.ID,
+		URL:     w.URL,
+		Created: w.Created,
+	}
+}
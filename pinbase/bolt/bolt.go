@@ -2,9 +2,12 @@ package bolt
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
 	"encoding/gob"
 	cerrors "errors"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/apiarian/ipfs-pinbase/pinbase"
@@ -13,22 +16,54 @@ import (
 )
 
 var (
-	PartiesBucketKey         = []byte("PARTIES")
-	PartyBucketDataKey       = []byte("DATA")
-	PartyBucketPinsBucketKey = []byte("PINS")
-	PinArchiveBucketKey      = []byte("PIN-ARCHIVE")
+	PartiesBucketKey             = []byte("PARTIES")
+	PartyBucketDataKey           = []byte("DATA")
+	PartyBucketPinsBucketKey     = []byte("PINS")
+	PinArchiveBucketKey          = []byte("PIN-ARCHIVE")
+	PinOwnersBucketKey           = []byte("PIN-OWNERS")
+	NodesBucketKey               = []byte("NODES")
+	KeysBucketKey                = []byte("API-KEYS")
+	PartyBucketWebhooksBucketKey = []byte("WEBHOOKS")
+	PartyBucketHistoryBucketKey  = []byte("PIN-HISTORY")
+	WebhookDeliveriesBucketKey   = []byte("WEBHOOK-DELIVERIES")
+	NameTargetsBucketKey         = []byte("NAME-TARGETS")
+	SchemaBucketKey              = []byte("SCHEMA")
 )
 
 type Client struct {
-	path string
-	db   *bolt.DB
-	bump chan struct{}
+	path  string
+	db    *bolt.DB
+	bump  chan struct{}
+	dirty *dirtySet
+
+	// Retry controls the backoff between failed pin attempts. Change it
+	// before handing out services.
+	Retry pinbase.RetryPolicy
+
+	// Sizer is used to check pins against the byte quotas of their parties.
+	// Without one only the pin count quotas are enforced.
+	Sizer pinbase.PinSizer
+
+	// Events is told about the pin status changes the pin processor reports,
+	// if set.
+	Events pinbase.PinEventPublisher
+
+	// ResolveInterval is how often the names of name pins are resolved, and
+	// NameGrace how long they hold on to a target after their name moves on
+	// from it. Change them before handing out services.
+	ResolveInterval time.Duration
+	NameGrace       time.Duration
 }
 
 func NewClient(path string) *Client {
 	return &Client{
-		path: path,
-		bump: make(chan struct{}),
+		path:  path,
+		bump:  make(chan struct{}),
+		dirty: newDirtySet(),
+		Retry: pinbase.DefaultRetryPolicy,
+
+		ResolveInterval: time.Minute,
+		NameGrace:       time.Hour,
 	}
 }
 
@@ -73,28 +108,184 @@ func setupSchema(tx *bolt.Tx) error {
 		return errors.Wrap(err, "create pin archive bucket")
 	}
 
-	return nil
+	_, err = tx.CreateBucketIfNotExists(NodesBucketKey)
+	if err != nil {
+		return errors.Wrap(err, "create nodes bucket")
+	}
+
+	_, err = tx.CreateBucketIfNotExists(KeysBucketKey)
+	if err != nil {
+		return errors.Wrap(err, "create keys bucket")
+	}
+
+	_, err = tx.CreateBucketIfNotExists(WebhookDeliveriesBucketKey)
+	if err != nil {
+		return errors.Wrap(err, "create webhook deliveries bucket")
+	}
+
+	_, err = tx.CreateBucketIfNotExists(NameTargetsBucketKey)
+	if err != nil {
+		return errors.Wrap(err, "create name targets bucket")
+	}
+
+	if tx.Bucket(PinOwnersBucketKey) == nil {
+		owners, err := tx.CreateBucket(PinOwnersBucketKey)
+		if err != nil {
+			return errors.Wrap(err, "create pin owners bucket")
+		}
+
+		// databases from before the index existed need it filled in
+		err = indexPinOwners(tx, owners)
+		if err != nil {
+			return errors.Wrap(err, "index pin owners")
+		}
+	}
+
+	return migrateSchema(tx)
 }
 
 func (c *Client) PinService() pinbase.PinService {
 	return &PinService{
-		db:   c.db,
-		bump: c.bump,
+		db:     c.db,
+		bump:   c.bump,
+		dirty:  c.dirty,
+		retry:  c.Retry,
+		sizer:  c.Sizer,
+		events: c.Events,
+
+		resolveInterval: c.ResolveInterval,
+		nameGrace:       c.NameGrace,
 	}
 }
 
 func (c *Client) PinBackend() pinbase.PinBackend {
 	return &PinService{
-		db:   c.db,
-		bump: c.bump,
+		db:     c.db,
+		bump:   c.bump,
+		dirty:  c.dirty,
+		retry:  c.Retry,
+		sizer:  c.Sizer,
+		events: c.Events,
+
+		resolveInterval: c.ResolveInterval,
+		nameGrace:       c.NameGrace,
+	}
+}
+
+func (c *Client) NodeService() pinbase.NodeService {
+	return &NodeService{
+		db: c.db,
+	}
+}
+
+func (c *Client) KeyService() pinbase.KeyService {
+	return &KeyService{
+		db: c.db,
+	}
+}
+
+func (c *Client) WebhookService() pinbase.WebhookService {
+	return &WebhookService{
+		db:    c.db,
+		retry: c.Retry,
+	}
+}
+
+func (c *Client) WebhookQueue() pinbase.WebhookQueue {
+	return &WebhookService{
+		db:    c.db,
+		retry: c.Retry,
 	}
 }
 
 var _ pinbase.PinProvider = &Client{}
+var _ pinbase.NodeProvider = &Client{}
+var _ pinbase.KeyProvider = &Client{}
+var _ pinbase.WebhookProvider = &Client{}
 
 type PinService struct {
-	db   *bolt.DB
-	bump chan struct{}
+	db     *bolt.DB
+	bump   chan struct{}
+	dirty  *dirtySet
+	retry  pinbase.RetryPolicy
+	sizer  pinbase.PinSizer
+	events pinbase.PinEventPublisher
+
+	resolveInterval time.Duration
+	nameGrace       time.Duration
+}
+
+// sizeTimeout limits how long sizing a pin for a quota check may take.
+var sizeTimeout = 30 * time.Second
+
+// dirtySet collects the hashes changed since the pin processor last asked for
+// requirements, and the first time one of the pins changed since it last asked
+// for the next pin change changes by itself. It is shared by all the services
+// handed out by a Client.
+type dirtySet struct {
+	m      *sync.Mutex
+	hashes map[pinbase.Hash]struct{}
+	next   time.Time
+}
+
+func newDirtySet() *dirtySet {
+	return &dirtySet{
+		m:      &sync.Mutex{},
+		hashes: make(map[pinbase.Hash]struct{}),
+	}
+}
+
+func (d *dirtySet) add(hs ...pinbase.Hash) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	for _, h := range hs {
+		d.hashes[h] = struct{}{}
+	}
+}
+
+func (d *dirtySet) take() map[pinbase.Hash]struct{} {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	hs := d.hashes
+	d.hashes = make(map[pinbase.Hash]struct{})
+
+	return hs
+}
+
+// schedule notes that a pin next changes by itself at t, zero meaning never.
+func (d *dirtySet) schedule(t time.Time) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if !t.IsZero() && (d.next.IsZero() || t.Before(d.next)) {
+		d.next = t
+	}
+}
+
+func (d *dirtySet) takeNext() time.Time {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	next := d.next
+	d.next = time.Time{}
+
+	return next
+}
+
+// bumpPins marks the hashes as dirty and wakes up the pin processor.
+func (ps *PinService) bumpPins(hs ...pinbase.Hash) {
+	ps.dirty.add(hs...)
+
+	go func(c chan<- struct{}) { c <- struct{}{} }(ps.bump)
+}
+
+// sequenceKey turns a bucket sequence into a key that sorts in order.
+func sequenceKey(seq uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, seq)
+	return k
 }
 
 //
@@ -112,6 +303,17 @@ func getPartiesBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
 
 type partyStorage struct {
 	Description string
+	MaxPins     int
+	MaxBytes    int64
+	Grants      map[string]pinbase.Role
+	PublicKey   []byte
+}
+
+func (p *partyStorage) quota() pinbase.PartyQuota {
+	return pinbase.PartyQuota{
+		MaxPins:  p.MaxPins,
+		MaxBytes: p.MaxBytes,
+	}
 }
 
 func extractPartyStorage(party *bolt.Bucket) (*partyStorage, error) {
@@ -157,6 +359,153 @@ func getArchiveBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
 
 var sentinel = []byte("x")
 
+// archiveStorage tracks the unpinning of a hash no party holds anymore. The
+// entry is dropped once the node reports the hash as unpinned.
+type archiveStorage struct {
+	Status           pinbase.PinStatus
+	LastErrorMessage string
+}
+
+func extractArchiveStorage(data []byte) (*archiveStorage, error) {
+	// older databases only stored a sentinel for archived pins
+	if bytes.Equal(data, sentinel) {
+		return &archiveStorage{Status: pinbase.PinPending}, nil
+	}
+
+	var a archiveStorage
+	err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&a)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode archive data")
+	}
+
+	return &a, nil
+}
+
+func writeArchiveStorage(archive *bolt.Bucket, h pinbase.Hash, a *archiveStorage) error {
+	var b bytes.Buffer
+	enc := gob.NewEncoder(&b)
+
+	err := enc.Encode(a)
+	if err != nil {
+		return errors.Wrap(err, "encode archive data")
+	}
+
+	err = archive.Put([]byte(h), b.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "put archive data")
+	}
+
+	return nil
+}
+
+// The pin owners bucket holds a bucket for every pinned hash, which in turn
+// holds a key for every party that has a pin for that hash.
+
+func getOwnersBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	o := tx.Bucket(PinOwnersBucketKey)
+	if o == nil {
+		return nil, errors.New("no pin owners bucket found")
+	}
+
+	return o, nil
+}
+
+func indexPinOwners(tx *bolt.Tx, owners *bolt.Bucket) error {
+	parties, err := getPartiesBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	partiesC := parties.Cursor()
+
+	for partyK, partyV := partiesC.First(); partyK != nil; partyK, partyV = partiesC.Next() {
+		if partyV != nil {
+			return errors.New("found a non-bucket party")
+		}
+
+		party := parties.Bucket(partyK)
+		if party == nil {
+			return errors.New("did not get party bucket")
+		}
+
+		pins := party.Bucket(PartyBucketPinsBucketKey)
+		if pins == nil {
+			return errors.New("did not get a pins bucket")
+		}
+
+		pinsC := pins.Cursor()
+
+		for pinK, pinV := pinsC.First(); pinK != nil; pinK, pinV = pinsC.Next() {
+			p, err := extractPinStorage(pinV)
+			if err != nil {
+				return err
+			}
+			if p.Kind == pinbase.PinKindName {
+				continue
+			}
+
+			err = addPinOwner(owners, pinbase.Hash(pinK), pinbase.Hash(partyK))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func addPinOwner(owners *bolt.Bucket, pinID, partyID pinbase.Hash) error {
+	o, err := owners.CreateBucketIfNotExists([]byte(pinID))
+	if err != nil {
+		return errors.Wrapf(err, "create owners bucket for pin %s", pinID)
+	}
+
+	return errors.Wrapf(o.Put([]byte(partyID), sentinel), "add owner of pin %s", pinID)
+}
+
+// removePinOwner drops partyID from the owners of pinID and reports whether
+// any other party still holds the pin.
+func removePinOwner(owners *bolt.Bucket, pinID, partyID pinbase.Hash) (bool, error) {
+	pinKey := []byte(pinID)
+
+	o := owners.Bucket(pinKey)
+	if o == nil {
+		return false, nil
+	}
+
+	err := o.Delete([]byte(partyID))
+	if err != nil {
+		return false, errors.Wrapf(err, "remove owner of pin %s", pinID)
+	}
+
+	if k, _ := o.Cursor().First(); k != nil {
+		return true, nil
+	}
+
+	err = owners.DeleteBucket(pinKey)
+	if err != nil {
+		return false, errors.Wrapf(err, "delete owners bucket for pin %s", pinID)
+	}
+
+	return false, nil
+}
+
+func pinOwners(owners *bolt.Bucket, pinID pinbase.Hash) []pinbase.Hash {
+	o := owners.Bucket([]byte(pinID))
+	if o == nil {
+		return nil
+	}
+
+	var list []pinbase.Hash
+
+	c := o.Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		list = append(list, pinbase.Hash(k))
+	}
+
+	return list
+}
+
 func (ps *PinService) Parties() ([]*pinbase.PartyView, error) {
 	if ps.db == nil {
 		return nil, errors.New("no database connection")
@@ -187,9 +536,18 @@ func (ps *PinService) Parties() ([]*pinbase.PartyView, error) {
 				return err
 			}
 
+			usage, err := partyUsage(party, "")
+			if err != nil {
+				return err
+			}
+
 			pv := &pinbase.PartyView{
 				ID:          pinbase.Hash(k),
 				Description: ps.Description,
+				Quota:       ps.quota(),
+				Usage:       usage,
+				Grants:      ps.Grants,
+				PublicKey:   ps.PublicKey,
 			}
 
 			list = append(list, pv)
@@ -224,9 +582,18 @@ func (ps *PinService) Party(h pinbase.Hash) (*pinbase.PartyView, error) {
 			return err
 		}
 
+		usage, err := partyUsage(party, "")
+		if err != nil {
+			return err
+		}
+
 		p = &pinbase.PartyView{
 			ID:          h,
 			Description: ps.Description,
+			Quota:       ps.quota(),
+			Usage:       usage,
+			Grants:      ps.Grants,
+			PublicKey:   ps.PublicKey,
 		}
 
 		return nil
@@ -262,6 +629,9 @@ func (ps *PinService) CreateParty(p *pinbase.PartyCreate) error {
 			newParty,
 			&partyStorage{
 				Description: p.Description,
+				MaxPins:     p.Quota.MaxPins,
+				MaxBytes:    p.Quota.MaxBytes,
+				PublicKey:   p.PublicKey,
 			},
 		)
 		if err != nil {
@@ -282,7 +652,7 @@ func (ps *PinService) DeleteParty(h pinbase.Hash) error {
 		return errors.New("no database connection")
 	}
 
-	var pinsDeleted bool
+	var oldPins []pinbase.Hash
 
 	err := ps.db.Update(func(tx *bolt.Tx) error {
 		parties, err := getPartiesBucket(tx)
@@ -303,11 +673,16 @@ func (ps *PinService) DeleteParty(h pinbase.Hash) error {
 			return errors.New("did not get a pins bucket")
 		}
 
-		oldPins := make(map[pinbase.Hash]struct{})
+		stored := make(map[pinbase.Hash]*pinStorage)
 
 		c := pins.Cursor()
-		for k, _ := c.First(); k != nil; k, _ = c.Next() {
-			oldPins[pinbase.Hash(k)] = struct{}{}
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			p, err := extractPinStorage(v)
+			if err != nil {
+				return errors.Wrapf(err, "extract pin %s", k)
+			}
+
+			stored[pinbase.Hash(k)] = p
 		}
 
 		err = parties.DeleteBucket(partyKey)
@@ -315,19 +690,14 @@ func (ps *PinService) DeleteParty(h pinbase.Hash) error {
 			return errors.Wrap(err, "delete party bucket")
 		}
 
-		archive, err := getArchiveBucket(tx)
-		if err != nil {
-			return err
-		}
-
-		for h, _ := range oldPins {
-			err := archive.Put([]byte(h), sentinel)
+		for pinID, p := range stored {
+			hs, err := releasePin(tx, h, pinID, p)
 			if err != nil {
-				return errors.Wrapf(err, "archive pin %s", h)
+				return err
 			}
-		}
 
-		pinsDeleted = len(oldPins) > 0
+			oldPins = append(oldPins, hs...)
+		}
 
 		return nil
 	})
@@ -336,8 +706,8 @@ func (ps *PinService) DeleteParty(h pinbase.Hash) error {
 		return err
 	}
 
-	if pinsDeleted {
-		go func(c chan<- struct{}) { c <- struct{}{} }(ps.bump)
+	if len(oldPins) > 0 {
+		ps.bumpPins(oldPins...)
 	}
 
 	return nil
@@ -365,102 +735,465 @@ func (ps *PinService) UpdateParty(h pinbase.Hash, p *pinbase.PartyEdit) error {
 		}
 
 		ps.Description = p.Description
+		ps.MaxPins = p.Quota.MaxPins
+		ps.MaxBytes = p.Quota.MaxBytes
 
 		return writePartyStorage(party, ps)
 	})
 }
 
-func getPinsBucket(tx *bolt.Tx, h pinbase.Hash) (*bolt.Bucket, error) {
-	parties, err := getPartiesBucket(tx)
-	if err != nil {
-		return nil, err
-	}
-
-	party := parties.Bucket([]byte(h))
-	if party == nil {
-		return nil, errors.New("could not find party")
-	}
+// partyUsage adds up the pins of the party, leaving out skip.
+func partyUsage(party *bolt.Bucket, skip pinbase.Hash) (pinbase.PartyUsage, error) {
+	var u pinbase.PartyUsage
 
 	pins := party.Bucket(PartyBucketPinsBucketKey)
 	if pins == nil {
-		return nil, errors.New("did not get a pins bucket")
+		return u, errors.New("did not get a pins bucket")
 	}
 
-	return pins, nil
-}
+	c := pins.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if pinbase.Hash(k) == skip {
+			continue
+		}
 
-type pinStorage struct {
-	Aliases          []string
-	WantPinned       bool
-	Status           pinbase.PinStatus
-	LastErrorMessage string
-}
+		p, err := extractPinStorage(v)
+		if err != nil {
+			return u, errors.Wrapf(err, "extract pin %s", k)
+		}
 
-func extractPinStorage(data []byte) (*pinStorage, error) {
-	var p pinStorage
-	err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&p)
-	if err != nil {
-		return nil, errors.Wrap(err, "decode pin data")
+		if p.WantPinned {
+			u.Pins++
+			u.Bytes += p.Size
+		}
+
+		if p.Status == pinbase.PinPinned {
+			u.PinnedPins++
+			u.PinnedBytes += p.Size
+		}
 	}
 
-	return &p, nil
+	return u, nil
 }
 
-func writePinStorage(pins *bolt.Bucket, h pinbase.Hash, p *pinStorage) error {
-	var b bytes.Buffer
-	enc := gob.NewEncoder(&b)
+// checkQuota makes sure the party can take on the pin as a wanted pin of the
+// given size, on top of all its other wanted pins.
+func checkQuota(tx *bolt.Tx, partyID, pinID pinbase.Hash, size int64) error {
+	parties, err := getPartiesBucket(tx)
+	if err != nil {
+		return err
+	}
 
-	err := enc.Encode(p)
+	party := parties.Bucket([]byte(partyID))
+	if party == nil {
+		return errors.New("could not find party")
+	}
+
+	p, err := extractPartyStorage(party)
 	if err != nil {
-		return errors.Wrap(err, "encode pin data")
+		return err
 	}
 
-	err = pins.Put([]byte(h), b.Bytes())
+	if p.MaxPins == 0 && p.MaxBytes == 0 {
+		return nil
+	}
+
+	u, err := partyUsage(party, pinID)
 	if err != nil {
-		return errors.Wrap(err, "put pin data")
+		return err
+	}
+
+	if p.MaxPins > 0 && u.Pins+1 > p.MaxPins {
+		return errors.Wrapf(
+			pinbase.ErrQuotaExceeded,
+			"party %s already wants %d of its %d pins",
+			partyID, u.Pins, p.MaxPins,
+		)
+	}
+
+	if p.MaxBytes > 0 && u.Bytes+size > p.MaxBytes {
+		return errors.Wrapf(
+			pinbase.ErrQuotaExceeded,
+			"pin of %d bytes does not fit in the %d bytes left of party %s's %d",
+			size, p.MaxBytes-u.Bytes, partyID, p.MaxBytes,
+		)
 	}
 
 	return nil
 }
 
-func (ps *PinService) Pins(partyID pinbase.Hash) ([]*pinbase.PinView, error) {
-	if ps.db == nil {
-		return nil, errors.New("no database connection")
+// pinSize looks up the size of a wanted pin when its party has a byte quota
+// to check it against, and is zero otherwise.
+func (ps *PinService) pinSize(partyID, pinID pinbase.Hash, m pinbase.PinMode, wanted bool) (int64, error) {
+	if !wanted || ps.sizer == nil {
+		return 0, nil
 	}
 
-	var list []*pinbase.PinView
+	var maxBytes int64
 
 	err := ps.db.View(func(tx *bolt.Tx) error {
-		pins, err := getPinsBucket(tx, partyID)
+		parties, err := getPartiesBucket(tx)
 		if err != nil {
 			return err
 		}
 
-		c := pins.Cursor()
+		party := parties.Bucket([]byte(partyID))
+		if party == nil {
+			return errors.New("could not find party")
+		}
 
-		for k, v := c.First(); k != nil; k, v = c.Next() {
-			if v == nil {
-				return errors.New("found a bucket pin")
-			}
+		p, err := extractPartyStorage(party)
+		if err != nil {
+			return err
+		}
 
-			ps, err := extractPinStorage(v)
-			if err != nil {
-				return err
-			}
+		maxBytes = p.MaxBytes
 
-			pv := &pinbase.PinView{
-				ID:         pinbase.Hash(k),
-				Aliases:    ps.Aliases,
-				WantPinned: ps.WantPinned,
-				Status:     ps.Status,
-				LastError:  nil,
-			}
+		// name pins take up whatever their name resolved to
+		if pins := party.Bucket(PartyBucketPinsBucketKey); pins != nil {
+			if pin := pins.Get([]byte(pinID)); pin != nil {
+				ps, err := extractPinStorage(pin)
+				if err != nil {
+					return err
+				}
 
-			if ps.LastErrorMessage != "" {
-				pv.LastError = cerrors.New(ps.LastErrorMessage)
+				if ps.Kind == pinbase.PinKindName {
+					pinID = ps.Target
+				}
 			}
-
-			list = append(list, pv)
+		}
+
+		return nil
+	})
+	if err != nil || maxBytes == 0 || pinID == "" {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sizeTimeout)
+	defer cancel()
+
+	size, err := ps.sizer.PinSize(ctx, pinID, m)
+
+	return size, errors.Wrapf(err, "size pin %s", pinID)
+}
+
+func (ps *PinService) GrantParty(partyID pinbase.Hash, keyID string, r pinbase.Role) error {
+	if ps.db == nil {
+		return errors.New("no database connection")
+	}
+
+	if r == pinbase.RoleAdmin {
+		return errors.New("admin is not a party role")
+	}
+
+	return ps.db.Update(func(tx *bolt.Tx) error {
+		parties, err := getPartiesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		party := parties.Bucket([]byte(partyID))
+		if party == nil {
+			return errors.New("could not find party")
+		}
+
+		p, err := extractPartyStorage(party)
+		if err != nil {
+			return err
+		}
+
+		if r == pinbase.RoleNone {
+			delete(p.Grants, keyID)
+		} else {
+			if p.Grants == nil {
+				p.Grants = make(map[string]pinbase.Role)
+			}
+			p.Grants[keyID] = r
+		}
+
+		return writePartyStorage(party, p)
+	})
+}
+
+func getPinsBucket(tx *bolt.Tx, h pinbase.Hash) (*bolt.Bucket, error) {
+	parties, err := getPartiesBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	party := parties.Bucket([]byte(h))
+	if party == nil {
+		return nil, errors.New("could not find party")
+	}
+
+	pins := party.Bucket(PartyBucketPinsBucketKey)
+	if pins == nil {
+		return nil, errors.New("did not get a pins bucket")
+	}
+
+	return pins, nil
+}
+
+// canonicalPinID is the form of the ID a pin of the kind is kept under: the
+// canonical form of the hash for hash pins, see pinbase.Hash.Canonical, and
+// of the IPNS key for name pins, see pinbase.CanonicalName.
+func canonicalPinID(kind pinbase.PinKind, id pinbase.Hash) pinbase.Hash {
+	if kind == pinbase.PinKindName {
+		return pinbase.Hash(pinbase.CanonicalName(string(id)))
+	}
+	return id.Canonical()
+}
+
+// storedPinID finds the form of pinID the pin or the pin history of the party
+// is kept under, since a peer ID in CIDv0 form names a hash pin and a name pin
+// by different canonical IDs. It falls back to the canonical hash.
+func (ps *PinService) storedPinID(partyID, pinID pinbase.Hash) pinbase.Hash {
+	ids := []pinbase.Hash{
+		canonicalPinID(pinbase.PinKindHash, pinID),
+		canonicalPinID(pinbase.PinKindName, pinID),
+	}
+	if ids[0] == ids[1] {
+		return ids[0]
+	}
+
+	found := ids[0]
+	ps.db.View(func(tx *bolt.Tx) error {
+		parties, err := getPartiesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		party := parties.Bucket([]byte(partyID))
+		if party == nil {
+			return nil
+		}
+
+		for _, key := range [][]byte{PartyBucketPinsBucketKey, PartyBucketHistoryBucketKey} {
+			b := party.Bucket(key)
+			if b == nil {
+				continue
+			}
+
+			for _, id := range ids {
+				if k, _ := b.Cursor().Seek([]byte(id)); bytes.Equal(k, []byte(id)) {
+					found = id
+					return nil
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return found
+}
+
+type pinStorage struct {
+	Aliases          []string
+	WantPinned       bool
+	Mode             pinbase.PinMode
+	Replication      int
+	Status           pinbase.PinStatus
+	LastErrorMessage string
+	Attempts         int
+	NextAttempt      time.Time
+	Progress         pinbase.PinProgress
+	Nodes            map[string]nodePinStorage
+	Size             int64
+	ExpiresAt        time.Time
+	NotBefore        time.Time
+	NotAfter         time.Time
+	Kind             pinbase.PinKind
+	Path             string
+	Target           pinbase.Hash
+	Previous         []previousTargetStorage
+	NextResolve      time.Time
+	Resolutions      []resolutionStorage
+}
+
+type nodePinStorage struct {
+	Status           pinbase.PinStatus
+	LastErrorMessage string
+}
+
+func (p *pinStorage) nodeStates() map[string]pinbase.NodePinState {
+	if len(p.Nodes) == 0 {
+		return nil
+	}
+
+	m := make(map[string]pinbase.NodePinState)
+	for name, n := range p.Nodes {
+		nps := pinbase.NodePinState{
+			Status:    n.Status,
+			LastError: nil,
+		}
+
+		if n.LastErrorMessage != "" {
+			nps.LastError = cerrors.New(n.LastErrorMessage)
+		}
+
+		m[name] = nps
+	}
+
+	return m
+}
+
+func (p *pinStorage) updateNodes(s *pinbase.PinBackendState) {
+	if s.Nodes == nil {
+		return
+	}
+
+	// progress updates only carry the node that is fetching
+	if s.Status != pinbase.PinPinning || p.Nodes == nil {
+		p.Nodes = make(map[string]nodePinStorage)
+	}
+
+	for name, nps := range s.Nodes {
+		n := nodePinStorage{
+			Status:           nps.Status,
+			LastErrorMessage: "",
+		}
+
+		if nps.LastError != nil {
+			n.LastErrorMessage = nps.LastError.Error()
+		}
+
+		p.Nodes[name] = n
+	}
+}
+
+// deferred reports whether the pin should be left alone for now, either
+// because it is waiting out a retry delay or because it has failed for good.
+func (p *pinStorage) deferred(now time.Time) bool {
+	return p.Status == pinbase.PinFatal || p.NextAttempt.After(now)
+}
+
+// requirement is what the pin asks of the nodes at now, which is nothing while
+// now is outside of its window.
+func (p *pinStorage) requirement(now time.Time) pinbase.PinRequirement {
+	return pinbase.PinRequirement{
+		WantPinned:  p.WantPinned && pinbase.InPinWindow(p.NotBefore, p.NotAfter, now),
+		Mode:        p.Mode,
+		Replication: p.Replication,
+		Sized:       p.Size > 0,
+	}
+}
+
+// nextChange returns the first time after now that the wanted pin expires, has
+// its window open or close, has its name due to be resolved or lets go of a
+// previous target, now itself if it is due to expire or have its name resolved
+// already, or zero if there is nothing coming.
+func (p *pinStorage) nextChange(now time.Time) time.Time {
+	var next time.Time
+
+	consider := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+
+	if p.WantPinned {
+		// expiries that are due already need sweeping right away
+		if !p.ExpiresAt.IsZero() && !p.ExpiresAt.After(now) {
+			return now
+		}
+		consider(p.ExpiresAt)
+		consider(p.NotBefore)
+		consider(p.NotAfter)
+	}
+
+	if p.Kind == pinbase.PinKindName && p.WantPinned {
+		// names that are due already need resolving right away
+		if !p.NextResolve.After(now) {
+			return now
+		}
+		consider(p.NextResolve)
+	}
+
+	for _, prev := range p.Previous {
+		consider(prev.Until)
+	}
+
+	return next
+}
+
+func extractPinStorage(data []byte) (*pinStorage, error) {
+	var p pinStorage
+	err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&p)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode pin data")
+	}
+
+	return &p, nil
+}
+
+func writePinStorage(pins *bolt.Bucket, h pinbase.Hash, p *pinStorage) error {
+	var b bytes.Buffer
+	enc := gob.NewEncoder(&b)
+
+	err := enc.Encode(p)
+	if err != nil {
+		return errors.Wrap(err, "encode pin data")
+	}
+
+	err = pins.Put([]byte(h), b.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "put pin data")
+	}
+
+	return nil
+}
+
+func (ps *PinService) Pins(partyID pinbase.Hash) ([]*pinbase.PinView, error) {
+	if ps.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	var list []*pinbase.PinView
+
+	err := ps.db.View(func(tx *bolt.Tx) error {
+		pins, err := getPinsBucket(tx, partyID)
+		if err != nil {
+			return err
+		}
+
+		c := pins.Cursor()
+
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if v == nil {
+				return errors.New("found a bucket pin")
+			}
+
+			ps, err := extractPinStorage(v)
+			if err != nil {
+				return err
+			}
+
+			pv := &pinbase.PinView{
+				ID:          pinbase.Hash(k),
+				Kind:        ps.Kind,
+				Path:        ps.Path,
+				Aliases:     ps.Aliases,
+				WantPinned:  ps.WantPinned,
+				Mode:        ps.Mode,
+				Replication: ps.Replication,
+				Status:      ps.Status,
+				LastError:   nil,
+				Progress:    ps.Progress,
+				Nodes:       ps.nodeStates(),
+				Size:        ps.Size,
+				ExpiresAt:   ps.ExpiresAt,
+				NotBefore:   ps.NotBefore,
+				NotAfter:    ps.NotAfter,
+				Target:      ps.Target,
+				Resolutions: ps.resolutions(),
+			}
+
+			if ps.LastErrorMessage != "" {
+				pv.LastError = cerrors.New(ps.LastErrorMessage)
+			}
+
+			list = append(list, pv)
 		}
 
 		return nil
@@ -474,6 +1207,8 @@ func (ps *PinService) Pin(partyID, pinID pinbase.Hash) (*pinbase.PinView, error)
 		return nil, errors.New("no database connection")
 	}
 
+	pinID = ps.storedPinID(partyID, pinID)
+
 	var p *pinbase.PinView
 
 	err := ps.db.View(func(tx *bolt.Tx) error {
@@ -494,11 +1229,23 @@ func (ps *PinService) Pin(partyID, pinID pinbase.Hash) (*pinbase.PinView, error)
 		}
 
 		p = &pinbase.PinView{
-			ID:         pinID,
-			Aliases:    ps.Aliases,
-			WantPinned: ps.WantPinned,
-			Status:     ps.Status,
-			LastError:  nil,
+			ID:          pinID,
+			Kind:        ps.Kind,
+			Path:        ps.Path,
+			Aliases:     ps.Aliases,
+			WantPinned:  ps.WantPinned,
+			Mode:        ps.Mode,
+			Replication: ps.Replication,
+			Status:      ps.Status,
+			LastError:   nil,
+			Progress:    ps.Progress,
+			Nodes:       ps.nodeStates(),
+			Size:        ps.Size,
+			ExpiresAt:   ps.ExpiresAt,
+			NotBefore:   ps.NotBefore,
+			NotAfter:    ps.NotAfter,
+			Target:      ps.Target,
+			Resolutions: ps.resolutions(),
 		}
 
 		if ps.LastErrorMessage != "" {
@@ -516,7 +1263,32 @@ func (ps *PinService) CreatePin(partyID pinbase.Hash, pc *pinbase.PinCreate) err
 		return errors.New("no database connection")
 	}
 
-	err := ps.db.Update(func(tx *bolt.Tx) error {
+	// name pins are checked as their names resolve
+	if pc.Kind == pinbase.PinKindHash {
+		_, err := pinbase.ParseHash(string(pc.ID))
+		if err != nil {
+			return err
+		}
+	}
+
+	canonical := *pc
+	canonical.ID = canonicalPinID(pc.Kind, pc.ID)
+	pc = &canonical
+
+	var size int64
+	var err error
+
+	// there is no telling how big a name pin is until its name resolves
+	if pc.Kind == pinbase.PinKindHash {
+		size, err = ps.pinSize(partyID, pc.ID, pc.Mode, pc.WantPinned)
+		if err != nil {
+			return err
+		}
+	}
+
+	var change time.Time
+
+	err = ps.db.Update(func(tx *bolt.Tx) error {
 		pins, err := getPinsBucket(tx, partyID)
 		if err != nil {
 			return err
@@ -529,32 +1301,105 @@ func (ps *PinService) CreatePin(partyID pinbase.Hash, pc *pinbase.PinCreate) err
 			return errors.New("pin already exists")
 		}
 
-		return writePinStorage(
-			pins,
-			pc.ID,
-			&pinStorage{
-				Aliases:          pc.Aliases,
-				WantPinned:       pc.WantPinned,
-				Status:           pinbase.PinPending,
-				LastErrorMessage: "",
-			},
-		)
+		if pc.WantPinned {
+			err = checkQuota(tx, partyID, pc.ID, size)
+			if err != nil {
+				return err
+			}
+		}
+
+		p := &pinStorage{
+			Kind:             pc.Kind,
+			Path:             pc.Path,
+			Aliases:          pc.Aliases,
+			WantPinned:       pc.WantPinned,
+			Mode:             pc.Mode,
+			Replication:      pc.Replication,
+			Status:           pinbase.PinPending,
+			LastErrorMessage: "",
+			Size:             size,
+			ExpiresAt:        pc.ExpiresAt,
+			NotBefore:        pc.NotBefore,
+			NotAfter:         pc.NotAfter,
+		}
+		change = p.nextChange(time.Now())
+
+		err = writePinStorage(pins, pc.ID, p)
+		if err != nil {
+			return err
+		}
+
+		err = appendPinHistory(tx, partyID, pc.ID, &historyStorage{
+			Change: pinbase.PinChangeCreated,
+			By:     pc.By,
+			Status: pinbase.PinPending,
+			Changes: pinSettings(&pinStorage{
+				Kind:        pc.Kind,
+				Path:        pc.Path,
+				Aliases:     pc.Aliases,
+				WantPinned:  pc.WantPinned,
+				Mode:        pc.Mode,
+				Replication: pc.Replication,
+				ExpiresAt:   pc.ExpiresAt,
+				NotBefore:   pc.NotBefore,
+				NotAfter:    pc.NotAfter,
+			}),
+		})
+		if err != nil {
+			return err
+		}
+
+		// name pins hold nothing until their name resolves
+		if pc.Kind == pinbase.PinKindName {
+			return nil
+		}
+
+		owners, err := getOwnersBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		err = addPinOwner(owners, pc.ID, partyID)
+		if err != nil {
+			return err
+		}
+
+		archive, err := getArchiveBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		// the pin has an owner again, so it is no longer the archive's concern
+		return errors.Wrap(archive.Delete(pinKey), "unarchive the pin")
 	})
 	if err != nil {
 		return err
 	}
 
-	go func(c chan<- struct{}) { c <- struct{}{} }(ps.bump)
+	ps.dirty.schedule(change)
+
+	if pc.Kind == pinbase.PinKindName {
+		// wake up the pin processor to resolve the name
+		ps.bumpPins()
+	} else {
+		ps.bumpPins(pc.ID)
+	}
 
 	return nil
 }
 
-func (ps *PinService) DeletePin(partyID, pinID pinbase.Hash) error {
+func (ps *PinService) DeletePin(partyID, pinID pinbase.Hash, by string) error {
 	if ps.db == nil {
 		return errors.New("no database connection")
 	}
 
+	pinID = ps.storedPinID(partyID, pinID)
+
+	var hashes []pinbase.Hash
+
 	err := ps.db.Update(func(tx *bolt.Tx) error {
+		hashes = nil
+
 		pins, err := getPinsBucket(tx, partyID)
 		if err != nil {
 			return err
@@ -568,246 +1413,1002 @@ func (ps *PinService) DeletePin(partyID, pinID pinbase.Hash) error {
 			return nil
 		}
 
-		err = pins.Delete(pinKey)
-		if err != nil {
-			return errors.Wrap(err, "delete pin data")
+		p, err := extractPinStorage(pin)
+		if err != nil {
+			return err
+		}
+
+		err = pins.Delete(pinKey)
+		if err != nil {
+			return errors.Wrap(err, "delete pin data")
+		}
+
+		err = appendPinHistory(tx, partyID, pinID, &historyStorage{
+			Change:           pinbase.PinChangeDeleted,
+			By:               by,
+			Status:           p.Status,
+			LastErrorMessage: p.LastErrorMessage,
+		})
+		if err != nil {
+			return err
+		}
+
+		hashes, err = releasePin(tx, partyID, pinID, p)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(hashes) > 0 {
+		ps.bumpPins(hashes...)
+	}
+
+	return nil
+}
+
+func (ps *PinService) UpdatePin(partyID, pinID pinbase.Hash, pe *pinbase.PinEdit) error {
+	if ps.db == nil {
+		return errors.New("no database connection")
+	}
+
+	pinID = ps.storedPinID(partyID, pinID)
+
+	size, err := ps.pinSize(partyID, pinID, pe.Mode, pe.WantPinned)
+	if err != nil {
+		return err
+	}
+
+	var wantChanged bool
+	var hashes []pinbase.Hash
+	var change time.Time
+
+	err = ps.db.Update(func(tx *bolt.Tx) error {
+		pins, err := getPinsBucket(tx, partyID)
+		if err != nil {
+			return err
+		}
+
+		pin := pins.Get([]byte(pinID))
+		if pin == nil {
+			return errors.New("could not find pin")
+		}
+
+		ps, err := extractPinStorage(pin)
+		if err != nil {
+			return err
+		}
+
+		hashes = ps.hashes(pinID)
+
+		// a new expiry or window can change the requirement right away,
+		// and otherwise brings a new pin change for the processor to wait on
+		if ps.WantPinned != pe.WantPinned || ps.Mode != pe.Mode || ps.Replication != pe.Replication ||
+			!ps.ExpiresAt.Equal(pe.ExpiresAt) || !ps.NotBefore.Equal(pe.NotBefore) || !ps.NotAfter.Equal(pe.NotAfter) {
+			wantChanged = true
+		}
+
+		changes := pinChanges(ps, pe)
+
+		// a party over its quota can still edit the pins it already wants
+		if pe.WantPinned && (!ps.WantPinned || size > ps.Size) {
+			err = checkQuota(tx, partyID, pinID, size)
+			if err != nil {
+				return err
+			}
+		}
+
+		if size > 0 || ps.Mode != pe.Mode {
+			ps.Size = size
+		}
+
+		ps.Aliases = pe.Aliases
+		ps.WantPinned = pe.WantPinned
+		ps.Mode = pe.Mode
+		ps.Replication = pe.Replication
+		ps.ExpiresAt = pe.ExpiresAt
+		ps.NotBefore = pe.NotBefore
+		ps.NotAfter = pe.NotAfter
+		ps.Status = pinbase.PinPending
+		ps.LastErrorMessage = ""
+		ps.Attempts = 0
+		ps.NextAttempt = time.Time{}
+		ps.Progress = pinbase.PinProgress{}
+		change = ps.nextChange(time.Now())
+
+		err = writePinStorage(pins, pinID, ps)
+		if err != nil {
+			return err
+		}
+
+		return appendPinHistory(tx, partyID, pinID, &historyStorage{
+			Change:  pinbase.PinChangeUpdated,
+			By:      pe.By,
+			Status:  ps.Status,
+			Changes: changes,
+		})
+	})
+
+	if err != nil {
+		return err
+	}
+
+	ps.dirty.schedule(change)
+
+	if wantChanged {
+		ps.bumpPins(hashes...)
+	}
+
+	return nil
+}
+
+func (ps *PinService) ResetPin(partyID, pinID pinbase.Hash, by string) error {
+	if ps.db == nil {
+		return errors.New("no database connection")
+	}
+
+	pinID = ps.storedPinID(partyID, pinID)
+
+	var hashes []pinbase.Hash
+
+	err := ps.db.Update(func(tx *bolt.Tx) error {
+		pins, err := getPinsBucket(tx, partyID)
+		if err != nil {
+			return err
+		}
+
+		pin := pins.Get([]byte(pinID))
+		if pin == nil {
+			return errors.New("could not find pin")
+		}
+
+		ps, err := extractPinStorage(pin)
+		if err != nil {
+			return err
+		}
+
+		hashes = ps.hashes(pinID)
+
+		ps.Status = pinbase.PinPending
+		ps.LastErrorMessage = ""
+		ps.Attempts = 0
+		ps.NextAttempt = time.Time{}
+		ps.Progress = pinbase.PinProgress{}
+
+		err = writePinStorage(pins, pinID, ps)
+		if err != nil {
+			return err
+		}
+
+		return appendPinHistory(tx, partyID, pinID, &historyStorage{
+			Change: pinbase.PinChangeReset,
+			By:     by,
+			Status: ps.Status,
+		})
+	})
+
+	if err != nil {
+		return err
+	}
+
+	ps.bumpPins(hashes...)
+
+	return nil
+}
+
+func (ps *PinService) RenewPin(partyID, pinID pinbase.Hash, expiresAt time.Time, by string) error {
+	if ps.db == nil {
+		return errors.New("no database connection")
+	}
+
+	pinID = ps.storedPinID(partyID, pinID)
+
+	p, err := ps.Pin(partyID, pinID)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return errors.New("could not find pin")
+	}
+
+	size, err := ps.pinSize(partyID, pinID, p.Mode, !p.WantPinned)
+	if err != nil {
+		return err
+	}
+
+	var wantChanged bool
+	var hashes []pinbase.Hash
+	var change time.Time
+
+	err = ps.db.Update(func(tx *bolt.Tx) error {
+		pins, err := getPinsBucket(tx, partyID)
+		if err != nil {
+			return err
+		}
+
+		pin := pins.Get([]byte(pinID))
+		if pin == nil {
+			return errors.New("could not find pin")
+		}
+
+		ps, err := extractPinStorage(pin)
+		if err != nil {
+			return err
+		}
+
+		hashes = ps.hashes(pinID)
+
+		changes := pinChanges(ps, &pinbase.PinEdit{
+			Aliases:     ps.Aliases,
+			WantPinned:  true,
+			Mode:        ps.Mode,
+			Replication: ps.Replication,
+			ExpiresAt:   expiresAt,
+			NotBefore:   ps.NotBefore,
+			NotAfter:    ps.NotAfter,
+		})
+
+		if !ps.WantPinned {
+			wantChanged = true
+
+			err = checkQuota(tx, partyID, pinID, size)
+			if err != nil {
+				return err
+			}
+
+			if size > 0 {
+				ps.Size = size
+			}
+
+			ps.WantPinned = true
+			ps.Status = pinbase.PinPending
+			ps.LastErrorMessage = ""
+			ps.Attempts = 0
+			ps.NextAttempt = time.Time{}
+			ps.Progress = pinbase.PinProgress{}
+		}
+
+		ps.ExpiresAt = expiresAt
+		change = ps.nextChange(time.Now())
+
+		err = writePinStorage(pins, pinID, ps)
+		if err != nil {
+			return err
+		}
+
+		return appendPinHistory(tx, partyID, pinID, &historyStorage{
+			Change:           pinbase.PinChangeRenewed,
+			By:               by,
+			Status:           ps.Status,
+			LastErrorMessage: ps.LastErrorMessage,
+			Changes:          changes,
+		})
+	})
+
+	if err != nil {
+		return err
+	}
+
+	ps.dirty.schedule(change)
+
+	if wantChanged {
+		ps.bumpPins(hashes...)
+	}
+
+	return nil
+}
+
+func (ps *PinService) PinParties(pinID pinbase.Hash) ([]*pinbase.PartyView, error) {
+	if ps.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	pinID = pinID.Canonical()
+
+	var list []*pinbase.PartyView
+
+	err := ps.db.View(func(tx *bolt.Tx) error {
+		parties, err := getPartiesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		owners, err := getOwnersBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, partyID := range pinOwners(owners, pinID) {
+			party := parties.Bucket([]byte(partyID))
+			if party == nil {
+				return errors.Errorf("indexed party %s not found", partyID)
+			}
+
+			ps, err := extractPartyStorage(party)
+			if err != nil {
+				return err
+			}
+
+			list = append(list, &pinbase.PartyView{
+				ID:          partyID,
+				Description: ps.Description,
+			})
+		}
+
+		return nil
+	})
+
+	return list, err
+}
+
+func (ps *PinService) ArchivedPins() ([]*pinbase.ArchivedPinView, error) {
+	if ps.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	var list []*pinbase.ArchivedPinView
+
+	err := ps.db.View(func(tx *bolt.Tx) error {
+		archive, err := getArchiveBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		c := archive.Cursor()
+
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			a, err := extractArchiveStorage(v)
+			if err != nil {
+				return err
+			}
+
+			av := &pinbase.ArchivedPinView{
+				ID:        pinbase.Hash(k),
+				Status:    a.Status,
+				LastError: nil,
+			}
+
+			if a.LastErrorMessage != "" {
+				av.LastError = cerrors.New(a.LastErrorMessage)
+			}
+
+			list = append(list, av)
+		}
+
+		return nil
+	})
+
+	return list, err
+}
+
+//
+// pinbase.PinBackend implementation
+//
+
+func (ps *PinService) PinProcessorBump() <-chan struct{} {
+	return ps.bump
+}
+
+func (ps *PinService) PinRequirements() map[pinbase.Hash]pinbase.PinRequirement {
+	m := make(map[pinbase.Hash]pinbase.PinRequirement)
+
+	if ps.db == nil {
+		log.Print("no database connection")
+		return m
+	}
+
+	// a full sweep covers everything that was dirty up to now
+	ps.dirty.take()
+
+	now := time.Now()
+
+	// hashes waiting on a retry should not be touched at all unless some
+	// other party wants them pinned
+	deferred := make(map[pinbase.Hash]struct{})
+
+	err := ps.db.View(func(tx *bolt.Tx) error {
+		parties, err := getPartiesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		archive, err := getArchiveBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		partiesC := parties.Cursor()
+
+		for partyK, partyV := partiesC.First(); partyK != nil; partyK, partyV = partiesC.Next() {
+			if partyV != nil {
+				log.Printf("non-bucket party found at %s", partyK)
+				continue
+			}
+
+			party := parties.Bucket(partyK)
+			if party == nil {
+				log.Printf("did not get bucket for party %s", partyK)
+				continue
+			}
+
+			pins := party.Bucket(PartyBucketPinsBucketKey)
+			if pins == nil {
+				log.Printf("did not get pins bucket for party %s", partyK)
+				continue
+			}
+
+			pinsC := pins.Cursor()
+
+			for pinK, pinV := pinsC.First(); pinK != nil; pinK, pinV = pinsC.Next() {
+				pinHash := pinbase.Hash(pinK)
+
+				ps, err := extractPinStorage(pinV)
+				if err != nil {
+					log.Printf("failed to extract data for pin %s under party %s", pinK, partyK)
+					continue
+				}
+
+				if ps.Kind == pinbase.PinKindName {
+					// the previous targets are held through their grace
+					// period whatever becomes of the current one
+					for _, prev := range ps.Previous {
+						if prev.Until.After(now) {
+							m[prev.Target] = m[prev.Target].Merge(ps.requirement(now))
+						}
+					}
+
+					if ps.Target == "" {
+						continue
+					}
+					pinHash = ps.Target
+				}
+
+				if ps.deferred(now) {
+					deferred[pinHash] = struct{}{}
+					continue
+				}
+
+				m[pinHash] = m[pinHash].Merge(ps.requirement(now))
+			}
+		}
+
+		archiveC := archive.Cursor()
+
+		for pinK, _ := archiveC.First(); pinK != nil; pinK, _ = archiveC.Next() {
+			pinHash := pinbase.Hash(pinK)
+
+			if _, exists := m[pinHash]; !exists {
+				m[pinHash] = pinbase.PinRequirement{}
+			}
+		}
+
+		for pinHash, _ := range deferred {
+			if !m[pinHash].WantPinned {
+				delete(m, pinHash)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Printf("error in bolt transaction: %s", err)
+	}
+
+	return m
+}
+
+func (ps *PinService) DirtyPinRequirements() map[pinbase.Hash]pinbase.PinRequirement {
+	m := make(map[pinbase.Hash]pinbase.PinRequirement)
+
+	if ps.db == nil {
+		log.Print("no database connection")
+		return m
+	}
+
+	dirty := ps.dirty.take()
+	if len(dirty) == 0 {
+		return m
+	}
+
+	now := time.Now()
+
+	err := ps.db.View(func(tx *bolt.Tx) error {
+		parties, err := getPartiesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		archive, err := getArchiveBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		owners, err := getOwnersBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		targets, err := getNameTargetsBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		for pinHash, _ := range dirty {
+			r, ok := pinRequirement(parties, archive, owners, targets, pinHash, now)
+			if ok {
+				m[pinHash] = r
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Printf("error in bolt transaction: %s", err)
+	}
+
+	return m
+}
+
+// pinRequirement works out whether h should be pinned the same way
+// PinRequirements does, but for a single hash. The second return value is
+// false if h should be left alone altogether.
+func pinRequirement(parties, archive, owners, targets *bolt.Bucket, h pinbase.Hash, now time.Time) (pinbase.PinRequirement, bool) {
+	var r pinbase.PinRequirement
+	var found, deferred bool
+
+	consider := func(partyID, pinID pinbase.Hash) {
+		ps := partyPinStorage(parties, partyID, pinID)
+		if ps == nil {
+			return
+		}
+
+		if ps.Kind == pinbase.PinKindName && ps.Target != h {
+			if ps.heldPrevious(h, now) {
+				found = true
+				r = r.Merge(ps.requirement(now))
+			}
+			return
 		}
 
-		archive, err := getArchiveBucket(tx)
-		if err != nil {
-			return err
+		if ps.deferred(now) {
+			deferred = true
+			return
 		}
 
-		return errors.Wrap(archive.Put(pinKey, sentinel), "archive the pin")
-	})
+		found = true
+		r = r.Merge(ps.requirement(now))
+	}
 
-	if err != nil {
-		return err
+	for _, partyID := range pinOwners(owners, h) {
+		consider(partyID, h)
 	}
 
-	go func(c chan<- struct{}) { c <- struct{}{} }(ps.bump)
+	for partyID, pinIDs := range nameTargets(targets, h) {
+		for _, pinID := range pinIDs {
+			consider(partyID, pinID)
+		}
+	}
 
-	return nil
-}
+	switch {
+	case r.WantPinned:
+		return r, true
 
-func (ps *PinService) UpdatePin(partyID, pinID pinbase.Hash, pe *pinbase.PinEdit) error {
-	if ps.db == nil {
-		return errors.New("no database connection")
-	}
+	case deferred:
+		return r, false
 
-	var wantChanged bool
+	case found:
+		return r, true
 
-	err := ps.db.Update(func(tx *bolt.Tx) error {
-		pins, err := getPinsBucket(tx, partyID)
-		if err != nil {
-			return err
-		}
+	case archive.Get([]byte(h)) != nil:
+		return r, true
 
-		pin := pins.Get([]byte(pinID))
-		if pin == nil {
-			return errors.New("could not find pin")
-		}
+	default:
+		return r, false
+	}
+}
 
-		ps, err := extractPinStorage(pin)
-		if err != nil {
-			return err
+// matchingPins lists the pins of every party that match.
+func matchingPins(tx *bolt.Tx, match func(p *pinStorage) bool) ([]partyPin, error) {
+	parties, err := getPartiesBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []partyPin
+
+	err = parties.ForEach(func(partyK, partyV []byte) error {
+		if partyV != nil {
+			return nil
 		}
 
-		if ps.WantPinned != pe.WantPinned {
-			wantChanged = true
+		pins := parties.Bucket(partyK).Bucket(PartyBucketPinsBucketKey)
+		if pins == nil {
+			return nil
 		}
 
-		ps.Aliases = pe.Aliases
-		ps.WantPinned = pe.WantPinned
-		ps.Status = pinbase.PinPending
-		ps.LastErrorMessage = ""
+		return pins.ForEach(func(pinK, pinV []byte) error {
+			p, err := extractPinStorage(pinV)
+			if err != nil {
+				log.Printf("failed to extract pin %s for party %s: %s", pinK, partyK, err)
+				return nil
+			}
 
-		return writePinStorage(pins, pinID, ps)
+			if match(p) {
+				list = append(list, partyPin{pinbase.Hash(partyK), pinbase.Hash(pinK)})
+			}
+			return nil
+		})
 	})
 
-	if err != nil {
-		return err
+	return list, err
+}
+
+// partyPinStorage returns the stored pin of an indexed party, or nil if it
+// can not be found.
+func partyPinStorage(parties *bolt.Bucket, partyID, pinID pinbase.Hash) *pinStorage {
+	party := parties.Bucket([]byte(partyID))
+	if party == nil {
+		log.Printf("did not get bucket for indexed party %s", partyID)
+		return nil
 	}
 
-	if wantChanged {
-		go func(c chan<- struct{}) { c <- struct{}{} }(ps.bump)
+	pins := party.Bucket(PartyBucketPinsBucketKey)
+	if pins == nil {
+		log.Printf("did not get pins bucket for party %s", partyID)
+		return nil
 	}
 
-	return nil
-}
+	pin := pins.Get([]byte(pinID))
+	if pin == nil {
+		log.Printf("did not find indexed pin %s for party %s", pinID, partyID)
+		return nil
+	}
 
-//
-// pinbase.PinBackend implementation
-//
+	ps, err := extractPinStorage(pin)
+	if err != nil {
+		log.Printf("failed to extract data for pin %s under party %s", pinID, partyID)
+		return nil
+	}
 
-func (ps *PinService) PinProcessorBump() <-chan struct{} {
-	return ps.bump
+	return ps
 }
 
-func (ps *PinService) PinRequirements() map[pinbase.Hash]bool {
-	m := make(map[pinbase.Hash]bool)
-
+func (ps *PinService) NotifyPin(pinID pinbase.Hash, s *pinbase.PinBackendState) {
 	if ps.db == nil {
 		log.Print("no database connection")
-		return m
+		return
 	}
 
-	err := ps.db.View(func(tx *bolt.Tx) error {
-		parties, err := getPartiesBucket(tx)
+	pinKey := []byte(pinID)
+
+	now := time.Now()
+	retry := ps.retry
+
+	var events []*pinbase.PinEvent
+
+	err := ps.db.Update(func(tx *bolt.Tx) error {
+		events = nil
+
+		owners, err := getOwnersBucket(tx)
 		if err != nil {
 			return err
 		}
 
-		archive, err := getArchiveBucket(tx)
+		targets, err := getNameTargetsBucket(tx)
 		if err != nil {
 			return err
 		}
 
-		partiesC := parties.Cursor()
+		// key is the party's key for the pin, which is the name for name
+		// pins
+		notify := func(partyID, key pinbase.Hash) {
+			pins, err := getPinsBucket(tx, partyID)
+			if err != nil {
+				log.Printf("did not get pins for party %s", partyID)
+				return
+			}
 
-		for partyK, partyV := partiesC.First(); partyK != nil; partyK, partyV = partiesC.Next() {
-			if partyV != nil {
-				log.Printf("non-bucket party found at %s", partyK)
-				continue
+			pin := pins.Get([]byte(key))
+			if pin == nil {
+				log.Printf("most suprisingly did not find pin %s for party %s", key, partyID)
+				return
 			}
 
-			party := parties.Bucket(partyK)
-			if party == nil {
-				log.Printf("did not get bucket for party %s", partyK)
-				continue
+			ps, err := extractPinStorage(pin)
+			if err != nil {
+				log.Printf("failed to extract pin %s for party %s: %s", key, partyID, err)
+				return
 			}
 
-			pins := party.Bucket(PartyBucketPinsBucketKey)
-			if pins == nil {
-				log.Printf("did not get pins bucket for party %s", partyK)
-				continue
+			// name pins only follow how their current target is doing
+			if ps.Kind == pinbase.PinKindName && ps.Target != pinID {
+				return
 			}
 
-			pinsC := pins.Cursor()
+			oldStatus := ps.Status
 
-			for pinK, pinV := pinsC.First(); pinK != nil; pinK, pinV = pinsC.Next() {
-				pinHash := pinbase.Hash(pinK)
+			ps.Status = s.Status
+			if s.LastError == nil {
+				ps.LastErrorMessage = ""
+			} else {
+				ps.LastErrorMessage = s.LastError.Error()
+			}
 
-				if m[pinHash] {
-					// if the pin is already marked, no need to extract its data
-					continue
+			if s.Progress != nil {
+				ps.Progress = *s.Progress
+			}
+
+			ps.updateNodes(s)
+
+			if s.Size > 0 {
+				ps.Size = s.Size
+			}
+
+			switch s.Status {
+			case pinbase.PinError:
+				// parties still waiting out their backoff were only along
+				// for the ride of another party's attempt
+				if ps.NextAttempt.After(now) {
+					break
 				}
 
-				ps, err := extractPinStorage(pinV)
+				ps.Attempts++
+
+				if retry.Fatal(ps.Attempts) {
+					ps.Status = pinbase.PinFatal
+					ps.NextAttempt = time.Time{}
+				} else {
+					ps.NextAttempt = now.Add(retry.Delay(ps.Attempts))
+				}
+
+			case pinbase.PinPinning:
+				// still working on it, the attempt has not ended yet
+
+			default:
+				ps.Attempts = 0
+				ps.NextAttempt = time.Time{}
+			}
+
+			err = writePinStorage(pins, key, ps)
+			if err != nil {
+				log.Printf("failed to store pin %s for party %s: %s", key, partyID, err)
+				return
+			}
+
+			if ps.Status != oldStatus {
+				e := &pinbase.PinEvent{
+					Party:     partyID,
+					Pin:       key,
+					Status:    ps.Status,
+					LastError: ps.LastErrorMessage,
+					Time:      now,
+				}
+				events = append(events, e)
+
+				err = appendPinHistory(tx, partyID, key, &historyStorage{
+					Time:             now.UTC(),
+					Change:           pinbase.PinChangeStatus,
+					Status:           e.Status,
+					LastErrorMessage: e.LastError,
+				})
 				if err != nil {
-					log.Printf("failed to extract data for pin %s under party %s", pinK, partyK)
-					continue
+					log.Printf("failed to record the history of pin %s for party %s: %s", key, partyID, err)
 				}
 
-				m[pinHash] = ps.WantPinned
+				if pinbase.WebhookStatus(e.Status) {
+					err = queueWebhookDeliveries(tx, e)
+					if err != nil {
+						log.Printf("failed to queue webhook deliveries for pin %s for party %s: %s", key, partyID, err)
+					}
+				}
 			}
 		}
 
-		archiveC := archive.Cursor()
-
-		for pinK, _ := archiveC.First(); pinK != nil; pinK, _ = archiveC.Next() {
-			pinHash := pinbase.Hash(pinK)
+		for _, partyID := range pinOwners(owners, pinID) {
+			notify(partyID, pinID)
+		}
 
-			if _, exists := m[pinHash]; !exists {
-				m[pinHash] = false
+		for partyID, keys := range nameTargets(targets, pinID) {
+			for _, key := range keys {
+				notify(partyID, key)
 			}
 		}
 
-		return nil
+		archive, err := getArchiveBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		archived := archive.Get(pinKey)
+		if archived == nil {
+			return nil
+		}
+
+		if s.Status == pinbase.PinUnpinned {
+			// the node let go of it, so there is nothing left to track
+			return errors.Wrap(archive.Delete(pinKey), "drop archived pin")
+		}
+
+		a, err := extractArchiveStorage(archived)
+		if err != nil {
+			return err
+		}
+
+		a.Status = s.Status
+		if s.LastError == nil {
+			a.LastErrorMessage = ""
+		} else {
+			a.LastErrorMessage = s.LastError.Error()
+		}
+
+		return writeArchiveStorage(archive, pinID, a)
 	})
+
 	if err != nil {
 		log.Printf("error in bolt transaction: %s", err)
+		return
 	}
 
-	return m
+	if ps.events != nil {
+		for _, e := range events {
+			ps.events.PublishPinEvent(e)
+		}
+	}
 }
 
-func (ps *PinService) NotifyPin(pinID pinbase.Hash, s *pinbase.PinBackendState) {
+// ExpirePins stops wanting the pins whose expiry has passed, leaving them with
+// their parties so they can be renewed.
+func (ps *PinService) ExpirePins(now time.Time) {
 	if ps.db == nil {
 		log.Print("no database connection")
 		return
 	}
 
-	pinKey := []byte(pinID)
+	isDue := func(p *pinStorage) bool {
+		return p.WantPinned && !p.ExpiresAt.IsZero() && !p.ExpiresAt.After(now)
+	}
 
-	err := ps.db.Update(func(tx *bolt.Tx) error {
-		var partiesWithPin []pinbase.Hash
+	// most sweeps have nothing to expire, so there is no need to hold up
+	// writers while looking
+	var due []partyPin
+
+	err := ps.db.View(func(tx *bolt.Tx) error {
+		var err error
+		due, err = matchingPins(tx, isDue)
+		return err
+	})
+	if err != nil {
+		log.Printf("error in bolt transaction: %s", err)
+		return
+	}
+
+	if len(due) == 0 {
+		return
+	}
 
-		// first go through the parties to find the ones that need updating
+	var expired []pinbase.Hash
+
+	err = ps.db.Update(func(tx *bolt.Tx) error {
+		expired = nil
 
 		parties, err := getPartiesBucket(tx)
 		if err != nil {
 			return err
 		}
 
-		partiesC := parties.Cursor()
-
-		for partyK, partyV := partiesC.First(); partyK != nil; partyK, partyV = partiesC.Next() {
-			if partyV != nil {
-				log.Printf("non-bucket party found at %s", partyK)
+		for _, pp := range due {
+			// the pin may have been renewed or deleted since
+			p := partyPinStorage(parties, pp.partyID, pp.pinID)
+			if p == nil || !isDue(p) {
 				continue
 			}
 
-			party := parties.Bucket(partyK)
-			if party == nil {
-				log.Printf("did not get bucket for party %s", partyK)
-				continue
+			pins, err := getPinsBucket(tx, pp.partyID)
+			if err != nil {
+				return err
 			}
 
-			pins := party.Bucket(PartyBucketPinsBucketKey)
-			if pins == nil {
-				log.Printf("did not get pins bucket for party %s", partyK)
-				continue
+			p.WantPinned = false
+			p.Status = pinbase.PinPending
+			p.LastErrorMessage = ""
+			p.Attempts = 0
+			p.NextAttempt = time.Time{}
+			p.Progress = pinbase.PinProgress{}
+
+			err = writePinStorage(pins, pp.pinID, p)
+			if err != nil {
+				return err
 			}
 
-			pin := pins.Get(pinKey)
-			if pin != nil {
-				partiesWithPin = append(partiesWithPin, pinbase.Hash(partyK))
+			err = appendPinHistory(tx, pp.partyID, pp.pinID, &historyStorage{
+				Time:    now.UTC(),
+				Change:  pinbase.PinChangeExpired,
+				Status:  p.Status,
+				Changes: []string{"want-pinned: true -> false"},
+			})
+			if err != nil {
+				return err
 			}
+
+			expired = append(expired, p.hashes(pp.pinID)...)
 		}
 
-		// now update the pins in the parties that need updating
+		return nil
+	})
+	if err != nil {
+		log.Printf("error in bolt transaction: %s", err)
+		return
+	}
 
-		for _, partyID := range partiesWithPin {
-			pins, err := getPinsBucket(tx, partyID)
-			if err != nil {
-				log.Printf("did not get pins for party %s", partyID)
-				continue
-			}
+	if len(expired) > 0 {
+		ps.bumpPins(expired...)
+	}
+}
 
-			pin := pins.Get(pinKey)
-			if pin == nil {
-				log.Printf("most suprisingly did not find pin %s for party %s", pinID, partyID)
-				continue
-			}
+// NextPinChange returns the first time after now that any pin changes by
+// itself, as worked out by nextChange.
+func (ps *PinService) NextPinChange(now time.Time) time.Time {
+	if ps.db == nil {
+		return time.Time{}
+	}
 
-			ps, err := extractPinStorage(pin)
-			if err != nil {
-				log.Printf("failed to extract pin %s for party %s: %s", pinID, partyID, err)
-				continue
-			}
+	// a full scan covers every change noted up to now
+	ps.dirty.takeNext()
 
-			ps.Status = s.Status
-			if s.LastError == nil {
-				ps.LastErrorMessage = ""
-			} else {
-				ps.LastErrorMessage = s.LastError.Error()
+	var next time.Time
+
+	err := ps.db.View(func(tx *bolt.Tx) error {
+		parties, err := getPartiesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		return parties.ForEach(func(partyK, partyV []byte) error {
+			if partyV != nil {
+				return nil
 			}
 
-			err = writePinStorage(pins, pinID, ps)
-			if err != nil {
-				log.Printf("failed to store pin %s for party %s: %s", pinID, partyID, err)
-				continue
+			pins := parties.Bucket(partyK).Bucket(PartyBucketPinsBucketKey)
+			if pins == nil {
+				return nil
 			}
-		}
 
-		return nil
-	})
+			return pins.ForEach(func(pinK, pinV []byte) error {
+				p, err := extractPinStorage(pinV)
+				if err != nil {
+					log.Printf("failed to extract pin %s for party %s: %s", pinK, partyK, err)
+					return nil
+				}
 
+				if t := p.nextChange(now); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+					next = t
+				}
+				return nil
+			})
+		})
+	})
 	if err != nil {
 		log.Printf("error in bolt transaction: %s", err)
+		return time.Time{}
+	}
+
+	return next
+}
+
+// DirtyPinChange returns the first time after now that a pin written since the
+// last call to either it or NextPinChange changes by itself, now itself if that
+// time has passed, or zero if there is nothing coming.
+func (ps *PinService) DirtyPinChange(now time.Time) time.Time {
+	next := ps.dirty.takeNext()
+	if !next.IsZero() && next.Before(now) {
+		return now
 	}
+
+	return next
 }
 
 var _ pinbase.PinService = &PinService{}
 var _ pinbase.PinBackend = &PinService{}
+var _ pinbase.PinExpirer = &PinService{}
+var _ pinbase.PinScheduler = &PinService{}
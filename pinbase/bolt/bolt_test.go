@@ -4,8 +4,11 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/apiarian/ipfs-pinbase/pinbase"
 	"github.com/apiarian/ipfs-pinbase/pinbase/test"
+	"github.com/boltdb/bolt"
 )
 
 func TestClientService(t *testing.T) {
@@ -55,6 +58,338 @@ func TestClientFeedback(t *testing.T) {
 	test.TestPinFeedbackHappyPath(t, pb, ps)
 }
 
+func TestClientRetry(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	rp := pinbase.RetryPolicy{
+		MinDelay:    20 * time.Millisecond,
+		MaxDelay:    30 * time.Millisecond,
+		MaxAttempts: 3,
+	}
+
+	c := NewClient(filename)
+	c.Retry = rp
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pb := c.PinBackend()
+
+	test.TestPinRetryHappyPath(t, pb, ps, rp)
+}
+
+func TestClientDirty(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pb := c.PinBackend()
+
+	test.TestPinDirtyHappyPath(t, pb, ps)
+}
+
+func TestClientPinParties(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pb := c.PinBackend()
+
+	test.TestPinPartiesHappyPath(t, pb, ps)
+}
+
+func TestClientArchive(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pb := c.PinBackend()
+
+	test.TestPinArchiveHappyPath(t, pb, ps)
+}
+
+func TestClientProgress(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pb := c.PinBackend()
+
+	test.TestPinProgressHappyPath(t, pb, ps)
+}
+
+func TestClientModes(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pb := c.PinBackend()
+
+	test.TestPinModeHappyPath(t, pb, ps)
+}
+
+func TestClientReplication(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pb := c.PinBackend()
+
+	test.TestPinReplicationHappyPath(t, pb, ps)
+}
+
+func TestClientOldArchiveEntries(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+	defer c.Close()
+
+	err = c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(PinArchiveBucketKey).Put([]byte("bar"), sentinel)
+	})
+	if err != nil {
+		t.Fatalf("failed to write an old archive entry: %+v", err)
+	}
+
+	archived, err := c.PinService().ArchivedPins()
+	if err != nil {
+		t.Fatalf("failed to get archived pins: %+v", err)
+	}
+
+	if len(archived) != 1 || archived[0].ID != "bar" || archived[0].Status != pinbase.PinPending {
+		t.Errorf("old archive entry not read as pending: %+v", archived)
+	}
+}
+
+func TestClientIndexesOldDatabase(t *testing.T) {
+	const h = pinbase.Hash("bafybeiczsscdsbs7ffqz55asqdf3smv6klcw3gofszvwlyarci47bgf354")
+
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+
+	for _, party := range []pinbase.Hash{"foo", "baz"} {
+		err = ps.CreateParty(&pinbase.PartyCreate{ID: party})
+		if err != nil {
+			t.Fatalf("failed to create party %s: %+v", party, err)
+		}
+
+		err = ps.CreatePin(party, &pinbase.PinCreate{ID: h})
+		if err != nil {
+			t.Fatalf("failed to create pin for %s: %+v", party, err)
+		}
+	}
+
+	// pretend the database predates the index
+	err = c.db.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket(PinOwnersBucketKey)
+	})
+	if err != nil {
+		t.Fatalf("failed to drop the index: %+v", err)
+	}
+
+	err = c.Close()
+	if err != nil {
+		t.Fatalf("failed to close client: %+v", err)
+	}
+
+	c = NewClient(filename)
+	err = c.Open()
+	if err != nil {
+		t.Fatalf("failed to reopen client: %+v", err)
+	}
+	defer c.Close()
+
+	parties, err := c.PinService().PinParties(h)
+	if err != nil {
+		t.Fatalf("failed to get pin parties: %+v", err)
+	}
+
+	if len(parties) != 2 || parties[0].ID != "baz" || parties[1].ID != "foo" {
+		t.Errorf("index not rebuilt: %+v", parties)
+	}
+}
+
+func TestClientCanonicalizesOldDatabase(t *testing.T) {
+	const (
+		v0      = pinbase.Hash("QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn")
+		v1      = pinbase.Hash("zdj7WbTaiJT1fgatdet9Ei9iDB5hdCxkbVyhyh8YTUnXMiwYi")
+		h       = pinbase.Hash("bafybeiczsscdsbs7ffqz55asqdf3smv6klcw3gofszvwlyarci47bgf354")
+		target  = pinbase.Hash("bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi")
+		name    = pinbase.Hash("k51qzi5uqu5dlvj2baxnqndepeb86cbk3ng7n3i46uzyxzyqj2xjonzllnv0v8")
+		nameKey = pinbase.Hash("bafzaajaiaejcbzdibmxyzdjbbehgvizh6g5tikvy47mshdy6gwbruvgwvd24seje")
+	)
+
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+
+	for _, party := range []pinbase.Hash{"foo", "baz"} {
+		err = ps.CreateParty(&pinbase.PartyCreate{ID: party})
+		if err != nil {
+			t.Fatalf("failed to create party %s: %+v", party, err)
+		}
+	}
+
+	// pretend the database predates canonical hashes
+	err = c.db.Update(func(tx *bolt.Tx) error {
+		pins, err := getPinsBucket(tx, "foo")
+		if err != nil {
+			return err
+		}
+
+		// two forms of the same hash, the first one wins
+		for _, id := range []pinbase.Hash{v0, v1} {
+			err = writePinStorage(pins, id, &pinStorage{WantPinned: true, Replication: 1})
+			if err != nil {
+				return err
+			}
+
+			err = appendPinHistory(tx, "foo", id, &historyStorage{Change: pinbase.PinChangeCreated})
+			if err != nil {
+				return err
+			}
+		}
+
+		pins, err = getPinsBucket(tx, "baz")
+		if err != nil {
+			return err
+		}
+
+		err = writePinStorage(pins, name, &pinStorage{
+			Kind:       pinbase.PinKindName,
+			WantPinned: true,
+			Target:     "QmbWqxBEKC3P8tqsKc98xmWNzrzDtRLMiMPL8wBuTGsMnR",
+		})
+		if err != nil {
+			return err
+		}
+
+		err = writeArchiveStorage(tx.Bucket(PinArchiveBucketKey), v1, &archiveStorage{Status: pinbase.PinPending})
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(SchemaBucketKey).Delete(schemaVersionKey)
+	})
+	if err != nil {
+		t.Fatalf("failed to write old pins: %+v", err)
+	}
+
+	err = c.Close()
+	if err != nil {
+		t.Fatalf("failed to close client: %+v", err)
+	}
+
+	c = NewClient(filename)
+	err = c.Open()
+	if err != nil {
+		t.Fatalf("failed to reopen client: %+v", err)
+	}
+	defer c.Close()
+
+	ps = c.PinService()
+
+	pins, err := ps.Pins("foo")
+	if err != nil || len(pins) != 1 || pins[0].ID != h {
+		t.Errorf("got pins %v (%+v), expected just %s", pins, err, h)
+	}
+
+	// any form of the hash finds the pin
+	for _, id := range []pinbase.Hash{v0, v1, h} {
+		p, err := ps.Pin("foo", id)
+		if err != nil || p == nil || p.ID != h {
+			t.Errorf("got pin %v (%+v) for %s", p, err, id)
+		}
+	}
+
+	hs, err := ps.PinHistory("foo", h)
+	if err != nil || len(hs) != 1 {
+		t.Errorf("got history %v (%+v), expected the one moved along", hs, err)
+	}
+
+	parties, err := ps.PinParties(h)
+	if err != nil || len(parties) != 1 || parties[0].ID != "foo" {
+		t.Errorf("owners not reindexed: %v (%+v)", parties, err)
+	}
+
+	p, err := ps.Pin("baz", nameKey)
+	if err != nil || p == nil || p.Target != target {
+		t.Fatalf("got name pin %v (%+v), expected target %s", p, err, target)
+	}
+
+	// the name targets index leads notifications for the target to the pin
+	c.PinBackend().NotifyPin(target, &pinbase.PinBackendState{Status: pinbase.PinPinned})
+
+	p, err = ps.Pin("baz", nameKey)
+	if err != nil || p == nil || p.Status != pinbase.PinPinned {
+		t.Errorf("got name pin %v (%+v), expected it pinned", p, err)
+	}
+
+	archived, err := ps.ArchivedPins()
+	if err != nil || len(archived) != 1 || archived[0].ID != h {
+		t.Errorf("got archived pins %v (%+v), expected just %s", archived, err, h)
+	}
+}
+
 func tempfilename(t *testing.T) string {
 	f, err := ioutil.TempFile("", "pinbase-bolt-")
 	if err != nil {
@@ -73,3 +408,257 @@ func tempfilename(t *testing.T) string {
 
 	return f.Name()
 }
+
+func TestClientNodes(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ns := c.NodeService()
+
+	test.TestNodeServiceHappyPath(t, ns)
+}
+
+func TestClientQuotas(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	c.Sizer = test.QuotaSizer
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+
+	test.TestPinQuotaHappyPath(t, ps)
+}
+
+func TestClientSizes(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pb := c.PinBackend()
+
+	test.TestPinSizeHappyPath(t, pb, ps)
+}
+
+func TestClientKeys(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ks := c.KeyService()
+
+	test.TestKeyServiceHappyPath(t, ks)
+}
+
+func TestClientGrants(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+
+	test.TestPinGrantHappyPath(t, ps)
+}
+
+func TestClientEvents(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	hub := pinbase.NewEventHub()
+
+	c := NewClient(filename)
+	c.Events = hub
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pb := c.PinBackend()
+
+	test.TestPinEventsHappyPath(t, pb, ps, hub)
+}
+
+func TestClientWebhooks(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	rp := pinbase.RetryPolicy{
+		MinDelay:    20 * time.Millisecond,
+		MaxDelay:    30 * time.Millisecond,
+		MaxAttempts: 3,
+	}
+
+	c := NewClient(filename)
+	c.Retry = rp
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pb := c.PinBackend()
+
+	test.TestWebhookHappyPath(t, pb, ps, c.WebhookService(), c.WebhookQueue(), rp)
+}
+
+func TestClientHistory(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pb := c.PinBackend()
+
+	test.TestPinHistoryHappyPath(t, pb, ps)
+}
+
+func TestClientExpiry(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	pe := c.PinBackend().(pinbase.PinExpirer)
+
+	test.TestPinExpiryHappyPath(t, pe, ps)
+}
+
+func TestClientWindows(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	sc := c.PinBackend().(pinbase.PinScheduler)
+
+	test.TestPinWindowHappyPath(t, sc, ps)
+}
+
+func TestClientNames(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	c.NameGrace = time.Hour
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	ps := c.PinService()
+	nt := c.PinBackend().(pinbase.PinNameTracker)
+
+	test.TestPinNameHappyPath(t, nt, ps, c.NameGrace)
+}
+
+func TestClientNamePinForms(t *testing.T) {
+	const (
+		peerID  = pinbase.Hash("QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn")
+		nameKey = pinbase.Hash("bafzbeiczsscdsbs7ffqz55asqdf3smv6klcw3gofszvwlyarci47bgf354")
+	)
+
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+	defer c.Close()
+
+	ps := c.PinService()
+
+	err = ps.CreateParty(&pinbase.PartyCreate{ID: "foo"})
+	if err != nil {
+		t.Fatalf("failed to create party: %+v", err)
+	}
+
+	err = ps.CreatePin("foo", &pinbase.PinCreate{
+		ID:          peerID,
+		Kind:        pinbase.PinKindName,
+		WantPinned:  true,
+		Replication: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to create name pin: %+v", err)
+	}
+
+	// the peer ID is kept as an IPNS key, but still finds the pin
+	for _, id := range []pinbase.Hash{peerID, nameKey} {
+		p, err := ps.Pin("foo", id)
+		if err != nil || p == nil || p.ID != nameKey {
+			t.Errorf("got pin %v (%+v) for %s", p, err, id)
+		}
+	}
+
+	err = ps.DeletePin("foo", peerID, "tester")
+	if err != nil {
+		t.Fatalf("failed to delete name pin: %+v", err)
+	}
+
+	p, err := ps.Pin("foo", nameKey)
+	if err != nil || p != nil {
+		t.Errorf("got pin %v (%+v) after deleting it", p, err)
+	}
+
+	hs, err := ps.PinHistory("foo", peerID)
+	if err != nil || len(hs) != 2 || hs[1].Change != pinbase.PinChangeDeleted {
+		t.Errorf("got history %v (%+v), expected the pin created and deleted", hs, err)
+	}
+}
+
+func TestClientPaths(t *testing.T) {
+	filename := tempfilename(t)
+	defer os.Remove(filename)
+
+	c := NewClient(filename)
+	err := c.Open()
+	if err != nil {
+		t.Fatalf("failed to open client: %+v", err)
+	}
+
+	test.TestPinPathHappyPath(t, c.PinService())
+}
@@ -0,0 +1,217 @@
+package bolt
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+// The history of a party's pin is kept in a bucket of its own under the party's
+// history bucket, keyed by sequence. It is left behind when the pin is deleted
+// and goes away along with the party.
+
+type historyStorage struct {
+	Time             time.Time
+	Change           pinbase.PinChange
+	By               string
+	Status           pinbase.PinStatus
+	LastErrorMessage string
+	Changes          []string
+}
+
+func (h *historyStorage) entry() *pinbase.PinHistoryEntry {
+	return &pinbase.PinHistoryEntry{
+		Time:      h.Time,
+		Change:    h.Change,
+		By:        h.By,
+		Status:    h.Status,
+		LastError: h.LastErrorMessage,
+		Changes:   h.Changes,
+	}
+}
+
+func extractHistoryStorage(data []byte) (*historyStorage, error) {
+	var h historyStorage
+	err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&h)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode pin history data")
+	}
+
+	return &h, nil
+}
+
+func writeHistoryStorage(history *bolt.Bucket, seq uint64, h *historyStorage) error {
+	var b bytes.Buffer
+	enc := gob.NewEncoder(&b)
+
+	err := enc.Encode(h)
+	if err != nil {
+		return errors.Wrap(err, "encode pin history data")
+	}
+
+	err = history.Put(sequenceKey(seq), b.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "put pin history data")
+	}
+
+	return nil
+}
+
+// appendPinHistory adds h to the end of the history of the party's pin.
+func appendPinHistory(tx *bolt.Tx, partyID, pinID pinbase.Hash, h *historyStorage) error {
+	parties, err := getPartiesBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	party := parties.Bucket([]byte(partyID))
+	if party == nil {
+		return errors.New("could not find party")
+	}
+
+	histories, err := party.CreateBucketIfNotExists(PartyBucketHistoryBucketKey)
+	if err != nil {
+		return errors.Wrap(err, "create party-history bucket")
+	}
+
+	history, err := histories.CreateBucketIfNotExists([]byte(pinID))
+	if err != nil {
+		return errors.Wrap(err, "create pin history bucket")
+	}
+
+	seq, err := history.NextSequence()
+	if err != nil {
+		return errors.Wrap(err, "get pin history sequence")
+	}
+
+	if h.Time.IsZero() {
+		h.Time = time.Now().UTC()
+	}
+
+	return writeHistoryStorage(history, seq, h)
+}
+
+// pinSettings describes what a new pin was created with, in the form of the
+// changes of pinChanges.
+func pinSettings(p *pinStorage) []string {
+	var settings []string
+	if p.Kind != pinbase.PinKindHash {
+		settings = append(settings, "kind: "+p.Kind.String())
+	}
+	if p.Path != "" {
+		settings = append(settings, "path: "+p.Path)
+	}
+
+	settings = append(settings,
+		fmt.Sprintf("aliases: %q", p.Aliases),
+		fmt.Sprintf("want-pinned: %t", p.WantPinned),
+		fmt.Sprintf("mode: %s", p.Mode),
+		fmt.Sprintf("replication: %d", p.Replication),
+	)
+
+	if !p.ExpiresAt.IsZero() {
+		settings = append(settings, "expires-at: "+expiry(p.ExpiresAt))
+	}
+	if !p.NotBefore.IsZero() {
+		settings = append(settings, "not-before: "+windowEdge(p.NotBefore))
+	}
+	if !p.NotAfter.IsZero() {
+		settings = append(settings, "not-after: "+windowEdge(p.NotAfter))
+	}
+
+	return settings
+}
+
+func expiry(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func windowEdge(t time.Time) string {
+	if t.IsZero() {
+		return "open"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// pinChanges describes what pe changes about p.
+func pinChanges(p *pinStorage, pe *pinbase.PinEdit) []string {
+	var changes []string
+
+	if fmt.Sprintf("%q", p.Aliases) != fmt.Sprintf("%q", pe.Aliases) {
+		changes = append(changes, fmt.Sprintf("aliases: %q -> %q", p.Aliases, pe.Aliases))
+	}
+	if p.WantPinned != pe.WantPinned {
+		changes = append(changes, fmt.Sprintf("want-pinned: %t -> %t", p.WantPinned, pe.WantPinned))
+	}
+	if p.Mode != pe.Mode {
+		changes = append(changes, fmt.Sprintf("mode: %s -> %s", p.Mode, pe.Mode))
+	}
+	if p.Replication != pe.Replication {
+		changes = append(changes, fmt.Sprintf("replication: %d -> %d", p.Replication, pe.Replication))
+	}
+	if !p.ExpiresAt.Equal(pe.ExpiresAt) {
+		changes = append(changes, fmt.Sprintf("expires-at: %s -> %s", expiry(p.ExpiresAt), expiry(pe.ExpiresAt)))
+	}
+	if !p.NotBefore.Equal(pe.NotBefore) {
+		changes = append(changes, fmt.Sprintf("not-before: %s -> %s", windowEdge(p.NotBefore), windowEdge(pe.NotBefore)))
+	}
+	if !p.NotAfter.Equal(pe.NotAfter) {
+		changes = append(changes, fmt.Sprintf("not-after: %s -> %s", windowEdge(p.NotAfter), windowEdge(pe.NotAfter)))
+	}
+
+	return changes
+}
+
+func (ps *PinService) PinHistory(partyID, pinID pinbase.Hash) ([]*pinbase.PinHistoryEntry, error) {
+	if ps.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	pinID = ps.storedPinID(partyID, pinID)
+
+	var list []*pinbase.PinHistoryEntry
+
+	err := ps.db.View(func(tx *bolt.Tx) error {
+		parties, err := getPartiesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		party := parties.Bucket([]byte(partyID))
+		if party == nil {
+			return errors.New("could not find party")
+		}
+
+		histories := party.Bucket(PartyBucketHistoryBucketKey)
+		if histories == nil {
+			return nil
+		}
+
+		history := histories.Bucket([]byte(pinID))
+		if history == nil {
+			return nil
+		}
+
+		return history.ForEach(func(k, v []byte) error {
+			h, err := extractHistoryStorage(v)
+			if err != nil {
+				return err
+			}
+
+			list = append(list, h.entry())
+			return nil
+		})
+	})
+
+	return list, err
+}
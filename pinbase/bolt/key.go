@@ -0,0 +1,256 @@
+package bolt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/gob"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+// API keys look like <id>.<secret>, where the id picks out the key record and
+// only a hash of the whole key is stored.
+
+type KeyService struct {
+	db *bolt.DB
+}
+
+func getKeysBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	k := tx.Bucket(KeysBucketKey)
+	if k == nil {
+		return nil, errors.New("no keys bucket found")
+	}
+
+	return k, nil
+}
+
+type keyStorage struct {
+	Description string
+	Created     time.Time
+	Admin       bool
+	Hash        []byte
+}
+
+func (k *keyStorage) view(id string) *pinbase.KeyView {
+	return &pinbase.KeyView{
+		ID:          id,
+		Description: k.Description,
+		Created:     k.Created,
+		Admin:       k.Admin,
+	}
+}
+
+func extractKeyStorage(data []byte) (*keyStorage, error) {
+	var k keyStorage
+	err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&k)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode key data")
+	}
+
+	return &k, nil
+}
+
+func writeKeyStorage(keys *bolt.Bucket, id string, k *keyStorage) error {
+	var b bytes.Buffer
+	enc := gob.NewEncoder(&b)
+
+	err := enc.Encode(k)
+	if err != nil {
+		return errors.Wrap(err, "encode key data")
+	}
+
+	err = keys.Put([]byte(id), b.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "put key data")
+	}
+
+	return nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", errors.Wrap(err, "read random bytes")
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func hashKey(key string) []byte {
+	h := sha256.Sum256([]byte(key))
+	return h[:]
+}
+
+func (ks *KeyService) Keys() ([]*pinbase.KeyView, error) {
+	if ks.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	var list []*pinbase.KeyView
+
+	err := ks.db.View(func(tx *bolt.Tx) error {
+		keys, err := getKeysBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		c := keys.Cursor()
+
+		for id, v := c.First(); id != nil; id, v = c.Next() {
+			k, err := extractKeyStorage(v)
+			if err != nil {
+				return errors.Wrapf(err, "extract key %s", id)
+			}
+
+			list = append(list, k.view(string(id)))
+		}
+
+		return nil
+	})
+
+	return list, err
+}
+
+func (ks *KeyService) Key(id string) (*pinbase.KeyView, error) {
+	if ks.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	var kv *pinbase.KeyView
+
+	err := ks.db.View(func(tx *bolt.Tx) error {
+		keys, err := getKeysBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		data := keys.Get([]byte(id))
+		if data == nil {
+			// no key is not an error, just a nil key
+			return nil
+		}
+
+		k, err := extractKeyStorage(data)
+		if err != nil {
+			return err
+		}
+
+		kv = k.view(id)
+
+		return nil
+	})
+
+	return kv, err
+}
+
+func (ks *KeyService) CreateKey(kc *pinbase.KeyCreate) (*pinbase.KeyView, string, error) {
+	if ks.db == nil {
+		return nil, "", errors.New("no database connection")
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "generate key id")
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "generate key secret")
+	}
+
+	key := id + "." + secret
+
+	// the view should match what comes back out of the database, which does
+	// not keep the monotonic clock reading or the local time zone
+	k := &keyStorage{
+		Description: kc.Description,
+		Created:     time.Now().UTC().Round(0),
+		Admin:       kc.Admin,
+		Hash:        hashKey(key),
+	}
+
+	err = ks.db.Update(func(tx *bolt.Tx) error {
+		keys, err := getKeysBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		if keys.Get([]byte(id)) != nil {
+			return errors.New("key id already exists")
+		}
+
+		return writeKeyStorage(keys, id, k)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return k.view(id), key, nil
+}
+
+func (ks *KeyService) DeleteKey(id string) error {
+	if ks.db == nil {
+		return errors.New("no database connection")
+	}
+
+	return ks.db.Update(func(tx *bolt.Tx) error {
+		keys, err := getKeysBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		// deleting something that does not exist is not an error
+		return errors.Wrap(keys.Delete([]byte(id)), "delete key")
+	})
+}
+
+func (ks *KeyService) Authenticate(key string) (*pinbase.KeyView, error) {
+	i := strings.IndexByte(key, '.')
+	if i < 1 {
+		return nil, nil
+	}
+
+	id := key[:i]
+
+	if ks.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	var kv *pinbase.KeyView
+
+	err := ks.db.View(func(tx *bolt.Tx) error {
+		keys, err := getKeysBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		data := keys.Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+
+		k, err := extractKeyStorage(data)
+		if err != nil {
+			return err
+		}
+
+		if subtle.ConstantTimeCompare(k.Hash, hashKey(key)) == 1 {
+			kv = k.view(id)
+		}
+
+		return nil
+	})
+
+	return kv, err
+}
+
+var _ pinbase.KeyService = &KeyService{}
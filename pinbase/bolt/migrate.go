@@ -0,0 +1,247 @@
+package bolt
+
+import (
+	"encoding/binary"
+	"log"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+// The schema bucket keeps the version of the layout of the database under
+// schemaVersionKey, missing for databases from before it was kept. Open runs
+// the migrations the database has not been through yet.
+
+var schemaVersionKey = []byte("VERSION")
+
+// migrations bring the database from the version of their index to the next
+// one.
+var migrations = []func(tx *bolt.Tx) error{
+	canonicalizeHashes,
+}
+
+func migrateSchema(tx *bolt.Tx) error {
+	schema, err := tx.CreateBucketIfNotExists(SchemaBucketKey)
+	if err != nil {
+		return errors.Wrap(err, "create schema bucket")
+	}
+
+	var version uint64
+	if v := schema.Get(schemaVersionKey); v != nil {
+		version = binary.BigEndian.Uint64(v)
+	}
+
+	for ; version < uint64(len(migrations)); version++ {
+		err = migrations[version](tx)
+		if err != nil {
+			return errors.Wrapf(err, "migrate from schema version %d", version)
+		}
+	}
+
+	return errors.Wrap(schema.Put(schemaVersionKey, sequenceKey(version)), "put schema version")
+}
+
+// canonicalizeHashes moves the pins, pin histories and archived pins kept
+// under some other form of a CID to its canonical form, see
+// pinbase.Hash.Canonical, and rebuilds the indexes keyed by hashes. A pin
+// that lands on another pin of its party is dropped in favour of that one,
+// leaving its history behind.
+func canonicalizeHashes(tx *bolt.Tx) error {
+	parties, err := getPartiesBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	var partyIDs []pinbase.Hash
+	err = parties.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return errors.New("found a non-bucket party")
+		}
+
+		partyIDs = append(partyIDs, pinbase.Hash(k))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, partyID := range partyIDs {
+		err = canonicalizePins(parties.Bucket([]byte(partyID)), partyID)
+		if err != nil {
+			return err
+		}
+	}
+
+	archive, err := getArchiveBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	moves := make(map[string]pinbase.Hash)
+	err = archive.ForEach(func(k, v []byte) error {
+		if c := pinbase.Hash(k).Canonical(); string(c) != string(k) {
+			moves[string(k)] = c
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for k, c := range moves {
+		v := append([]byte(nil), archive.Get([]byte(k))...)
+
+		err = archive.Delete([]byte(k))
+		if err != nil {
+			return errors.Wrapf(err, "delete archived pin %s", k)
+		}
+
+		if archive.Get([]byte(c)) != nil {
+			continue
+		}
+
+		err = archive.Put([]byte(c), v)
+		if err != nil {
+			return errors.Wrapf(err, "put archived pin %s", c)
+		}
+	}
+
+	err = tx.DeleteBucket(PinOwnersBucketKey)
+	if err != nil {
+		return errors.Wrap(err, "delete pin owners bucket")
+	}
+
+	owners, err := tx.CreateBucket(PinOwnersBucketKey)
+	if err != nil {
+		return errors.Wrap(err, "create pin owners bucket")
+	}
+
+	err = indexPinOwners(tx, owners)
+	if err != nil {
+		return errors.Wrap(err, "index pin owners")
+	}
+
+	err = tx.DeleteBucket(NameTargetsBucketKey)
+	if err != nil {
+		return errors.Wrap(err, "delete name targets bucket")
+	}
+
+	targets, err := tx.CreateBucket(NameTargetsBucketKey)
+	if err != nil {
+		return errors.Wrap(err, "create name targets bucket")
+	}
+
+	return errors.Wrap(indexNameTargets(tx, targets), "index name targets")
+}
+
+func canonicalizePins(party *bolt.Bucket, partyID pinbase.Hash) error {
+	pins := party.Bucket(PartyBucketPinsBucketKey)
+	if pins == nil {
+		return errors.New("did not get a pins bucket")
+	}
+
+	type move struct {
+		from, to pinbase.Hash
+		p        *pinStorage
+	}
+	var moves []move
+
+	err := pins.ForEach(func(k, v []byte) error {
+		p, err := extractPinStorage(v)
+		if err != nil {
+			return err
+		}
+
+		id := pinbase.Hash(k)
+		m := move{from: id, to: canonicalPinID(p.Kind, id), p: p}
+
+		if p.canonicalizeTargets() || m.to != m.from {
+			moves = append(moves, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	histories := party.Bucket(PartyBucketHistoryBucketKey)
+
+	for _, m := range moves {
+		if m.to != m.from {
+			err = pins.Delete([]byte(m.from))
+			if err != nil {
+				return errors.Wrapf(err, "delete pin %s", m.from)
+			}
+
+			if pins.Get([]byte(m.to)) != nil {
+				log.Printf("dropping pin %s of party %s in favour of its pin %s", m.from, partyID, m.to)
+				continue
+			}
+
+			if histories != nil {
+				err = moveHistory(histories, m.from, m.to)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		err = writePinStorage(pins, m.to, m.p)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// canonicalizeTargets puts the targets of a name pin in canonical form and
+// reports whether that changed any of them.
+func (p *pinStorage) canonicalizeTargets() bool {
+	changed := false
+	canonical := func(h *pinbase.Hash) {
+		if c := h.Canonical(); c != *h {
+			*h = c
+			changed = true
+		}
+	}
+
+	canonical(&p.Target)
+	for i := range p.Previous {
+		canonical(&p.Previous[i].Target)
+	}
+	for i := range p.Resolutions {
+		canonical(&p.Resolutions[i].Target)
+	}
+
+	return changed
+}
+
+// moveHistory moves the history of a pin over to another pin ID, unless that
+// one has a history of its own already.
+func moveHistory(histories *bolt.Bucket, from, to pinbase.Hash) error {
+	old := histories.Bucket([]byte(from))
+	if old == nil || histories.Bucket([]byte(to)) != nil {
+		return nil
+	}
+
+	history, err := histories.CreateBucket([]byte(to))
+	if err != nil {
+		return errors.Wrapf(err, "create pin history bucket for %s", to)
+	}
+
+	err = old.ForEach(func(k, v []byte) error {
+		return history.Put(k, v)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "copy pin history of %s", from)
+	}
+
+	err = history.SetSequence(old.Sequence())
+	if err != nil {
+		return errors.Wrapf(err, "set pin history sequence of %s", to)
+	}
+
+	return errors.Wrapf(histories.DeleteBucket([]byte(from)), "delete pin history bucket for %s", from)
+}
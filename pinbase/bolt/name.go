@@ -0,0 +1,642 @@
+package bolt
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+// Name pins are left out of the pin owners index, as their IDs are names
+// rather than hashes. The name targets bucket indexes the hashes they hold
+// instead: it holds a bucket for every such hash, which holds a bucket for
+// every party with name pins holding it, which in turn holds a key for each of
+// those pins.
+
+// resolutionHistory is how many resolutions of its name a name pin keeps.
+const resolutionHistory = 20
+
+type previousTargetStorage struct {
+	Target pinbase.Hash
+	Until  time.Time
+}
+
+type resolutionStorage struct {
+	Time         time.Time
+	Target       pinbase.Hash
+	ErrorMessage string
+}
+
+func getNameTargetsBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	t := tx.Bucket(NameTargetsBucketKey)
+	if t == nil {
+		return nil, errors.New("no name targets bucket found")
+	}
+
+	return t, nil
+}
+
+func addNameTarget(targets *bolt.Bucket, h, partyID, pinID pinbase.Hash) error {
+	t, err := targets.CreateBucketIfNotExists([]byte(h))
+	if err != nil {
+		return errors.Wrapf(err, "create name targets bucket for %s", h)
+	}
+
+	p, err := t.CreateBucketIfNotExists([]byte(partyID))
+	if err != nil {
+		return errors.Wrapf(err, "create name targets bucket for %s of party %s", h, partyID)
+	}
+
+	return errors.Wrapf(p.Put([]byte(pinID), sentinel), "add name target %s", h)
+}
+
+func removeNameTarget(targets *bolt.Bucket, h, partyID, pinID pinbase.Hash) error {
+	t := targets.Bucket([]byte(h))
+	if t == nil {
+		return nil
+	}
+
+	p := t.Bucket([]byte(partyID))
+	if p == nil {
+		return nil
+	}
+
+	err := p.Delete([]byte(pinID))
+	if err != nil {
+		return errors.Wrapf(err, "remove name target %s", h)
+	}
+
+	if k, _ := p.Cursor().First(); k == nil {
+		err = t.DeleteBucket([]byte(partyID))
+		if err != nil {
+			return errors.Wrapf(err, "delete name targets bucket for %s of party %s", h, partyID)
+		}
+	}
+
+	if k, _ := t.Cursor().First(); k == nil {
+		err = targets.DeleteBucket([]byte(h))
+		if err != nil {
+			return errors.Wrapf(err, "delete name targets bucket for %s", h)
+		}
+	}
+
+	return nil
+}
+
+// indexNameTargets fills in the name targets bucket from the name pins of all
+// the parties.
+func indexNameTargets(tx *bolt.Tx, targets *bolt.Bucket) error {
+	parties, err := getPartiesBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	partiesC := parties.Cursor()
+
+	for partyK, partyV := partiesC.First(); partyK != nil; partyK, partyV = partiesC.Next() {
+		if partyV != nil {
+			return errors.New("found a non-bucket party")
+		}
+
+		pins, err := getPinsBucket(tx, pinbase.Hash(partyK))
+		if err != nil {
+			return err
+		}
+
+		pinsC := pins.Cursor()
+
+		for pinK, pinV := pinsC.First(); pinK != nil; pinK, pinV = pinsC.Next() {
+			p, err := extractPinStorage(pinV)
+			if err != nil {
+				return err
+			}
+			if p.Kind != pinbase.PinKindName {
+				continue
+			}
+
+			for _, h := range p.hashes(pinbase.Hash(pinK)) {
+				err = addNameTarget(targets, h, pinbase.Hash(partyK), pinbase.Hash(pinK))
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// nameTargets lists the name pins holding h by party.
+func nameTargets(targets *bolt.Bucket, h pinbase.Hash) map[pinbase.Hash][]pinbase.Hash {
+	t := targets.Bucket([]byte(h))
+	if t == nil {
+		return nil
+	}
+
+	m := make(map[pinbase.Hash][]pinbase.Hash)
+
+	c := t.Cursor()
+	for partyK, _ := c.First(); partyK != nil; partyK, _ = c.Next() {
+		p := t.Bucket(partyK)
+		if p == nil {
+			log.Printf("non-bucket party %s found in the name targets of %s", partyK, h)
+			continue
+		}
+
+		pc := p.Cursor()
+		for pinK, _ := pc.First(); pinK != nil; pinK, _ = pc.Next() {
+			m[pinbase.Hash(partyK)] = append(m[pinbase.Hash(partyK)], pinbase.Hash(pinK))
+		}
+	}
+
+	return m
+}
+
+// hashHeld reports whether any pin, by hash or by name, still holds h.
+func hashHeld(owners, targets *bolt.Bucket, h pinbase.Hash) bool {
+	return owners.Bucket([]byte(h)) != nil || targets.Bucket([]byte(h)) != nil
+}
+
+// releasePin drops the party's pin from the indexes, archiving the hashes
+// no other pin holds, and returns the hashes it held.
+func releasePin(tx *bolt.Tx, partyID, pinID pinbase.Hash, p *pinStorage) ([]pinbase.Hash, error) {
+	owners, err := getOwnersBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := getNameTargetsBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := p.hashes(pinID)
+
+	for _, h := range hashes {
+		if p.Kind == pinbase.PinKindName {
+			err = removeNameTarget(targets, h, partyID, pinID)
+		} else {
+			_, err = removePinOwner(owners, h, partyID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = archiveUnheld(tx, owners, targets, hashes...)
+	if err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// archiveUnheld archives the hashes no pin holds anymore, so that they get
+// unpinned.
+func archiveUnheld(tx *bolt.Tx, owners, targets *bolt.Bucket, hs ...pinbase.Hash) error {
+	archive, err := getArchiveBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range hs {
+		if hashHeld(owners, targets, h) {
+			continue
+		}
+
+		err = writeArchiveStorage(archive, h, &archiveStorage{Status: pinbase.PinPending})
+		if err != nil {
+			return errors.Wrapf(err, "archive pin %s", h)
+		}
+	}
+
+	return nil
+}
+
+// hashes lists the hashes the pin holds: its ID for hash pins, and the current
+// and previous targets for name pins.
+func (p *pinStorage) hashes(id pinbase.Hash) []pinbase.Hash {
+	if p.Kind != pinbase.PinKindName {
+		return []pinbase.Hash{id}
+	}
+
+	var hs []pinbase.Hash
+	if p.Target != "" {
+		hs = append(hs, p.Target)
+	}
+	for _, prev := range p.Previous {
+		hs = append(hs, prev.Target)
+	}
+
+	return hs
+}
+
+// heldPrevious reports whether h is a previous target of the pin still in its
+// grace period at now.
+func (p *pinStorage) heldPrevious(h pinbase.Hash, now time.Time) bool {
+	for _, prev := range p.Previous {
+		if prev.Target == h && prev.Until.After(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *pinStorage) resolutions() []*pinbase.PinResolution {
+	if len(p.Resolutions) == 0 {
+		return nil
+	}
+
+	list := make([]*pinbase.PinResolution, 0, len(p.Resolutions))
+	for _, r := range p.Resolutions {
+		list = append(list, &pinbase.PinResolution{
+			Time:   r.Time,
+			Target: r.Target,
+			Error:  r.ErrorMessage,
+		})
+	}
+
+	return list
+}
+
+// addResolution records the outcome of resolving the pin's name, unless it is
+// the same as the last one.
+func (p *pinStorage) addResolution(now time.Time, target pinbase.Hash, err error) {
+	r := resolutionStorage{Time: now.UTC(), Target: target}
+	if err != nil {
+		r = resolutionStorage{Time: now.UTC(), ErrorMessage: err.Error()}
+	}
+
+	if n := len(p.Resolutions); n > 0 {
+		last := p.Resolutions[n-1]
+		if last.Target == r.Target && last.ErrorMessage == r.ErrorMessage {
+			return
+		}
+	}
+
+	p.Resolutions = append(p.Resolutions, r)
+	if n := len(p.Resolutions); n > resolutionHistory {
+		p.Resolutions = p.Resolutions[n-resolutionHistory:]
+	}
+}
+
+// partyPin names a pin of a party.
+type partyPin struct {
+	partyID, pinID pinbase.Hash
+}
+
+// namePins lists the wanted name pins for the name, if name is not empty, or
+// all of them otherwise.
+func namePins(tx *bolt.Tx, name string) ([]partyPin, error) {
+	parties, err := getPartiesBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []partyPin
+
+	err = parties.ForEach(func(partyK, partyV []byte) error {
+		if partyV != nil {
+			return nil
+		}
+
+		pins := parties.Bucket(partyK).Bucket(PartyBucketPinsBucketKey)
+		if pins == nil {
+			return nil
+		}
+
+		add := func(pinK, pinV []byte) error {
+			p, err := extractPinStorage(pinV)
+			if err != nil {
+				log.Printf("failed to extract pin %s for party %s: %s", pinK, partyK, err)
+				return nil
+			}
+
+			if p.Kind == pinbase.PinKindName && p.WantPinned {
+				list = append(list, partyPin{pinbase.Hash(partyK), pinbase.Hash(pinK)})
+			}
+			return nil
+		}
+
+		if name == "" {
+			return pins.ForEach(add)
+		}
+
+		if v := pins.Get([]byte(name)); v != nil {
+			return add([]byte(name), v)
+		}
+		return nil
+	})
+
+	return list, err
+}
+
+// DueNames lists the names of the wanted name pins that are due to be
+// resolved again.
+func (ps *PinService) DueNames(now time.Time) []string {
+	if ps.db == nil {
+		log.Print("no database connection")
+		return nil
+	}
+
+	due := make(map[string]struct{})
+
+	err := ps.db.View(func(tx *bolt.Tx) error {
+		list, err := namePins(tx, "")
+		if err != nil {
+			return err
+		}
+
+		for _, pp := range list {
+			pins, err := getPinsBucket(tx, pp.partyID)
+			if err != nil {
+				return err
+			}
+
+			p, err := extractPinStorage(pins.Get([]byte(pp.pinID)))
+			if err != nil {
+				return err
+			}
+
+			if !p.NextResolve.After(now) {
+				due[string(pp.pinID)] = struct{}{}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Printf("error in bolt transaction: %s", err)
+		return nil
+	}
+
+	var names []string
+	for name := range due {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// NotifyName records the outcome of resolving the name with the wanted name
+// pins for it. Pins whose name now points somewhere else switch over to the
+// new target, holding on to the old one for the grace period.
+func (ps *PinService) NotifyName(name string, target pinbase.Hash, resolveErr error, now time.Time) {
+	if ps.db == nil {
+		log.Print("no database connection")
+		return
+	}
+
+	var changed []pinbase.Hash
+
+	err := ps.db.Update(func(tx *bolt.Tx) error {
+		changed = nil
+
+		list, err := namePins(tx, name)
+		if err != nil {
+			return err
+		}
+
+		owners, err := getOwnersBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		targets, err := getNameTargetsBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		archive, err := getArchiveBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, pp := range list {
+			pins, err := getPinsBucket(tx, pp.partyID)
+			if err != nil {
+				return err
+			}
+
+			p, err := extractPinStorage(pins.Get([]byte(pp.pinID)))
+			if err != nil {
+				return err
+			}
+
+			p.NextResolve = now.Add(ps.resolveInterval)
+			p.addResolution(now, target, resolveErr)
+
+			if resolveErr != nil || target == p.Target {
+				err = writePinStorage(pins, pp.pinID, p)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			old := p.Target
+
+			// the new target may be an old one still in its grace period
+			var previous []previousTargetStorage
+			for _, prev := range p.Previous {
+				if prev.Target != target {
+					previous = append(previous, prev)
+				}
+			}
+			p.Previous = previous
+
+			if old != "" {
+				if ps.nameGrace > 0 {
+					p.Previous = append(p.Previous, previousTargetStorage{Target: old, Until: now.Add(ps.nameGrace)})
+				} else {
+					err = removeNameTarget(targets, old, pp.partyID, pp.pinID)
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			err = addNameTarget(targets, target, pp.partyID, pp.pinID)
+			if err != nil {
+				return err
+			}
+
+			// the target has an owner again, so it is no longer the
+			// archive's concern
+			err = archive.Delete([]byte(target))
+			if err != nil {
+				return errors.Wrap(err, "unarchive the target")
+			}
+
+			if old != "" && ps.nameGrace <= 0 {
+				err = archiveUnheld(tx, owners, targets, old)
+				if err != nil {
+					return err
+				}
+			}
+
+			p.Target = target
+			p.Status = pinbase.PinPending
+			p.LastErrorMessage = ""
+			p.Attempts = 0
+			p.NextAttempt = time.Time{}
+			p.Progress = pinbase.PinProgress{}
+			p.Nodes = nil
+			p.Size = 0
+
+			err = writePinStorage(pins, pp.pinID, p)
+			if err != nil {
+				return err
+			}
+
+			err = appendPinHistory(tx, pp.partyID, pp.pinID, &historyStorage{
+				Time:    now.UTC(),
+				Change:  pinbase.PinChangeResolved,
+				Status:  p.Status,
+				Changes: []string{"target: " + targetName(old) + " -> " + string(target)},
+			})
+			if err != nil {
+				return err
+			}
+
+			changed = append(changed, target)
+			if old != "" {
+				changed = append(changed, old)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Printf("error in bolt transaction: %s", err)
+		return
+	}
+
+	if len(changed) > 0 {
+		ps.bumpPins(changed...)
+	}
+}
+
+func targetName(h pinbase.Hash) string {
+	if h == "" {
+		return "none"
+	}
+
+	return string(h)
+}
+
+// ReleaseTargets lets go of the previous targets of name pins whose grace
+// period is over, archiving the ones no other pin holds.
+func (ps *PinService) ReleaseTargets(now time.Time) {
+	if ps.db == nil {
+		log.Print("no database connection")
+		return
+	}
+
+	isDue := func(p *pinStorage) bool {
+		for _, prev := range p.Previous {
+			if !prev.Until.After(now) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// most sweeps have nothing to release, so there is no need to hold up
+	// writers while looking
+	var due []partyPin
+
+	err := ps.db.View(func(tx *bolt.Tx) error {
+		var err error
+		due, err = matchingPins(tx, isDue)
+		return err
+	})
+	if err != nil {
+		log.Printf("error in bolt transaction: %s", err)
+		return
+	}
+
+	if len(due) == 0 {
+		return
+	}
+
+	var released []pinbase.Hash
+
+	err = ps.db.Update(func(tx *bolt.Tx) error {
+		released = nil
+
+		parties, err := getPartiesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		owners, err := getOwnersBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		targets, err := getNameTargetsBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, pp := range due {
+			// the pin may have changed or been deleted since
+			p := partyPinStorage(parties, pp.partyID, pp.pinID)
+			if p == nil || !isDue(p) {
+				continue
+			}
+
+			pins, err := getPinsBucket(tx, pp.partyID)
+			if err != nil {
+				return err
+			}
+
+			var kept []previousTargetStorage
+			var hs []pinbase.Hash
+			for _, prev := range p.Previous {
+				if prev.Until.After(now) {
+					kept = append(kept, prev)
+					continue
+				}
+
+				hs = append(hs, prev.Target)
+
+				err = removeNameTarget(targets, prev.Target, pp.partyID, pp.pinID)
+				if err != nil {
+					return err
+				}
+			}
+			p.Previous = kept
+
+			err = writePinStorage(pins, pp.pinID, p)
+			if err != nil {
+				return err
+			}
+
+			err = archiveUnheld(tx, owners, targets, hs...)
+			if err != nil {
+				return err
+			}
+
+			released = append(released, hs...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Printf("error in bolt transaction: %s", err)
+		return
+	}
+
+	if len(released) > 0 {
+		ps.bumpPins(released...)
+	}
+}
+
+var _ pinbase.PinNameTracker = &PinService{}
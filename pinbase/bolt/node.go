@@ -0,0 +1,253 @@
+package bolt
+
+import (
+	"bytes"
+	"encoding/gob"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+type NodeService struct {
+	db *bolt.DB
+}
+
+func getNodesBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	n := tx.Bucket(NodesBucketKey)
+	if n == nil {
+		return nil, errors.New("no nodes bucket found")
+	}
+
+	return n, nil
+}
+
+type nodeStorage struct {
+	APIAddress string
+	Reachable  bool
+	LastSeen   time.Time
+	PinCount   int
+	RepoSize   int64
+}
+
+func (n *nodeStorage) view(id string) *pinbase.NodeView {
+	return &pinbase.NodeView{
+		ID:         id,
+		APIAddress: n.APIAddress,
+		Reachable:  n.Reachable,
+		LastSeen:   n.LastSeen,
+		PinCount:   n.PinCount,
+		RepoSize:   n.RepoSize,
+	}
+}
+
+func extractNodeStorage(data []byte) (*nodeStorage, error) {
+	var n nodeStorage
+	err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&n)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode node data")
+	}
+
+	return &n, nil
+}
+
+func writeNodeStorage(nodes *bolt.Bucket, id string, n *nodeStorage) error {
+	var b bytes.Buffer
+	enc := gob.NewEncoder(&b)
+
+	err := enc.Encode(n)
+	if err != nil {
+		return errors.Wrap(err, "encode node data")
+	}
+
+	err = nodes.Put([]byte(id), b.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "put node data")
+	}
+
+	return nil
+}
+
+func (ns *NodeService) Nodes() ([]*pinbase.NodeView, error) {
+	if ns.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	var list []*pinbase.NodeView
+
+	err := ns.db.View(func(tx *bolt.Tx) error {
+		nodes, err := getNodesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		c := nodes.Cursor()
+
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			n, err := extractNodeStorage(v)
+			if err != nil {
+				return errors.Wrapf(err, "extract node %s", k)
+			}
+
+			list = append(list, n.view(string(k)))
+		}
+
+		return nil
+	})
+
+	return list, err
+}
+
+func (ns *NodeService) Node(id string) (*pinbase.NodeView, error) {
+	if ns.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	var nv *pinbase.NodeView
+
+	err := ns.db.View(func(tx *bolt.Tx) error {
+		nodes, err := getNodesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		data := nodes.Get([]byte(id))
+		if data == nil {
+			// no node is not an error, just a nil node
+			return nil
+		}
+
+		n, err := extractNodeStorage(data)
+		if err != nil {
+			return err
+		}
+
+		nv = n.view(id)
+
+		return nil
+	})
+
+	return nv, err
+}
+
+func (ns *NodeService) CreateNode(nc *pinbase.NodeCreate) error {
+	if ns.db == nil {
+		return errors.New("no database connection")
+	}
+
+	if nc.ID == "" {
+		return errors.New("node needs a name")
+	}
+
+	if nc.APIAddress == "" {
+		return errors.New("node needs an API address")
+	}
+
+	return ns.db.Update(func(tx *bolt.Tx) error {
+		nodes, err := getNodesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		if nodes.Get([]byte(nc.ID)) != nil {
+			return errors.New("node already exists")
+		}
+
+		return writeNodeStorage(
+			nodes,
+			nc.ID,
+			&nodeStorage{
+				APIAddress: nc.APIAddress,
+			},
+		)
+	})
+}
+
+func (ns *NodeService) DeleteNode(id string) error {
+	if ns.db == nil {
+		return errors.New("no database connection")
+	}
+
+	return ns.db.Update(func(tx *bolt.Tx) error {
+		nodes, err := getNodesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		// deleting something that does not exist is not an error
+		return errors.Wrap(nodes.Delete([]byte(id)), "delete node")
+	})
+}
+
+func (ns *NodeService) UpdateNode(id string, ne *pinbase.NodeEdit) error {
+	if ns.db == nil {
+		return errors.New("no database connection")
+	}
+
+	if ne.APIAddress == "" {
+		return errors.New("node needs an API address")
+	}
+
+	return ns.db.Update(func(tx *bolt.Tx) error {
+		nodes, err := getNodesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		data := nodes.Get([]byte(id))
+		if data == nil {
+			return errors.New("could not find node")
+		}
+
+		n, err := extractNodeStorage(data)
+		if err != nil {
+			return err
+		}
+
+		if n.APIAddress != ne.APIAddress {
+			// whatever we knew about the old address does not hold anymore
+			n = &nodeStorage{
+				APIAddress: ne.APIAddress,
+			}
+		}
+
+		return writeNodeStorage(nodes, id, n)
+	})
+}
+
+func (ns *NodeService) NotifyNode(id string, h *pinbase.NodeHealth) error {
+	if ns.db == nil {
+		return errors.New("no database connection")
+	}
+
+	return ns.db.Update(func(tx *bolt.Tx) error {
+		nodes, err := getNodesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		data := nodes.Get([]byte(id))
+		if data == nil {
+			// the node was deleted while it was being checked
+			return nil
+		}
+
+		n, err := extractNodeStorage(data)
+		if err != nil {
+			return err
+		}
+
+		n.Reachable = h.Reachable
+
+		if h.Reachable {
+			n.LastSeen = time.Now()
+			n.PinCount = h.PinCount
+			n.RepoSize = h.RepoSize
+		}
+
+		return writeNodeStorage(nodes, id, n)
+	})
+}
+
+var _ pinbase.NodeService = &NodeService{}
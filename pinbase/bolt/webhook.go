@@ -0,0 +1,432 @@
+package bolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"log"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+// Webhooks live in a bucket of their party, so they go away along with it.
+// Deliveries are queued in a bucket of their own, keyed by sequence so the
+// oldest come first, and are dropped once their webhook is gone.
+
+type WebhookService struct {
+	db    *bolt.DB
+	retry pinbase.RetryPolicy
+}
+
+func getWebhookDeliveriesBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	d := tx.Bucket(WebhookDeliveriesBucketKey)
+	if d == nil {
+		return nil, errors.New("no webhook deliveries bucket found")
+	}
+
+	return d, nil
+}
+
+// getWebhooksBucket returns the webhooks bucket of the party, which is nil for
+// parties that never had a webhook.
+func getWebhooksBucket(tx *bolt.Tx, partyID pinbase.Hash) (*bolt.Bucket, error) {
+	parties, err := getPartiesBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	party := parties.Bucket([]byte(partyID))
+	if party == nil {
+		return nil, errors.New("could not find party")
+	}
+
+	return party.Bucket(PartyBucketWebhooksBucketKey), nil
+}
+
+type webhookStorage struct {
+	URL     string
+	Secret  string
+	Created time.Time
+}
+
+func (w *webhookStorage) view(partyID pinbase.Hash, id string) *pinbase.WebhookView {
+	return &pinbase.WebhookView{
+		ID:      id,
+		Party:   partyID,
+		URL:     w.URL,
+		Created: w.Created,
+	}
+}
+
+type deliveryStorage struct {
+	Party            pinbase.Hash
+	Webhook          string
+	Event            pinbase.PinEvent
+	Attempts         int
+	NextAttempt      time.Time
+	LastErrorMessage string
+}
+
+func extractWebhookStorage(data []byte) (*webhookStorage, error) {
+	var w webhookStorage
+	err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&w)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode webhook data")
+	}
+
+	return &w, nil
+}
+
+func writeWebhookStorage(webhooks *bolt.Bucket, id string, w *webhookStorage) error {
+	var b bytes.Buffer
+	enc := gob.NewEncoder(&b)
+
+	err := enc.Encode(w)
+	if err != nil {
+		return errors.Wrap(err, "encode webhook data")
+	}
+
+	err = webhooks.Put([]byte(id), b.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "put webhook data")
+	}
+
+	return nil
+}
+
+func extractDeliveryStorage(data []byte) (*deliveryStorage, error) {
+	var d deliveryStorage
+	err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&d)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode webhook delivery data")
+	}
+
+	return &d, nil
+}
+
+func writeDeliveryStorage(deliveries *bolt.Bucket, id uint64, d *deliveryStorage) error {
+	var b bytes.Buffer
+	enc := gob.NewEncoder(&b)
+
+	err := enc.Encode(d)
+	if err != nil {
+		return errors.Wrap(err, "encode webhook delivery data")
+	}
+
+	err = deliveries.Put(sequenceKey(id), b.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "put webhook delivery data")
+	}
+
+	return nil
+}
+
+func (ws *WebhookService) Webhooks(partyID pinbase.Hash) ([]*pinbase.WebhookView, error) {
+	if ws.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	var list []*pinbase.WebhookView
+
+	err := ws.db.View(func(tx *bolt.Tx) error {
+		webhooks, err := getWebhooksBucket(tx, partyID)
+		if err != nil || webhooks == nil {
+			return err
+		}
+
+		return webhooks.ForEach(func(id, v []byte) error {
+			w, err := extractWebhookStorage(v)
+			if err != nil {
+				return errors.Wrapf(err, "extract webhook %s", id)
+			}
+
+			list = append(list, w.view(partyID, string(id)))
+			return nil
+		})
+	})
+
+	return list, err
+}
+
+func (ws *WebhookService) Webhook(partyID pinbase.Hash, id string) (*pinbase.WebhookView, error) {
+	if ws.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	var wv *pinbase.WebhookView
+
+	err := ws.db.View(func(tx *bolt.Tx) error {
+		webhooks, err := getWebhooksBucket(tx, partyID)
+		if err != nil || webhooks == nil {
+			return err
+		}
+
+		data := webhooks.Get([]byte(id))
+		if data == nil {
+			// no webhook is not an error, just a nil webhook
+			return nil
+		}
+
+		w, err := extractWebhookStorage(data)
+		if err != nil {
+			return err
+		}
+
+		wv = w.view(partyID, id)
+
+		return nil
+	})
+
+	return wv, err
+}
+
+func (ws *WebhookService) CreateWebhook(partyID pinbase.Hash, wc *pinbase.WebhookCreate) (*pinbase.WebhookView, string, error) {
+	if ws.db == nil {
+		return nil, "", errors.New("no database connection")
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "generate webhook id")
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "generate webhook secret")
+	}
+
+	w := &webhookStorage{
+		URL:     wc.URL,
+		Secret:  secret,
+		Created: time.Now().UTC().Round(0),
+	}
+
+	err = ws.db.Update(func(tx *bolt.Tx) error {
+		parties, err := getPartiesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		party := parties.Bucket([]byte(partyID))
+		if party == nil {
+			return errors.New("could not find party")
+		}
+
+		webhooks, err := party.CreateBucketIfNotExists(PartyBucketWebhooksBucketKey)
+		if err != nil {
+			return errors.Wrap(err, "create party-webhooks bucket")
+		}
+
+		if webhooks.Get([]byte(id)) != nil {
+			return errors.New("webhook id already exists")
+		}
+
+		return writeWebhookStorage(webhooks, id, w)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return w.view(partyID, id), secret, nil
+}
+
+func (ws *WebhookService) DeleteWebhook(partyID pinbase.Hash, id string) error {
+	if ws.db == nil {
+		return errors.New("no database connection")
+	}
+
+	return ws.db.Update(func(tx *bolt.Tx) error {
+		webhooks, err := getWebhooksBucket(tx, partyID)
+		if err != nil {
+			return err
+		}
+
+		// deleting something that does not exist is not an error
+		if webhooks != nil {
+			err = webhooks.Delete([]byte(id))
+			if err != nil {
+				return errors.Wrap(err, "delete webhook")
+			}
+		}
+
+		deliveries, err := getWebhookDeliveriesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		var drop [][]byte
+		err = deliveries.ForEach(func(k, v []byte) error {
+			d, err := extractDeliveryStorage(v)
+			if err != nil {
+				return err
+			}
+
+			if d.Party == partyID && d.Webhook == id {
+				drop = append(drop, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range drop {
+			err := deliveries.Delete(k)
+			if err != nil {
+				return errors.Wrap(err, "drop webhook delivery")
+			}
+		}
+
+		return nil
+	})
+}
+
+// queueWebhookDeliveries queues the event for each webhook of its party.
+func queueWebhookDeliveries(tx *bolt.Tx, e *pinbase.PinEvent) error {
+	webhooks, err := getWebhooksBucket(tx, e.Party)
+	if err != nil || webhooks == nil {
+		return err
+	}
+
+	deliveries, err := getWebhookDeliveriesBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	return webhooks.ForEach(func(id, _ []byte) error {
+		seq, err := deliveries.NextSequence()
+		if err != nil {
+			return errors.Wrap(err, "get webhook delivery sequence")
+		}
+
+		return writeDeliveryStorage(
+			deliveries,
+			seq,
+			&deliveryStorage{
+				Party:   e.Party,
+				Webhook: string(id),
+				Event:   *e,
+			},
+		)
+	})
+}
+
+func (ws *WebhookService) DueWebhookDeliveries(limit int) ([]*pinbase.WebhookDelivery, error) {
+	if ws.db == nil {
+		return nil, errors.New("no database connection")
+	}
+
+	now := time.Now()
+
+	var list []*pinbase.WebhookDelivery
+
+	err := ws.db.Update(func(tx *bolt.Tx) error {
+		list = nil
+
+		deliveries, err := getWebhookDeliveriesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		var orphans [][]byte
+
+		c := deliveries.Cursor()
+		for k, v := c.First(); k != nil && len(list) < limit; k, v = c.Next() {
+			d, err := extractDeliveryStorage(v)
+			if err != nil {
+				return err
+			}
+
+			if d.NextAttempt.After(now) {
+				continue
+			}
+
+			var data []byte
+			webhooks, err := getWebhooksBucket(tx, d.Party)
+			if err == nil && webhooks != nil {
+				data = webhooks.Get([]byte(d.Webhook))
+			}
+			if data == nil {
+				// the webhook or its party went away
+				orphans = append(orphans, k)
+				continue
+			}
+
+			w, err := extractWebhookStorage(data)
+			if err != nil {
+				return err
+			}
+
+			e := d.Event
+			list = append(list, &pinbase.WebhookDelivery{
+				ID:       binary.BigEndian.Uint64(k),
+				Webhook:  w.view(d.Party, d.Webhook),
+				Secret:   w.Secret,
+				Event:    &e,
+				Attempts: d.Attempts,
+			})
+		}
+
+		for _, k := range orphans {
+			err := deliveries.Delete(k)
+			if err != nil {
+				return errors.Wrap(err, "drop orphaned webhook delivery")
+			}
+		}
+
+		return nil
+	})
+
+	return list, err
+}
+
+func (ws *WebhookService) WebhookDelivered(id uint64, deliveryErr error) error {
+	if ws.db == nil {
+		return errors.New("no database connection")
+	}
+
+	now := time.Now()
+	retry := ws.retry
+
+	return ws.db.Update(func(tx *bolt.Tx) error {
+		deliveries, err := getWebhookDeliveriesBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		k := sequenceKey(id)
+
+		data := deliveries.Get(k)
+		if data == nil {
+			// the webhook was deleted in the meantime
+			return nil
+		}
+
+		if deliveryErr == nil {
+			return errors.Wrap(deliveries.Delete(k), "drop webhook delivery")
+		}
+
+		d, err := extractDeliveryStorage(data)
+		if err != nil {
+			return err
+		}
+
+		d.Attempts++
+		d.LastErrorMessage = deliveryErr.Error()
+
+		if retry.Fatal(d.Attempts) {
+			log.Printf("giving up on webhook delivery %d to %s after %d attempts: %s", id, d.Webhook, d.Attempts, deliveryErr)
+			return errors.Wrap(deliveries.Delete(k), "drop webhook delivery")
+		}
+
+		d.NextAttempt = now.Add(retry.Delay(d.Attempts))
+
+		return writeDeliveryStorage(deliveries, id, d)
+	})
+}
+
+var _ pinbase.WebhookService = &WebhookService{}
+var _ pinbase.WebhookQueue = &WebhookService{}
@@ -0,0 +1,119 @@
+package pinbase
+
+import (
+	"sync"
+	"time"
+)
+
+// PinEvent tells that one of a party's pins changed status.
+type PinEvent struct {
+	Party     Hash
+	Pin       Hash
+	Status    PinStatus
+	LastError string
+	Time      time.Time
+}
+
+// PinEventPublisher is told about pin status changes.
+type PinEventPublisher interface {
+	PublishPinEvent(e *PinEvent)
+}
+
+// subscriptionBuffer is how many events a subscriber may fall behind before
+// it is dropped.
+const subscriptionBuffer = 64
+
+// EventHub hands the pin events published to it out to its subscribers.
+// Subscribers that fall too far behind are dropped rather than holding up
+// the publisher, they can subscribe again and catch up from the pins
+// themselves.
+type EventHub struct {
+	m      sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+func NewEventHub() *EventHub {
+	return &EventHub{
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives the events of a party, or of every party when it has
+// no party. C is closed once the subscription ends.
+type Subscription struct {
+	C <-chan *PinEvent
+
+	c     chan *PinEvent
+	hub   *EventHub
+	party Hash
+}
+
+// Subscribe starts a subscription to the events of the party, or of every
+// party for an empty partyID.
+func (h *EventHub) Subscribe(partyID Hash) *Subscription {
+	c := make(chan *PinEvent, subscriptionBuffer)
+	s := &Subscription{
+		C:     c,
+		c:     c,
+		hub:   h,
+		party: partyID,
+	}
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if h.closed {
+		close(s.c)
+		return s
+	}
+
+	h.subs[s] = struct{}{}
+	return s
+}
+
+func (h *EventHub) PublishPinEvent(e *PinEvent) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	for s := range h.subs {
+		if s.party != "" && s.party != e.Party {
+			continue
+		}
+
+		select {
+		case s.c <- e:
+		default:
+			h.drop(s)
+		}
+	}
+}
+
+// Close ends all the subscriptions, along with any made later.
+func (h *EventHub) Close() {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	for s := range h.subs {
+		h.drop(s)
+	}
+	h.closed = true
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.hub.m.Lock()
+	defer s.hub.m.Unlock()
+
+	if _, ok := s.hub.subs[s]; ok {
+		s.hub.drop(s)
+	}
+}
+
+// drop must be called with the lock held.
+func (h *EventHub) drop(s *Subscription) {
+	delete(h.subs, s)
+	close(s.c)
+}
+
+var _ PinEventPublisher = &EventHub{}
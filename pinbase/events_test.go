@@ -0,0 +1,76 @@
+package pinbase
+
+import (
+	"testing"
+)
+
+func receive(t *testing.T, tag string, s *Subscription) []Hash {
+	var got []Hash
+	for {
+		select {
+		case e, ok := <-s.C:
+			if !ok {
+				return append(got, "closed")
+			}
+			got = append(got, e.Pin)
+		default:
+			return got
+		}
+	}
+}
+
+func checkReceived(t *testing.T, tag string, s *Subscription, expected ...Hash) {
+	got := receive(t, tag, s)
+
+	if len(got) != len(expected) {
+		t.Errorf("%s: got events %v, expected %v", tag, got, expected)
+		return
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("%s: got events %v, expected %v", tag, got, expected)
+			return
+		}
+	}
+}
+
+func TestEventHub(t *testing.T) {
+	h := NewEventHub()
+
+	all := h.Subscribe("")
+	foo := h.Subscribe("foo")
+	bar := h.Subscribe("bar")
+
+	h.PublishPinEvent(&PinEvent{Party: "foo", Pin: "a", Status: PinPinned})
+	h.PublishPinEvent(&PinEvent{Party: "bar", Pin: "b", Status: PinPinned})
+	h.PublishPinEvent(&PinEvent{Party: "foo", Pin: "c", Status: PinError})
+
+	checkReceived(t, "all", all, "a", "b", "c")
+	checkReceived(t, "foo", foo, "a", "c")
+	checkReceived(t, "bar", bar, "b")
+
+	bar.Close()
+	bar.Close()
+	h.PublishPinEvent(&PinEvent{Party: "bar", Pin: "d", Status: PinPinned})
+
+	checkReceived(t, "closed bar", bar, "closed")
+	checkReceived(t, "all after bar closed", all, "d")
+
+	// foo falls behind while all keeps up
+	for i := 0; i < subscriptionBuffer+1; i++ {
+		h.PublishPinEvent(&PinEvent{Party: "foo", Pin: "e", Status: PinPinned})
+		receive(t, "keeping up", all)
+	}
+
+	got := receive(t, "slow foo", foo)
+	if len(got) != subscriptionBuffer+1 || got[len(got)-1] != "closed" {
+		t.Errorf("slow foo should have been dropped after %d events, got %d: %v", subscriptionBuffer, len(got), got[len(got)-1])
+	}
+
+	h.Close()
+	checkReceived(t, "all after hub closed", all, "closed")
+
+	late := h.Subscribe("")
+	checkReceived(t, "subscribed after hub closed", late, "closed")
+	late.Close()
+}
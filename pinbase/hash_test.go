@@ -0,0 +1,58 @@
+package pinbase
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseHash(t *testing.T) {
+	const emptyDir = "bafybeiczsscdsbs7ffqz55asqdf3smv6klcw3gofszvwlyarci47bgf354"
+
+	for _, tc := range []struct {
+		s        string
+		expected Hash
+	}{
+		{"QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn", emptyDir},
+		{emptyDir, emptyDir},
+		{"zdj7WbTaiJT1fgatdet9Ei9iDB5hdCxkbVyhyh8YTUnXMiwYi", emptyDir},
+		{"k2jmtxtlhjl3fhmgndf92e48by79ryjuvqp3y2qgehpao6v3lurvnmcv", emptyDir},
+		// the codec is kept
+		{"k51qzi5uqu5dlvj2baxnqndepeb86cbk3ng7n3i46uzyxzyqj2xjonzllnv0v8", "bafzaajaiaejcbzdibmxyzdjbbehgvizh6g5tikvy47mshdy6gwbruvgwvd24seje"},
+	} {
+		h, err := ParseHash(tc.s)
+		if err != nil {
+			t.Errorf("%s: got error %+v", tc.s, err)
+			continue
+		}
+		if h != tc.expected {
+			t.Errorf("%s: got %s, expected %s", tc.s, h, tc.expected)
+		}
+		if c := Hash(tc.s).Canonical(); c != tc.expected {
+			t.Errorf("%s: got canonical %s, expected %s", tc.s, c, tc.expected)
+		}
+	}
+
+	for _, s := range []string{"", "foo", "bar", "QmHash", "example.com", "/ipfs/" + emptyDir} {
+		_, err := ParseHash(s)
+		if errors.Cause(err) != ErrInvalidHash {
+			t.Errorf("%q: got error %v, expected an invalid hash", s, err)
+		}
+		if c := Hash(s).Canonical(); c != Hash(s) {
+			t.Errorf("%q: got canonical %s", s, c)
+		}
+	}
+}
+
+func TestCanonicalName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"example.com": "example.com",
+		"k51qzi5uqu5dlvj2baxnqndepeb86cbk3ng7n3i46uzyxzyqj2xjonzllnv0v8": "bafzaajaiaejcbzdibmxyzdjbbehgvizh6g5tikvy47mshdy6gwbruvgwvd24seje",
+		// peer IDs in CIDv0 form are IPNS keys rather than dag-pb objects
+		"QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn": "bafzbeiczsscdsbs7ffqz55asqdf3smv6klcw3gofszvwlyarci47bgf354",
+	} {
+		if got := CanonicalName(name); got != expected {
+			t.Errorf("%s: got %s, expected %s", name, got, expected)
+		}
+	}
+}
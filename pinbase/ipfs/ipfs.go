@@ -1,6 +1,9 @@
 package ipfs
 
 import (
+	"context"
+	"encoding/json"
+	"io"
 	"strings"
 
 	"github.com/apiarian/ipfs-pinbase/pinbase"
@@ -24,12 +27,56 @@ func (ic *IPFSClient) Ping() bool {
 	return ic.s.IsUp()
 }
 
-func (ic *IPFSClient) Pin(h pinbase.Hash) error {
-	return errors.Wrap(ic.s.Pin("/ipfs/"+string(h)), "pin hash")
+func (ic *IPFSClient) Pin(ctx context.Context, h pinbase.Hash, m pinbase.PinMode) error {
+	err := ic.s.Request("pin/add", "/ipfs/"+string(h)).
+		Option("recursive", m == pinbase.PinRecursive).
+		Exec(ctx, nil)
+
+	return errors.Wrap(err, "pin hash")
 }
 
-func (ic *IPFSClient) Unpin(h pinbase.Hash) error {
-	err := ic.s.Unpin("/ipfs/" + string(h))
+// PinProgress pins h like Pin does, passing on the number of blocks the node
+// reports as fetched. The node does not say how many bytes that adds up to.
+func (ic *IPFSClient) PinProgress(ctx context.Context, h pinbase.Hash, m pinbase.PinMode, progress func(pinbase.PinProgress)) error {
+	resp, err := ic.s.Request("pin/add", "/ipfs/"+string(h)).
+		Option("recursive", m == pinbase.PinRecursive).
+		Option("progress", true).
+		Send(ctx)
+	if err != nil {
+		return errors.Wrap(err, "pin hash")
+	}
+	defer resp.Close()
+
+	if resp.Error != nil {
+		return errors.Wrap(resp.Error, "pin hash")
+	}
+
+	dec := json.NewDecoder(resp.Output)
+
+	for {
+		var out struct {
+			Pins     []string
+			Progress int64
+		}
+
+		err := dec.Decode(&out)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "read pin progress")
+		}
+
+		if out.Progress > 0 && progress != nil {
+			progress(pinbase.PinProgress{Blocks: out.Progress})
+		}
+	}
+}
+
+func (ic *IPFSClient) Unpin(ctx context.Context, h pinbase.Hash) error {
+	err := ic.s.Request("pin/rm", "/ipfs/"+string(h)).
+		Option("recursive", true).
+		Exec(ctx, nil)
 
 	if err != nil && strings.HasSuffix(err.Error(), "not pinned") {
 		return nil
@@ -38,20 +85,123 @@ func (ic *IPFSClient) Unpin(h pinbase.Hash) error {
 	return errors.Wrap(err, "unpin hash")
 }
 
-func (ic *IPFSClient) Pins() (map[pinbase.Hash]struct{}, error) {
-	pins, err := ic.s.Pins()
+func (ic *IPFSClient) Pins(ctx context.Context) (map[pinbase.Hash]pinbase.PinMode, error) {
+	var raw struct{ Keys map[string]shell.PinInfo }
+
+	err := ic.s.Request("pin/ls").Exec(ctx, &raw)
 	if err != nil {
 		return nil, errors.Wrap(err, "get pins")
 	}
 
-	r := make(map[pinbase.Hash]struct{})
-	for h, t := range pins {
-		if t.Type == shell.RecursivePin || t.Type == shell.DirectPin {
-			r[pinbase.Hash(h)] = struct{}{}
+	r := make(map[pinbase.Hash]pinbase.PinMode)
+	for k, t := range raw.Keys {
+		// the node lists pins in whatever form they were pinned
+		h := pinbase.Hash(k).Canonical()
+
+		switch t.Type {
+		case shell.RecursivePin:
+			r[h] = pinbase.PinRecursive
+		case shell.DirectPin:
+			r[h] = pinbase.PinDirect
 		}
 	}
 
 	return r, nil
 }
 
-var _ pinbase.PinJuggler = &IPFSClient{}
+// RepoSize returns the number of bytes the node's repo takes up.
+func (ic *IPFSClient) RepoSize(ctx context.Context) (int64, error) {
+	var raw struct{ RepoSize int64 }
+
+	err := ic.s.Request("repo/stat").Exec(ctx, &raw)
+	if err != nil {
+		return 0, errors.Wrap(err, "get repo stat")
+	}
+
+	return raw.RepoSize, nil
+}
+
+// PinSize returns the cumulative size of the object for recursive pins and the
+// size of its root block for direct ones.
+func (ic *IPFSClient) PinSize(ctx context.Context, h pinbase.Hash, m pinbase.PinMode) (int64, error) {
+	if m == pinbase.PinDirect {
+		var raw struct{ Size int64 }
+
+		err := ic.s.Request("block/stat", "/ipfs/"+string(h)).Exec(ctx, &raw)
+		if err != nil {
+			return 0, errors.Wrap(err, "get block stat")
+		}
+
+		return raw.Size, nil
+	}
+
+	var raw struct{ CumulativeSize int64 }
+
+	err := ic.s.Request("object/stat", "/ipfs/"+string(h)).Exec(ctx, &raw)
+	if err != nil {
+		return 0, errors.Wrap(err, "get object stat")
+	}
+
+	return raw.CumulativeSize, nil
+}
+
+// ResolveName returns the hash an IPNS name or DNSLink domain points at,
+// following names that point at other names.
+func (ic *IPFSClient) ResolveName(ctx context.Context, name string) (pinbase.Hash, error) {
+	var raw struct{ Path string }
+
+	err := ic.s.Request("name/resolve", "/ipns/"+name).
+		Option("recursive", true).
+		Exec(ctx, &raw)
+	if err != nil {
+		return "", errors.Wrap(err, "resolve name")
+	}
+
+	h, ok := resolvedHash(raw.Path)
+	if !ok {
+		return "", errors.Errorf("name resolved to %q rather than a hash", raw.Path)
+	}
+
+	return h, nil
+}
+
+// ResolvePath returns the hash an IPFS path such as /ipfs/<hash>/sub/path
+// points at.
+func (ic *IPFSClient) ResolvePath(ctx context.Context, path string) (pinbase.Hash, error) {
+	var raw struct{ Path string }
+
+	err := ic.s.Request("resolve", path).
+		Option("recursive", true).
+		Exec(ctx, &raw)
+	if err != nil {
+		return "", errors.Wrap(err, "resolve path")
+	}
+
+	h, ok := resolvedHash(raw.Path)
+	if !ok {
+		return "", errors.Errorf("path resolved to %q rather than a hash", raw.Path)
+	}
+
+	return h, nil
+}
+
+// resolvedHash returns the hash of a resolved path, which should be a plain
+// /ipfs/<hash>, in canonical form.
+func resolvedHash(p string) (pinbase.Hash, bool) {
+	s := strings.TrimPrefix(p, "/ipfs/")
+	if s == p {
+		return "", false
+	}
+
+	h, err := pinbase.ParseHash(s)
+	if err != nil {
+		return "", false
+	}
+
+	return h, true
+}
+
+var _ pinbase.PinSizer = &IPFSClient{}
+var _ pinbase.NameResolver = &IPFSClient{}
+var _ pinbase.PathResolver = &IPFSClient{}
+var _ pinbase.PinProgressJuggler = &IPFSClient{}
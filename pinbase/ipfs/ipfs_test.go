@@ -2,13 +2,19 @@ package ipfs
 
 import (
 	"bytes"
+	"context"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/ipfs/go-ipfs-api"
 )
 
 func TestPinning(t *testing.T) {
+	ctx := context.Background()
+
 	s0, err := newShellForNode(0)
 	if err != nil {
 		t.Fatalf("failed to get shell: %+v", err)
@@ -40,131 +46,363 @@ func TestPinning(t *testing.T) {
 
 	// start out with nothing pinned
 
-	pins, err := c.Pins()
+	pins, err := c.Pins(ctx)
 	if err != nil {
 		t.Errorf("failed to get pins: %+v", err)
 	}
 
-	if _, pinned := pins[pinbase.Hash(h1)]; pinned {
+	if _, pinned := pins[pinbase.Hash(h1).Canonical()]; pinned {
 		t.Errorf("object 1 (%s) somehow pinned already: %+v", h1, pins)
 	}
 
-	if _, pinned := pins[pinbase.Hash(h2)]; pinned {
+	if _, pinned := pins[pinbase.Hash(h2).Canonical()]; pinned {
 		t.Errorf("object 1 (%s) somehow pinned already: %+v", h2, pins)
 	}
 
 	// pin objects 1 and 2
 
-	err = c.Pin(pinbase.Hash(h1))
+	err = c.Pin(ctx, pinbase.Hash(h1), pinbase.PinRecursive)
 	if err != nil {
 		t.Errorf("failed to pin object 1: %+v", err)
 	}
 
-	err = c.Pin(pinbase.Hash(h2))
+	err = c.Pin(ctx, pinbase.Hash(h2), pinbase.PinRecursive)
 	if err != nil {
 		t.Errorf("failed to pin object 2: %+v", err)
 	}
 
 	time.Sleep(10 * time.Millisecond)
 
-	pins, err = c.Pins()
+	pins, err = c.Pins(ctx)
 	if err != nil {
 		t.Errorf("failed to get pins")
 	}
 
-	if _, pinned := pins[pinbase.Hash(h1)]; !pinned {
+	if _, pinned := pins[pinbase.Hash(h1).Canonical()]; !pinned {
 		t.Errorf("object 1 (%s) not pinned: %+v", h1, pins)
 	}
 
-	if _, pinned := pins[pinbase.Hash(h2)]; !pinned {
+	if _, pinned := pins[pinbase.Hash(h2).Canonical()]; !pinned {
 		t.Errorf("object 1 (%s) not pinned: %+v", h2, pins)
 	}
 
 	// pinning object 1 again is not a problem
 
-	err = c.Pin(pinbase.Hash(h1))
+	err = c.Pin(ctx, pinbase.Hash(h1), pinbase.PinRecursive)
 	if err != nil {
 		t.Errorf("failed to pin object 1 again: %+v", err)
 	}
 
 	time.Sleep(10 * time.Millisecond)
 
-	pins, err = c.Pins()
+	pins, err = c.Pins(ctx)
 	if err != nil {
 		t.Errorf("failed to get pins")
 	}
 
-	if _, pinned := pins[pinbase.Hash(h1)]; !pinned {
+	if _, pinned := pins[pinbase.Hash(h1).Canonical()]; !pinned {
 		t.Errorf("object 1 (%s) not pinned: %+v", h1, pins)
 	}
 
-	if _, pinned := pins[pinbase.Hash(h2)]; !pinned {
+	if _, pinned := pins[pinbase.Hash(h2).Canonical()]; !pinned {
 		t.Errorf("object 1 (%s) not pinned: %+v", h2, pins)
 	}
 
 	// unpin object 1
 
-	err = c.Unpin(pinbase.Hash(h1))
+	err = c.Unpin(ctx, pinbase.Hash(h1))
 	if err != nil {
 		t.Errorf("failed to unpin object 1: %+v", err)
 	}
 
 	time.Sleep(10 * time.Millisecond)
 
-	pins, err = c.Pins()
+	pins, err = c.Pins(ctx)
 	if err != nil {
 		t.Errorf("failed to get pins")
 	}
 
-	if _, pinned := pins[pinbase.Hash(h1)]; pinned {
+	if _, pinned := pins[pinbase.Hash(h1).Canonical()]; pinned {
 		t.Errorf("object 1 (%s) still pinned: %+v", h1, pins)
 	}
 
-	if _, pinned := pins[pinbase.Hash(h2)]; !pinned {
+	if _, pinned := pins[pinbase.Hash(h2).Canonical()]; !pinned {
 		t.Errorf("object 1 (%s) not pinned: %+v", h2, pins)
 	}
 
 	// unpinning junk should fail
 
-	err = c.Unpin(pinbase.Hash(h2 + "foobar"))
+	err = c.Unpin(ctx, pinbase.Hash(h2+"foobar"))
 	if err == nil {
 		t.Errorf("did not get an error unpinning junk")
 	}
 
 	time.Sleep(10 * time.Millisecond)
 
-	pins, err = c.Pins()
+	pins, err = c.Pins(ctx)
 	if err != nil {
 		t.Errorf("failed to get pins")
 	}
 
-	if _, pinned := pins[pinbase.Hash(h1)]; pinned {
+	if _, pinned := pins[pinbase.Hash(h1).Canonical()]; pinned {
 		t.Errorf("object 1 (%s) still pinned: %+v", h1, pins)
 	}
 
-	if _, pinned := pins[pinbase.Hash(h2)]; !pinned {
+	if _, pinned := pins[pinbase.Hash(h2).Canonical()]; !pinned {
 		t.Errorf("object 1 (%s) not pinned: %+v", h2, pins)
 	}
 
 	// unpin object 1 again (an error in ipfs, but not in pinbase)
 
-	err = c.Unpin(pinbase.Hash(h1))
+	err = c.Unpin(ctx, pinbase.Hash(h1))
 	if err != nil {
 		t.Errorf("failed to unpin an unpinned object 1: %+v", err)
 	}
 
 	time.Sleep(10 * time.Millisecond)
 
-	pins, err = c.Pins()
+	pins, err = c.Pins(ctx)
 	if err != nil {
 		t.Errorf("failed to get pins")
 	}
 
-	if _, pinned := pins[pinbase.Hash(h1)]; pinned {
+	if _, pinned := pins[pinbase.Hash(h1).Canonical()]; pinned {
 		t.Errorf("object 1 (%s) still pinned: %+v", h1, pins)
 	}
 
-	if _, pinned := pins[pinbase.Hash(h2)]; !pinned {
+	if _, pinned := pins[pinbase.Hash(h2).Canonical()]; !pinned {
 		t.Errorf("object 1 (%s) not pinned: %+v", h2, pins)
 	}
 }
+
+func TestPinDeadline(t *testing.T) {
+	s0, err := newShellForNode(0)
+	if err != nil {
+		t.Fatalf("failed to get shell: %+v", err)
+	}
+
+	apiAddr, err := addressForNode(1)
+	if err != nil {
+		t.Fatalf("failed to get node address: %+v", err)
+	}
+
+	c, err := NewIPFSClient(apiAddr)
+	if err != nil {
+		t.Fatalf("failed to get client: %+v", err)
+	}
+
+	// nobody actually has this object, so pinning it can never finish
+	h, err := s0.Add(
+		bytes.NewBufferString("nobody has this "+time.Now().String()),
+		shell.OnlyHash(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to hash the missing object: %+v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	err = c.Pin(ctx, pinbase.Hash(h), pinbase.PinRecursive)
+	if err == nil {
+		t.Errorf("pinning missing object %s did not fail", h)
+	}
+
+	if d := time.Since(start); d > 1*time.Second {
+		t.Errorf("pinning missing object did not honor the deadline: %s", d)
+	}
+
+	pins, err := c.Pins(context.Background())
+	if err != nil {
+		t.Errorf("failed to get pins: %+v", err)
+	}
+
+	if _, pinned := pins[pinbase.Hash(h).Canonical()]; pinned {
+		t.Errorf("missing object %s somehow pinned: %+v", h, pins)
+	}
+}
+
+func TestPinProgress(t *testing.T) {
+	s0, err := newShellForNode(0)
+	if err != nil {
+		t.Fatalf("failed to get shell: %+v", err)
+	}
+
+	apiAddr, err := addressForNode(1)
+	if err != nil {
+		t.Fatalf("failed to get node address: %+v", err)
+	}
+
+	c, err := NewIPFSClient(apiAddr)
+	if err != nil {
+		t.Fatalf("failed to get client: %+v", err)
+	}
+
+	// big enough to be split into several blocks
+	h, err := s0.Add(bytes.NewBufferString(strings.Repeat("a big thing "+time.Now().String(), 100000)))
+	if err != nil {
+		t.Fatalf("failed to create object: %+v", err)
+	}
+
+	var last pinbase.PinProgress
+
+	err = c.PinProgress(
+		context.Background(),
+		pinbase.Hash(h),
+		pinbase.PinRecursive,
+		func(p pinbase.PinProgress) { last = p },
+	)
+	if err != nil {
+		t.Errorf("failed to pin object: %+v", err)
+	}
+
+	if last.Blocks < 2 {
+		t.Errorf("did not get progress for the blocks of %s: %+v", h, last)
+	}
+
+	pins, err := c.Pins(context.Background())
+	if err != nil {
+		t.Errorf("failed to get pins: %+v", err)
+	}
+
+	if _, pinned := pins[pinbase.Hash(h).Canonical()]; !pinned {
+		t.Errorf("object %s not pinned: %+v", h, pins)
+	}
+}
+
+func TestPinModes(t *testing.T) {
+	ctx := context.Background()
+
+	s0, err := newShellForNode(0)
+	if err != nil {
+		t.Fatalf("failed to get shell: %+v", err)
+	}
+
+	// a direct pin does not fetch anything, so stick to the node that has
+	// the block already
+	apiAddr, err := addressForNode(0)
+	if err != nil {
+		t.Fatalf("failed to get node address: %+v", err)
+	}
+
+	c, err := NewIPFSClient(apiAddr)
+	if err != nil {
+		t.Fatalf("failed to get client: %+v", err)
+	}
+
+	h, err := s0.Add(bytes.NewBufferString("a thing for modes " + time.Now().String()))
+	if err != nil {
+		t.Fatalf("failed to create object: %+v", err)
+	}
+
+	err = c.Unpin(ctx, pinbase.Hash(h))
+	if err != nil {
+		t.Fatalf("failed to unpin the added object: %+v", err)
+	}
+
+	// direct first, then recursive, which takes the direct pin over
+	for _, m := range []pinbase.PinMode{pinbase.PinDirect, pinbase.PinRecursive} {
+		err = c.Pin(ctx, pinbase.Hash(h), m)
+		if err != nil {
+			t.Errorf("failed to pin object %s: %+v", m, err)
+		}
+
+		pins, err := c.Pins(ctx)
+		if err != nil {
+			t.Errorf("failed to get pins: %+v", err)
+		}
+
+		if pm, pinned := pins[pinbase.Hash(h).Canonical()]; !pinned || pm != m {
+			t.Errorf("object %s not pinned %s: %+v", h, m, pins)
+		}
+	}
+}
+
+// replicationBackend wants a single hash pinned on a number of nodes and
+// passes on whatever it is told about it.
+type replicationBackend struct {
+	h        pinbase.Hash
+	r        int
+	notified chan *pinbase.PinBackendState
+}
+
+func (rb *replicationBackend) PinProcessorBump() <-chan struct{} {
+	return nil
+}
+
+func (rb *replicationBackend) PinRequirements() map[pinbase.Hash]pinbase.PinRequirement {
+	return map[pinbase.Hash]pinbase.PinRequirement{
+		rb.h: {WantPinned: true, Replication: rb.r},
+	}
+}
+
+func (rb *replicationBackend) DirtyPinRequirements() map[pinbase.Hash]pinbase.PinRequirement {
+	return nil
+}
+
+func (rb *replicationBackend) NotifyPin(h pinbase.Hash, s *pinbase.PinBackendState) {
+	if h == rb.h && s.Status != pinbase.PinPinning {
+		rb.notified <- s
+	}
+}
+
+func TestReplication(t *testing.T) {
+	s0, err := newShellForNode(0)
+	if err != nil {
+		t.Fatalf("failed to get shell: %+v", err)
+	}
+
+	nodes := make(pinbase.StaticNodes)
+	for _, n := range []int{0, 1} {
+		apiAddr, err := addressForNode(n)
+		if err != nil {
+			t.Fatalf("failed to get node address: %+v", err)
+		}
+
+		c, err := NewIPFSClient(apiAddr)
+		if err != nil {
+			t.Fatalf("failed to get client: %+v", err)
+		}
+
+		nodes[strconv.Itoa(n)] = c
+	}
+
+	h, err := s0.Add(bytes.NewBufferString("a replicated thing " + time.Now().String()))
+	if err != nil {
+		t.Fatalf("failed to create object: %+v", err)
+	}
+
+	rb := &replicationBackend{
+		h:        pinbase.Hash(h).Canonical(),
+		r:        2,
+		notified: make(chan *pinbase.PinBackendState),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go pinbase.ManagePins(ctx, make(chan struct{}), rb, nodes, time.Minute, 2, 10*time.Second)
+
+	select {
+	case s := <-rb.notified:
+		if s.Status != pinbase.PinPinned {
+			t.Errorf("object %s not replicated: %s %v %+v", h, s.Status, s.LastError, s.Nodes)
+		}
+
+	case <-time.After(15 * time.Second):
+		t.Fatalf("did not hear back about object %s", h)
+	}
+
+	for name, c := range nodes {
+		pins, err := c.Pins(context.Background())
+		if err != nil {
+			t.Errorf("failed to get pins from node %s: %+v", name, err)
+		}
+
+		if _, pinned := pins[pinbase.Hash(h).Canonical()]; !pinned {
+			t.Errorf("object %s not pinned on node %s", h, name)
+		}
+	}
+}
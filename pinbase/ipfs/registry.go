@@ -0,0 +1,242 @@
+package ipfs
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/pkg/errors"
+)
+
+// Registry is a pinbase.NodeSet made up of the nodes registered with a
+// pinbase.NodeService. Clients are reused for as long as a node keeps its API
+// address.
+type Registry struct {
+	ns pinbase.NodeService
+
+	m       sync.Mutex
+	clients map[string]*registeredClient
+}
+
+type registeredClient struct {
+	addr string
+	c    *IPFSClient
+}
+
+func NewRegistry(ns pinbase.NodeService) *Registry {
+	return &Registry{
+		ns:      ns,
+		clients: make(map[string]*registeredClient),
+	}
+}
+
+// Nodes returns a client for every registered node. If the registry can not
+// be read, the nodes from the last successful read are used instead.
+func (r *Registry) Nodes() map[string]pinbase.PinJuggler {
+	err := r.refresh()
+	if err != nil {
+		log.Printf("failed to refresh the node registry: %+v", err)
+	}
+
+	nodes := make(map[string]pinbase.PinJuggler)
+	for name, c := range r.snapshot() {
+		nodes[name] = c
+	}
+
+	return nodes
+}
+
+func (r *Registry) snapshot() map[string]*IPFSClient {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	clients := make(map[string]*IPFSClient)
+	for name, rc := range r.clients {
+		clients[name] = rc.c
+	}
+
+	return clients
+}
+
+func (r *Registry) refresh() error {
+	nvs, err := r.ns.Nodes()
+	if err != nil {
+		return errors.Wrap(err, "get registered nodes")
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	clients := make(map[string]*registeredClient)
+	for _, nv := range nvs {
+		rc, ok := r.clients[nv.ID]
+		if !ok || rc.addr != nv.APIAddress {
+			c, err := NewIPFSClient(nv.APIAddress)
+			if err != nil {
+				return errors.Wrapf(err, "create client for node %s", nv.ID)
+			}
+
+			rc = &registeredClient{addr: nv.APIAddress, c: c}
+		}
+
+		clients[nv.ID] = rc
+	}
+
+	r.clients = clients
+
+	return nil
+}
+
+// Check asks every registered node for its pins and repo size and records the
+// outcome with the node service. Each node is given at most timeout to answer.
+func (r *Registry) Check(timeout time.Duration) error {
+	err := r.refresh()
+	if err != nil {
+		return err
+	}
+
+	for name, c := range r.snapshot() {
+		h := checkNode(c, timeout)
+		if !h.Reachable {
+			log.Printf("failed to reach ipfs node %s", name)
+		}
+
+		err := r.ns.NotifyNode(name, h)
+		if err != nil {
+			return errors.Wrapf(err, "notify node %s", name)
+		}
+	}
+
+	return nil
+}
+
+func checkNode(c *IPFSClient, timeout time.Duration) *pinbase.NodeHealth {
+	if !c.Ping() {
+		return &pinbase.NodeHealth{Reachable: false}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	pins, err := c.Pins(ctx)
+	if err != nil {
+		return &pinbase.NodeHealth{Reachable: false}
+	}
+
+	size, err := c.RepoSize(ctx)
+	if err != nil {
+		return &pinbase.NodeHealth{Reachable: false}
+	}
+
+	return &pinbase.NodeHealth{
+		Reachable: true,
+		PinCount:  len(pins),
+		RepoSize:  size,
+	}
+}
+
+// PinSize asks the registered nodes for the size of the pin in name order,
+// going with the first answer.
+func (r *Registry) PinSize(ctx context.Context, h pinbase.Hash, m pinbase.PinMode) (int64, error) {
+	err := r.refresh()
+	if err != nil {
+		return 0, err
+	}
+
+	clients := r.snapshot()
+
+	err = pinbase.ErrNoNodes
+	for _, name := range sortedNames(clients) {
+		var size int64
+		size, err = clients[name].PinSize(ctx, h, m)
+		if err == nil {
+			return size, nil
+		}
+
+		err = errors.Wrapf(err, "node %s", name)
+	}
+
+	return 0, err
+}
+
+// ResolveName asks the registered nodes to resolve the name in name order,
+// going with the first answer.
+func (r *Registry) ResolveName(ctx context.Context, name string) (pinbase.Hash, error) {
+	err := r.refresh()
+	if err != nil {
+		return "", err
+	}
+
+	clients := r.snapshot()
+
+	err = pinbase.ErrNoNodes
+	for _, node := range sortedNames(clients) {
+		var h pinbase.Hash
+		h, err = clients[node].ResolveName(ctx, name)
+		if err == nil {
+			return h, nil
+		}
+
+		err = errors.Wrapf(err, "node %s", node)
+	}
+
+	return "", err
+}
+
+// ResolvePath asks the registered nodes to resolve the path in name order,
+// going with the first answer.
+func (r *Registry) ResolvePath(ctx context.Context, path string) (pinbase.Hash, error) {
+	err := r.refresh()
+	if err != nil {
+		return "", err
+	}
+
+	clients := r.snapshot()
+
+	err = pinbase.ErrNoNodes
+	for _, node := range sortedNames(clients) {
+		var h pinbase.Hash
+		h, err = clients[node].ResolvePath(ctx, path)
+		if err == nil {
+			return h, nil
+		}
+
+		err = errors.Wrapf(err, "node %s", node)
+	}
+
+	return "", err
+}
+
+func sortedNames(clients map[string]*IPFSClient) []string {
+	var names []string
+	for name := range clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Monitor runs Check every interval until done is closed.
+func (r *Registry) Monitor(done <-chan struct{}, interval time.Duration) {
+	for {
+		err := r.Check(interval)
+		if err != nil {
+			log.Printf("failed to check the ipfs nodes: %+v", err)
+		}
+
+		select {
+		case <-done:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+var _ pinbase.NodeSet = &Registry{}
+var _ pinbase.PinSizer = &Registry{}
+var _ pinbase.NameResolver = &Registry{}
+var _ pinbase.PathResolver = &Registry{}
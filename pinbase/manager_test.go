@@ -1,14 +1,18 @@
 package pinbase
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 type NullBackend struct {
 	Bumper chan struct{}
 	c      int
+	d      int
 	m      *sync.Mutex
 }
 
@@ -29,12 +33,26 @@ func (nb *NullBackend) PinsCallCount() int {
 	return nb.c
 }
 
-func (nb *NullBackend) PinRequirements() map[Hash]bool {
+func (nb *NullBackend) DirtyCallCount() int {
+	nb.m.Lock()
+	defer nb.m.Unlock()
+	return nb.d
+}
+
+func (nb *NullBackend) PinRequirements() map[Hash]PinRequirement {
 	nb.m.Lock()
 	defer nb.m.Unlock()
 	nb.c = nb.c + 1
 
-	return make(map[Hash]bool)
+	return make(map[Hash]PinRequirement)
+}
+
+func (nb *NullBackend) DirtyPinRequirements() map[Hash]PinRequirement {
+	nb.m.Lock()
+	defer nb.m.Unlock()
+	nb.d = nb.d + 1
+
+	return make(map[Hash]PinRequirement)
 }
 
 func (nb *NullBackend) NotifyPin(_ Hash, _ *PinBackendState) {
@@ -50,16 +68,16 @@ func NewNullJuggler() *NullJuggler {
 	return &NullJuggler{}
 }
 
-func (nj *NullJuggler) Pin(Hash) error {
+func (nj *NullJuggler) Pin(context.Context, Hash, PinMode) error {
 	return nil
 }
 
-func (nj *NullJuggler) Unpin(Hash) error {
+func (nj *NullJuggler) Unpin(context.Context, Hash) error {
 	return nil
 }
 
-func (nj *NullJuggler) Pins() (map[Hash]struct{}, error) {
-	return make(map[Hash]struct{}), nil
+func (nj *NullJuggler) Pins(context.Context) (map[Hash]PinMode, error) {
+	return make(map[Hash]PinMode), nil
 }
 
 var _ PinJuggler = &NullJuggler{}
@@ -73,7 +91,7 @@ func TestManagePins(t *testing.T) {
 		t.Errorf("did not start out with a zero reqs call count: %d", c)
 	}
 
-	go ManagePins(done, pb, pj, 3*time.Second)
+	go ManagePins(context.Background(), done, pb, StaticNodes{"local": pj}, 3*time.Second, 1, 0)
 
 	time.Sleep(10 * time.Millisecond)
 
@@ -85,15 +103,19 @@ func TestManagePins(t *testing.T) {
 
 	time.Sleep(10 * time.Millisecond)
 
-	if c := pb.PinsCallCount(); c != 2 {
-		t.Errorf("reqs call count should be 2: %d", c)
+	if c := pb.PinsCallCount(); c != 1 {
+		t.Errorf("bump should not do a full sweep, reqs call count should be 1: %d", c)
+	}
+
+	if c := pb.DirtyCallCount(); c != 1 {
+		t.Errorf("dirty reqs call count should be 1: %d", c)
 	}
 
 	// wait for the timeout to trip
 	time.Sleep(4 * time.Second)
 
-	if c := pb.PinsCallCount(); c != 3 {
-		t.Errorf("reqs call count should be 3: %d", c)
+	if c := pb.PinsCallCount(); c != 2 {
+		t.Errorf("reqs call count should be 2: %d", c)
 	}
 
 	close(done)
@@ -104,7 +126,239 @@ func TestManagePins(t *testing.T) {
 
 	time.Sleep(10 * time.Millisecond)
 
+	if c := pb.PinsCallCount(); c != 2 {
+		t.Errorf("reqs call count should be 2: %d", c)
+	}
+
+	if c := pb.DirtyCallCount(); c != 1 {
+		t.Errorf("dirty reqs call count should be 1: %d", c)
+	}
+}
+
+type ExpiringBackend struct {
+	*NullBackend
+	e int
+}
+
+func (eb *ExpiringBackend) ExpirePins(now time.Time) {
+	eb.m.Lock()
+	defer eb.m.Unlock()
+	eb.e = eb.e + 1
+}
+
+func (eb *ExpiringBackend) ExpireCallCount() int {
+	eb.m.Lock()
+	defer eb.m.Unlock()
+	return eb.e
+}
+
+var _ PinExpirer = &ExpiringBackend{}
+
+func TestManagePinsExpires(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pb := &ExpiringBackend{NullBackend: NewNullBackend()}
+
+	go ManagePins(ctx, make(chan struct{}), pb, StaticNodes{"local": NewNullJuggler()}, 50*time.Millisecond, 1, 0)
+
+	time.Sleep(10 * time.Millisecond)
+
+	if c := pb.ExpireCallCount(); c != 1 {
+		t.Errorf("the first sweep should expire pins, expire call count should be 1: %d", c)
+	}
+
+	go func(c chan<- struct{}) { c <- struct{}{} }(pb.Bumper)
+
+	time.Sleep(10 * time.Millisecond)
+
+	if c := pb.ExpireCallCount(); c != 1 {
+		t.Errorf("a bump should not expire pins, expire call count should be 1: %d", c)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if e, c := pb.ExpireCallCount(), pb.PinsCallCount(); e != 2 || c != 2 {
+		t.Errorf("each sweep should expire pins first, expire and reqs call counts should be 2: %d %d", e, c)
+	}
+}
+
+type SchedulingBackend struct {
+	*NullBackend
+	next      time.Time
+	dirty     time.Time
+	nextCalls int
+}
+
+func (sb *SchedulingBackend) NextPinChange(now time.Time) time.Time {
+	sb.m.Lock()
+	defer sb.m.Unlock()
+	sb.nextCalls++
+	if !sb.next.After(now) {
+		return time.Time{}
+	}
+	return sb.next
+}
+
+func (sb *SchedulingBackend) DirtyPinChange(now time.Time) time.Time {
+	sb.m.Lock()
+	defer sb.m.Unlock()
+	dirty := sb.dirty
+	sb.dirty = time.Time{}
+	if !dirty.After(now) {
+		return time.Time{}
+	}
+	return dirty
+}
+
+func (sb *SchedulingBackend) SetNextPinChange(next time.Time) {
+	sb.m.Lock()
+	defer sb.m.Unlock()
+	sb.next = next
+}
+
+func (sb *SchedulingBackend) SetDirtyPinChange(dirty time.Time) {
+	sb.m.Lock()
+	defer sb.m.Unlock()
+	sb.dirty = dirty
+}
+
+func (sb *SchedulingBackend) NextCallCount() int {
+	sb.m.Lock()
+	defer sb.m.Unlock()
+	return sb.nextCalls
+}
+
+var _ PinScheduler = &SchedulingBackend{}
+
+func TestManagePinsSchedules(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pb := &SchedulingBackend{NullBackend: NewNullBackend()}
+	pb.SetNextPinChange(time.Now().Add(50 * time.Millisecond))
+
+	go ManagePins(ctx, make(chan struct{}), pb, StaticNodes{"local": NewNullJuggler()}, time.Hour, 1, 0)
+
+	time.Sleep(10 * time.Millisecond)
+
+	if c := pb.PinsCallCount(); c != 1 {
+		t.Errorf("reqs call count should be 1: %d", c)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if c := pb.PinsCallCount(); c != 2 {
+		t.Errorf("the pin change should wake up a sweep, reqs call count should be 2: %d", c)
+	}
+
+	// a bump can bring the next pin change closer
+	pb.SetDirtyPinChange(time.Now().Add(30 * time.Millisecond))
+	go func(c chan<- struct{}) { c <- struct{}{} }(pb.Bumper)
+
+	time.Sleep(10 * time.Millisecond)
+
+	if c := pb.PinsCallCount(); c != 2 {
+		t.Errorf("bump should not do a full sweep, reqs call count should be 2: %d", c)
+	}
+	if c := pb.NextCallCount(); c != 2 {
+		t.Errorf("bump should not look at every pin's changes, next change call count should be 2: %d", c)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
 	if c := pb.PinsCallCount(); c != 3 {
-		t.Errorf("reqs call count should be 3: %d", c)
+		t.Errorf("the bumped pin change should wake up a sweep, reqs call count should be 3: %d", c)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if c := pb.PinsCallCount(); c != 3 {
+		t.Errorf("without pin changes coming, reqs call count should stay 3: %d", c)
+	}
+}
+
+type TrackingBackend struct {
+	*NullBackend
+	due      []string
+	released int
+	resolved map[string]Hash
+	failed   map[string]error
+}
+
+func NewTrackingBackend(due ...string) *TrackingBackend {
+	return &TrackingBackend{
+		NullBackend: NewNullBackend(),
+		due:         due,
+		resolved:    make(map[string]Hash),
+		failed:      make(map[string]error),
+	}
+}
+
+func (tb *TrackingBackend) DueNames(now time.Time) []string {
+	return tb.due
+}
+
+func (tb *TrackingBackend) NotifyName(name string, target Hash, err error, now time.Time) {
+	if err != nil {
+		tb.failed[name] = err
+		return
+	}
+	tb.resolved[name] = target
+}
+
+func (tb *TrackingBackend) ReleaseTargets(now time.Time) {
+	tb.released++
+}
+
+var _ PinNameTracker = &TrackingBackend{}
+
+// ResolvingNodes is a NodeSet resolving the names in its map, standing in for
+// the network.
+type ResolvingNodes struct {
+	StaticNodes
+	names map[string]Hash
+}
+
+var errNoSuchName = errors.New("no such name")
+
+func (rn ResolvingNodes) ResolveName(_ context.Context, name string) (Hash, error) {
+	h, ok := rn.names[name]
+	if !ok {
+		return "", errNoSuchName
+	}
+	return h, nil
+}
+
+var _ NameResolver = ResolvingNodes{}
+
+func TestSweepPinsResolvesNames(t *testing.T) {
+	ctx := context.Background()
+
+	pb := NewTrackingBackend("example.com", "missing.example.com")
+	ns := ResolvingNodes{
+		StaticNodes: StaticNodes{"local": NewNullJuggler()},
+		names:       map[string]Hash{"example.com": "h1"},
+	}
+
+	sweepPins(ctx, pb, ns, 1, 0)
+
+	if pb.released != 1 {
+		t.Errorf("a sweep should release the targets first, release count should be 1: %d", pb.released)
+	}
+	if h := pb.resolved["example.com"]; h != "h1" {
+		t.Errorf("expected example.com to resolve to h1, got %q", h)
+	}
+	if err := pb.failed["missing.example.com"]; err != errNoSuchName {
+		t.Errorf("expected missing.example.com to fail to resolve, got %v", err)
+	}
+
+	// without a resolver the names still get their turn
+	pb = NewTrackingBackend("example.com")
+
+	sweepPins(ctx, pb, StaticNodes{"local": NewNullJuggler()}, 1, 0)
+
+	if err := pb.failed["example.com"]; err != ErrNoResolver {
+		t.Errorf("expected example.com to fail without a resolver, got %v", err)
 	}
 }
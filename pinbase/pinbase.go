@@ -1,62 +1,521 @@
 package pinbase
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/ipfs/go-cid"
 	"github.com/pkg/errors"
 )
 
 type Hash string
 
+// ErrInvalidHash is the cause of the errors ParseHash returns for strings that
+// are not CIDs.
+var ErrInvalidHash = errors.New("invalid hash")
+
+// ParseHash checks that s is a CID and returns it in canonical form, as a
+// CIDv1 in base32, so that the different encodings of a CID make for the same
+// Hash.
+func ParseHash(s string) (Hash, error) {
+	c, err := cid.Decode(s)
+	if err != nil {
+		return "", errors.Wrapf(ErrInvalidHash, "%q: %v", s, err)
+	}
+
+	return Hash(cid.NewCidV1(c.Type(), c.Hash()).String()), nil
+}
+
+// Canonical returns h in the canonical form of ParseHash if it is a CID, and
+// unchanged otherwise.
+func (h Hash) Canonical() Hash {
+	c, err := ParseHash(string(h))
+	if err != nil {
+		return h
+	}
+
+	return c
+}
+
+// CanonicalName returns the IPNS name or DNSLink domain of a name pin in
+// canonical form. IPNS keys become CIDv1 in base32 like hashes do, those given
+// as the CIDv0 form of a peer ID included, and domains are kept as they are.
+func CanonicalName(name string) string {
+	c, err := cid.Decode(name)
+	if err != nil {
+		return name
+	}
+
+	if c.Version() == 0 {
+		return cid.NewCidV1(cid.Libp2pKey, c.Hash()).String()
+	}
+
+	return cid.NewCidV1(c.Type(), c.Hash()).String()
+}
+
 type PartyCreate struct {
 	ID          Hash
 	Description string
+	Quota       PartyQuota
+	PublicKey   ed25519.PublicKey
 }
 
 type PartyEdit struct {
 	Description string
+	Quota       PartyQuota
 }
 
 type PartyView struct {
 	ID          Hash
 	Description string
+	Quota       PartyQuota
+	Usage       PartyUsage
+	Grants      map[string]Role
+	PublicKey   ed25519.PublicKey
 }
 
 func (pv *PartyView) String() string {
 	return string(pv.ID) + ": " + pv.Description
 }
 
+// Headers carrying the signature requests to the pins of a party bound to a
+// public key need.
+const (
+	SignatureDateHeader = "X-Pinbase-Date"
+	SignatureHeader     = "X-Pinbase-Signature"
+)
+
+// SigningString is what gets signed for requests to a party bound to a public
+// key: the method, the request URI, the date header and the SHA-256 of the
+// body, one per line.
+func SigningString(method, uri, date string, body []byte) []byte {
+	sum := sha256.Sum256(body)
+	return []byte(strings.Join([]string{method, uri, date, hex.EncodeToString(sum[:])}, "\n"))
+}
+
+// PartyQuota limits the pins a party may want pinned. Zero means no limit.
+type PartyQuota struct {
+	MaxPins  int
+	MaxBytes int64
+}
+
+// PartyUsage is what a party's pins add up to. Pins and Bytes cover the
+// wanted pins, which is what quotas are checked against, while PinnedPins and
+// PinnedBytes cover the ones the nodes confirmed. Bytes only count the pins
+// whose size is known.
+type PartyUsage struct {
+	Pins        int
+	Bytes       int64
+	PinnedPins  int
+	PinnedBytes int64
+}
+
+// Role is what an API key may do with a party. Each role may do everything the
+// ones before it may. RoleAdmin is never granted on a party, it belongs to
+// admin keys, which may do anything with every party and create or delete
+// them.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleReadOnly
+	RolePartyOwner
+	RoleAdmin
+	numRoles
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleNone:
+		return "none"
+	case RoleReadOnly:
+		return "read-only"
+	case RolePartyOwner:
+		return "party-owner"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseRole(s string) (Role, error) {
+	for r := Role(0); r < numRoles; r++ {
+		if r.String() == s {
+			return r, nil
+		}
+	}
+
+	return 0, errors.Errorf("unknown role %q", s)
+}
+
+// ErrQuotaExceeded is the cause of the errors returned when a pin would take a
+// party over its quota.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// PinCreate and PinEdit carry the ID of the API key making the change in By,
+// which ends up in the pin's history. Pins stop being wanted once their
+// ExpiresAt passes, a zero one never expires. NotBefore and NotAfter limit the
+// pin to a window of time, see InPinWindow. The ID of a PinKindName pin is the
+// IPNS name or DNSLink domain it tracks. Pins of a path below a hash, as in
+// /ipfs/<hash>/sub/path, carry the hash the path resolved to as their ID and
+// the path itself in Path.
 type PinCreate struct {
-	ID         Hash
-	Aliases    []string
-	WantPinned bool
+	ID          Hash
+	Kind        PinKind
+	Path        string
+	Aliases     []string
+	WantPinned  bool
+	Mode        PinMode
+	Replication int
+	ExpiresAt   time.Time
+	NotBefore   time.Time
+	NotAfter    time.Time
+	By          string
 }
 
 type PinEdit struct {
-	Aliases    []string
-	WantPinned bool
+	Aliases     []string
+	WantPinned  bool
+	Mode        PinMode
+	Replication int
+	ExpiresAt   time.Time
+	NotBefore   time.Time
+	NotAfter    time.Time
+	By          string
 }
 
+// PinView describes a party's pin. Name pins carry the hash their name last
+// resolved to in Target, empty until it first resolves, and the latest
+// outcomes of resolving it in Resolutions, oldest first. Path is the path the
+// ID was resolved from, empty for pins created with a plain hash.
 type PinView struct {
-	ID         Hash
-	Aliases    []string
-	WantPinned bool
-	Status     PinStatus
-	LastError  error
+	ID          Hash
+	Kind        PinKind
+	Path        string
+	Aliases     []string
+	WantPinned  bool
+	Mode        PinMode
+	Replication int
+	Status      PinStatus
+	LastError   error
+	Progress    PinProgress
+	Nodes       map[string]NodePinState
+	Size        int64
+	ExpiresAt   time.Time
+	NotBefore   time.Time
+	NotAfter    time.Time
+	Target      Hash
+	Resolutions []*PinResolution
+}
+
+// Expired reports whether the pin's expiry has passed by now.
+func (pv *PinView) Expired(now time.Time) bool {
+	return !pv.ExpiresAt.IsZero() && !pv.ExpiresAt.After(now)
+}
+
+// InWindow reports whether now falls in the pin's window.
+func (pv *PinView) InWindow(now time.Time) bool {
+	return InPinWindow(pv.NotBefore, pv.NotAfter, now)
+}
+
+// InPinWindow reports whether now falls in the window from notBefore up to but
+// not including notAfter. A zero notBefore leaves the window open at the
+// start and a zero notAfter leaves it open at the end.
+func InPinWindow(notBefore, notAfter, now time.Time) bool {
+	if !notBefore.IsZero() && now.Before(notBefore) {
+		return false
+	}
+
+	return notAfter.IsZero() || now.Before(notAfter)
 }
 
 func (pv *PinView) String() string {
 	return fmt.Sprintf(
-		"%s: %s want(%t) %s %v",
+		"%s: %s want(%t) %s x%d %s %v %s %v",
 		pv.ID,
 		pv.Aliases,
 		pv.WantPinned,
+		pv.Mode,
+		pv.Replication,
 		pv.Status,
 		pv.LastError,
+		&pv.Progress,
+		pv.Nodes,
 	)
 }
 
+// PinKind tells what the ID of a pin is. PinKindHash pins pin their ID, while
+// PinKindName pins pin whatever their IPNS name or DNSLink domain resolves to,
+// following it as it changes.
+type PinKind int
+
+const (
+	PinKindHash PinKind = iota
+	PinKindName
+	numPinKinds
+)
+
+func (k PinKind) String() string {
+	switch k {
+	case PinKindHash:
+		return "hash"
+	case PinKindName:
+		return "name"
+	default:
+		return "unknown"
+	}
+}
+
+func ParsePinKind(s string) (PinKind, error) {
+	for k := PinKind(0); k < numPinKinds; k++ {
+		if k.String() == s {
+			return k, nil
+		}
+	}
+
+	return 0, errors.Errorf("unknown pin kind %q", s)
+}
+
+// PinResolution is the outcome of resolving the name of a name pin, either
+// the hash it pointed at or the error resolving it ran into.
+type PinResolution struct {
+	Time   time.Time
+	Target Hash
+	Error  string
+}
+
+func (pr *PinResolution) String() string {
+	if pr.Error != "" {
+		return fmt.Sprintf("%s error(%s)", pr.Time.Format(time.RFC3339), pr.Error)
+	}
+
+	return fmt.Sprintf("%s %s", pr.Time.Format(time.RFC3339), pr.Target)
+}
+
+// NodePinState is how a pin is doing on a single IPFS node.
+type NodePinState struct {
+	Status    PinStatus
+	LastError error
+}
+
+func (ns NodePinState) String() string {
+	if ns.LastError != nil {
+		return fmt.Sprintf("%s(%v)", ns.Status, ns.LastError)
+	}
+
+	return ns.Status.String()
+}
+
+// PinChange is what a pin history entry records.
+type PinChange int
+
+const (
+	PinChangeStatus PinChange = iota
+	PinChangeCreated
+	PinChangeUpdated
+	PinChangeReset
+	PinChangeDeleted
+	PinChangeExpired
+	PinChangeRenewed
+	PinChangeResolved
+)
+
+func (c PinChange) String() string {
+	switch c {
+	case PinChangeStatus:
+		return "status"
+	case PinChangeCreated:
+		return "created"
+	case PinChangeUpdated:
+		return "updated"
+	case PinChangeReset:
+		return "reset"
+	case PinChangeDeleted:
+		return "deleted"
+	case PinChangeExpired:
+		return "expired"
+	case PinChangeRenewed:
+		return "renewed"
+	case PinChangeResolved:
+		return "resolved"
+	default:
+		return "unknown"
+	}
+}
+
+// PinHistoryEntry is a change of a party's pin. Status is what the pin was
+// left with. The changes made through the API carry the ID of the API key that
+// made them in By, and edits list what they changed in Changes, as in
+// "want-pinned: true -> false". Status changes reported by the pin processor
+// carry no key.
+type PinHistoryEntry struct {
+	Time      time.Time
+	Change    PinChange
+	By        string
+	Status    PinStatus
+	LastError string
+	Changes   []string
+}
+
+func (he *PinHistoryEntry) String() string {
+	return fmt.Sprintf("%s %s %s by(%s) %q %v", he.Time.Format(time.RFC3339), he.Change, he.Status, he.By, he.LastError, he.Changes)
+}
+
+// PinMode tells whether pinning a hash should also pin everything it links
+// to, or only the root block.
+type PinMode int
+
+const (
+	PinRecursive PinMode = iota
+	PinDirect
+	numPinModes
+)
+
+func (m PinMode) String() string {
+	switch m {
+	case PinRecursive:
+		return "recursive"
+	case PinDirect:
+		return "direct"
+	default:
+		return "unknown"
+	}
+}
+
+func ParsePinMode(s string) (PinMode, error) {
+	for m := PinMode(0); m < numPinModes; m++ {
+		if m.String() == s {
+			return m, nil
+		}
+	}
+
+	return 0, errors.Errorf("unknown pin mode %q", s)
+}
+
+// PinRequirement is what the backend wants done with a hash. The Mode and
+// Replication only matter for wanted pins, and a Replication below 1 means a
+// single node. Sized tells ManagePins that the backend already knows how big
+// the pin is, so there is no need to look it up.
+type PinRequirement struct {
+	WantPinned  bool
+	Mode        PinMode
+	Replication int
+	Sized       bool
+}
+
+// Merge combines the requirements of two parties holding the same hash. The
+// hash is wanted if either wants it, recursively if either wants it that way,
+// and on as many nodes as the more demanding of the two asks for.
+func (pr PinRequirement) Merge(o PinRequirement) PinRequirement {
+	switch {
+	case !o.WantPinned:
+		return pr
+	case !pr.WantPinned:
+		return o
+	}
+
+	m := pr
+	if o.Mode == PinRecursive {
+		m.Mode = PinRecursive
+	}
+	if o.Replication > m.Replication {
+		m.Replication = o.Replication
+	}
+	m.Sized = pr.Sized && o.Sized
+
+	return m
+}
+
+func (pr PinRequirement) replicas() int {
+	if pr.Replication < 1 {
+		return 1
+	}
+
+	return pr.Replication
+}
+
+// PinProgress tells how much of a pin has been fetched so far. Jugglers that
+// do not know one of the numbers leave it at zero.
+type PinProgress struct {
+	Blocks int64
+	Bytes  int64
+}
+
+func (pp *PinProgress) String() string {
+	return fmt.Sprintf("blocks(%d) bytes(%d)", pp.Blocks, pp.Bytes)
+}
+
+// ArchivedPinView describes a hash that no party holds anymore but which has
+// not been confirmed as unpinned yet.
+type ArchivedPinView struct {
+	ID        Hash
+	Status    PinStatus
+	LastError error
+}
+
+func (av *ArchivedPinView) String() string {
+	return fmt.Sprintf("%s: %s %v", av.ID, av.Status, av.LastError)
+}
+
+type NodeCreate struct {
+	ID         string
+	APIAddress string
+}
+
+type NodeEdit struct {
+	APIAddress string
+}
+
+// NodeView is a registered IPFS node along with what the last check found out
+// about it. LastSeen is zero for nodes that were never reachable.
+type NodeView struct {
+	ID         string
+	APIAddress string
+	Reachable  bool
+	LastSeen   time.Time
+	PinCount   int
+	RepoSize   int64
+}
+
+func (nv *NodeView) String() string {
+	return fmt.Sprintf("%s: %s reachable(%t) pins(%d) repo(%d)", nv.ID, nv.APIAddress, nv.Reachable, nv.PinCount, nv.RepoSize)
+}
+
+type KeyCreate struct {
+	Description string
+	Admin       bool
+}
+
+// KeyView describes an API key. The key itself is not stored anywhere, so only
+// whoever created it knows it.
+type KeyView struct {
+	ID          string
+	Description string
+	Created     time.Time
+	Admin       bool
+}
+
+func (kv *KeyView) String() string {
+	return kv.ID + ": " + kv.Description
+}
+
+// NodeHealth is the outcome of checking on a node. PinCount and RepoSize are
+// only meaningful for reachable nodes.
+type NodeHealth struct {
+	Reachable bool
+	PinCount  int
+	RepoSize  int64
+}
+
 type PinStatus int
 
 const (
@@ -65,6 +524,7 @@ const (
 	PinUnpinned
 	PinError
 	PinFatal
+	PinPinning
 	numPinStatuses
 )
 
@@ -80,6 +540,8 @@ func (p PinStatus) String() string {
 		return "error"
 	case PinFatal:
 		return "fatal"
+	case PinPinning:
+		return "pinning"
 	default:
 		return "unknown"
 	}
@@ -89,6 +551,9 @@ type PinProvider interface {
 	PinService() PinService
 }
 
+// PinService keeps the IDs of pins that are CIDs in canonical form, see
+// Hash.Canonical and CanonicalName, and finds hash pins by any form of their
+// hash.
 type PinService interface {
 	Parties() ([]*PartyView, error)
 	Party(Hash) (*PartyView, error)
@@ -97,111 +562,826 @@ type PinService interface {
 	DeleteParty(Hash) error
 	UpdateParty(Hash, *PartyEdit) error
 
+	// GrantParty gives the key the role on the party, RoleNone takes away
+	// whatever it had.
+	GrantParty(partyID Hash, keyID string, r Role) error
+
 	Pins(partyID Hash) ([]*PinView, error)
 	Pin(partyID, pinID Hash) (*PinView, error)
 
+	// by is the ID of the API key deleting or resetting the pin, like the
+	// By of PinCreate and PinEdit.
 	CreatePin(partyID Hash, pc *PinCreate) error
-	DeletePin(partyID, pinID Hash) error
+	DeletePin(partyID, pinID Hash, by string) error
 	UpdatePin(partyID, pinID Hash, pe *PinEdit) error
+	ResetPin(partyID, pinID Hash, by string) error
+
+	// RenewPin wants the pin again until expiresAt, or for good if it is
+	// zero, checking the party's quota if it had expired.
+	RenewPin(partyID, pinID Hash, expiresAt time.Time, by string) error
+
+	// PinHistory lists the changes of the party's pin, oldest first. The
+	// history outlives the pin, ending with its deletion.
+	PinHistory(partyID, pinID Hash) ([]*PinHistoryEntry, error)
+
+	// PinParties lists the parties holding a pin for the hash, wanted or not.
+	PinParties(pinID Hash) ([]*PartyView, error)
+
+	ArchivedPins() ([]*ArchivedPinView, error)
+}
+
+type NodeProvider interface {
+	NodeService() NodeService
+}
+
+// NodeService keeps the registry of IPFS nodes to pin on. NotifyNode records
+// the outcome of a health check, keeping the previous numbers around when the
+// node could not be reached.
+type NodeService interface {
+	Nodes() ([]*NodeView, error)
+	Node(id string) (*NodeView, error)
+
+	CreateNode(*NodeCreate) error
+	DeleteNode(id string) error
+	UpdateNode(id string, ne *NodeEdit) error
+
+	NotifyNode(id string, h *NodeHealth) error
+}
+
+type KeyProvider interface {
+	KeyService() KeyService
 }
 
+// KeyService manages the API keys. CreateKey returns the new key along with
+// its view, and there is no way to get the key back later. Authenticate
+// returns the view of a valid key and nil for anything else.
+type KeyService interface {
+	Keys() ([]*KeyView, error)
+	Key(id string) (*KeyView, error)
+
+	CreateKey(*KeyCreate) (*KeyView, string, error)
+	DeleteKey(id string) error
+
+	Authenticate(key string) (*KeyView, error)
+}
+
+// PinBackend tells ManagePins what should be pinned. PinRequirements covers
+// every hash the backend knows about, while DirtyPinRequirements only covers
+// the hashes that changed since the last call to either of them.
 type PinBackend interface {
 	PinProcessorBump() <-chan struct{}
-	PinRequirements() map[Hash]bool
+	PinRequirements() map[Hash]PinRequirement
+	DirtyPinRequirements() map[Hash]PinRequirement
 	NotifyPin(pinID Hash, s *PinBackendState)
 }
 
+// PinExpirer is a PinBackend with pins that expire. ManagePins calls
+// ExpirePins before each full pass, which should stop wanting the pins whose
+// expiry is not after now.
+type PinExpirer interface {
+	PinBackend
+	ExpirePins(now time.Time)
+}
+
+// PinScheduler is a PinBackend with pins whose requirements change at set
+// times, such as the opening and closing of pin windows. ManagePins makes its
+// next full pass by the time NextPinChange returns, which is the first such
+// time after now, now itself if something is due already, or zero if there is
+// nothing coming. NextPinChange covers every pin and is only asked after a
+// full pass, while DirtyPinChange only covers the pins that changed since the
+// last call to either of them and is asked after each bump.
+type PinScheduler interface {
+	PinBackend
+	NextPinChange(now time.Time) time.Time
+	DirtyPinChange(now time.Time) time.Time
+}
+
+// PinNameTracker is a PinBackend with name pins. Before each full pass
+// ManagePins drops the previous targets of name pins whose grace period is
+// over with ReleaseTargets, then resolves the names DueNames lists and hands
+// the outcomes to NotifyName.
+type PinNameTracker interface {
+	PinBackend
+	DueNames(now time.Time) []string
+	NotifyName(name string, target Hash, err error, now time.Time)
+	ReleaseTargets(now time.Time)
+}
+
+// NameResolver finds the hash an IPNS name or DNSLink domain points at.
+type NameResolver interface {
+	ResolveName(ctx context.Context, name string) (Hash, error)
+}
+
+// ErrNoResolver is reported for the names of name pins when the NodeSet given
+// to ManagePins can not resolve names.
+var ErrNoResolver = errors.New("no name resolver available")
+
+// PathResolver finds the hash an IPFS path, as in /ipfs/<hash>/sub/path,
+// points at.
+type PathResolver interface {
+	ResolvePath(ctx context.Context, path string) (Hash, error)
+}
+
+// PinBackendState is what ManagePins found out about a pin. Nodes lists the
+// nodes that hold the pin or failed to handle it. Progress is only set for
+// PinPinning updates sent while the pin is still being fetched, and those
+// only carry the node doing the fetching. Size is the cumulative size of a
+// recursive pin or the root block size of a direct one, and is only set when
+// a node looked it up after pinning.
 type PinBackendState struct {
 	Status    PinStatus
 	LastError error
+	Progress  *PinProgress
+	Nodes     map[string]NodePinState
+	Size      int64
+}
+
+// RetryPolicy describes how long a backend should wait before retrying a
+// failed pin, and how many failed attempts it tolerates before marking the pin
+// as PinFatal.
+type RetryPolicy struct {
+	MinDelay    time.Duration
+	MaxDelay    time.Duration
+	MaxAttempts int
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MinDelay:    5 * time.Second,
+	MaxDelay:    1 * time.Hour,
+	MaxAttempts: 10,
+}
+
+// Delay returns how long to wait after the given number of failed attempts,
+// doubling from MinDelay up to MaxDelay.
+func (rp RetryPolicy) Delay(attempts int) time.Duration {
+	d := rp.MinDelay
+
+	for i := 1; i < attempts; i++ {
+		d *= 2
+
+		if d >= rp.MaxDelay {
+			return rp.MaxDelay
+		}
+	}
+
+	return d
+}
+
+// Fatal reports whether the given number of failed attempts is enough to give
+// up on a pin. A zero MaxAttempts retries forever.
+func (rp RetryPolicy) Fatal(attempts int) bool {
+	return rp.MaxAttempts > 0 && attempts >= rp.MaxAttempts
 }
 
+// ErrTimedOut is the cause recorded for pins that did not finish within the
+// pin timeout given to ManagePins.
+var ErrTimedOut = errors.New("timed out")
+
+// PinJuggler implementations must allow Pin and Unpin to be called
+// concurrently, as ManagePins works on several hashes at once, and should give
+// up as soon as their context is done.
 type PinJuggler interface {
-	Pin(Hash) error
-	Unpin(Hash) error
-	Pins() (map[Hash]struct{}, error)
+	Pin(context.Context, Hash, PinMode) error
+	Unpin(context.Context, Hash) error
+	Pins(context.Context) (map[Hash]PinMode, error)
+}
+
+// PinProgressJuggler is a PinJuggler that can tell how a pin is coming along.
+// ManagePins uses PinProgress instead of Pin when it is available, and the
+// progress callback may be called any number of times before it returns.
+type PinProgressJuggler interface {
+	PinJuggler
+	PinProgress(ctx context.Context, h Hash, m PinMode, progress func(PinProgress)) error
+}
+
+// PinSizer tells how many bytes pinning a hash in the given mode takes up.
+type PinSizer interface {
+	PinSize(ctx context.Context, h Hash, m PinMode) (int64, error)
+}
+
+// NodeSet hands ManagePins the IPFS nodes to spread pins over, keyed by name.
+// It is asked again for every round of pinning, so nodes can come and go.
+type NodeSet interface {
+	Nodes() map[string]PinJuggler
+}
+
+// StaticNodes is a NodeSet that never changes.
+type StaticNodes map[string]PinJuggler
+
+func (sn StaticNodes) Nodes() map[string]PinJuggler {
+	return sn
 }
 
+// ErrNoNodes is returned when there are no nodes to ask.
+var ErrNoNodes = errors.New("no nodes available")
+
+// progressInterval limits how often pin progress is passed on to the backend.
+var progressInterval = 5 * time.Second
+
+// ManagePins keeps the nodes in line with the backend requirements until
+// done is closed or ctx is cancelled. Bumps only reconcile the dirty hashes,
+// while a full sweep happens at least every maxInterval, or sooner when a
+// PinScheduler backend has a pin change coming up. Closing done lets the
+// pass in progress finish first, while cancelling ctx also cancels any Pin or
+// Unpin calls in flight. Each juggler call is given at most pinTimeout to
+// finish, a zero pinTimeout lets them run for as long as they need.
 func ManagePins(
+	ctx context.Context,
 	done <-chan struct{},
 	pb PinBackend,
-	pj PinJuggler,
+	ns NodeSet,
 	maxInterval time.Duration,
+	workers int,
+	pinTimeout time.Duration,
 ) {
-	processPins(pb, pj)
+	sweepPins(ctx, pb, ns, workers, pinTimeout)
 
-	t := time.NewTimer(maxInterval)
+	next := nextSweep(pb, time.Now(), maxInterval)
+	t := time.NewTimer(time.Until(next))
+	defer t.Stop()
 
 	for {
+		// a bump should not start another pass once we are done
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+
 		select {
 		case <-pb.PinProcessorBump():
-			processPins(pb, pj)
+			processPins(ctx, pb.DirtyPinRequirements(), pb, ns.Nodes(), workers, pinTimeout)
+
+			// a bump only covers part of the pins, so it should not
+			// push back the next full sweep, but it may have brought a
+			// pin change closer
+			if n := nextDirtyChange(pb, time.Now()); !n.IsZero() && n.Before(next) {
+				if !t.Stop() {
+					select {
+					case <-t.C:
+					default:
+					}
+				}
+				next = n
+				t.Reset(time.Until(next))
+			}
+			continue
 
 		case <-t.C:
-			processPins(pb, pj)
+			sweepPins(ctx, pb, ns, workers, pinTimeout)
 
 		case <-done:
 			return
+
+		case <-ctx.Done():
+			return
+		}
+
+		next = nextSweep(pb, time.Now(), maxInterval)
+		t.Reset(time.Until(next))
+	}
+}
+
+// nextSweep returns when the full sweep after one made at now is due.
+func nextSweep(pb PinBackend, now time.Time, maxInterval time.Duration) time.Time {
+	next := now.Add(maxInterval)
+
+	if sc, ok := pb.(PinScheduler); ok {
+		if c := sc.NextPinChange(now); !c.IsZero() && c.Before(next) {
+			next = c
+		}
+	}
+
+	return next
+}
+
+// nextDirtyChange returns when the pins changed since the last sweep or bump
+// next change by themselves, zero if they do not or pb is no PinScheduler.
+func nextDirtyChange(pb PinBackend, now time.Time) time.Time {
+	if sc, ok := pb.(PinScheduler); ok {
+		return sc.DirtyPinChange(now)
+	}
+
+	return time.Time{}
+}
+
+// sweepPins makes a full pass over the pins, expiring the ones that are due
+// and resolving the names of name pins first.
+func sweepPins(ctx context.Context, pb PinBackend, ns NodeSet, workers int, pinTimeout time.Duration) {
+	if pe, ok := pb.(PinExpirer); ok {
+		pe.ExpirePins(time.Now())
+	}
+
+	if nt, ok := pb.(PinNameTracker); ok {
+		nt.ReleaseTargets(time.Now())
+		resolveNames(ctx, nt, ns, pinTimeout)
+	}
+
+	processPins(ctx, pb.PinRequirements(), pb, ns.Nodes(), workers, pinTimeout)
+}
+
+// resolveNames resolves the names that are due one at a time, giving each at
+// most pinTimeout like the juggler calls.
+func resolveNames(ctx context.Context, nt PinNameTracker, ns NodeSet, pinTimeout time.Duration) {
+	nr, _ := ns.(NameResolver)
+
+	for _, name := range nt.DueNames(time.Now()) {
+		if ctx.Err() != nil {
+			return
 		}
 
-		if !t.Stop() {
-			<-t.C
+		var h Hash
+		err := ErrNoResolver
+
+		if nr != nil {
+			rctx, cancel := withPinTimeout(ctx, pinTimeout)
+			h, err = nr.ResolveName(rctx, name)
+			cancel()
+
+			if err != nil && ctx.Err() != nil {
+				// we are shutting down, this is nobody's fault
+				return
+			}
 		}
-		t.Reset(maxInterval)
+
+		nt.NotifyName(name, h, err, time.Now())
 	}
 }
 
-func processPins(pb PinBackend, pj PinJuggler) {
-	pr := pb.PinRequirements()
+// pinTask is a single Pin or Unpin call to make on a node. pr is what the node
+// should end up with, which is unwanted for nodes holding a replica too many.
+type pinTask struct {
+	h      Hash
+	node   string
+	pr     PinRequirement
+	mode   PinMode
+	pinned bool
+}
+
+// pinResult carries either the outcome of a pinTask, or a progress update if
+// progress is set. A nil nps means the task was cancelled. A zero size means
+// the size was not looked up.
+type pinResult struct {
+	h        Hash
+	node     string
+	nps      *NodePinState
+	progress *PinProgress
+	size     int64
+}
 
-	ps, err := pj.Pins()
+// processPins reconciles the requirements pr with the nodes using up to
+// workers concurrent Pin/Unpin calls. NotifyPin is only ever called from
+// the processPins goroutine itself, so backends do not need to worry about
+// concurrent notifications. If none of the nodes can say what they hold, the
+// pins are left alone for the next sweep to try again.
+func processPins(
+	ctx context.Context,
+	pr map[Hash]PinRequirement,
+	pb PinBackend,
+	nodes map[string]PinJuggler,
+	workers int,
+	pinTimeout time.Duration,
+) {
+	if len(pr) == 0 {
+		return
+	}
 
+	held, failed, err := nodePins(ctx, nodes, pinTimeout)
 	if err != nil {
-		for h, _ := range pr {
+		if ctx.Err() != nil {
+			// we are shutting down, this is nobody's fault
+			return
+		}
+
+		// none of the pins is to blame, so none of them should use up
+		// an attempt, the next sweep tries them all again
+		log.Printf("failed to get initial pins for %d hashes: %s", len(pr), err)
+		return
+	}
+
+	// what the failed nodes hold is anybody's guess until they answer again
+	partial := len(failed) > 0
+
+	tasks, settled := planPins(pr, held, partial)
+
+	for h, pbs := range settled {
+		pb.NotifyPin(h, pbs)
+	}
+
+	if len(tasks) == 0 {
+		return
+	}
+
+	pending := make(map[Hash]int)
+	for _, t := range tasks {
+		pending[t.h]++
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan pinTask)
+	results := make(chan pinResult)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for t := range jobs {
+				progress := progressReporter(t.h, t.node, results)
+
+				nps := reconcilePin(ctx, nodes[t.node], t.h, t.pr, t.mode, t.pinned, pinTimeout, progress)
+
+				var size int64
+				if nps != nil && nps.Status == PinPinned && !t.pr.Sized {
+					size = pinSize(ctx, nodes[t.node], t.h, t.pr.Mode, pinTimeout)
+				}
+
+				results <- pinResult{t.h, t.node, nps, nil, size}
+			}
+		}()
+	}
+
+	go func() {
+	feed:
+		for _, t := range tasks {
+			select {
+			case jobs <- t:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		close(jobs)
+
+		wg.Wait()
+		close(results)
+	}()
+
+	states := make(map[Hash]map[string]NodePinState)
+	sizes := make(map[Hash]int64)
+	cancelled := make(map[Hash]bool)
+
+	for r := range results {
+		if r.progress != nil {
 			pb.NotifyPin(
-				h,
+				r.h,
 				&PinBackendState{
-					Status:    PinError,
-					LastError: errors.Wrap(err, "get initial pins"),
+					Status:   PinPinning,
+					Progress: r.progress,
+					Nodes: map[string]NodePinState{
+						r.node: NodePinState{PinPinning, nil},
+					},
 				},
 			)
+
+			continue
 		}
 
-		return
+		if r.nps == nil {
+			cancelled[r.h] = true
+		} else {
+			if states[r.h] == nil {
+				states[r.h] = make(map[string]NodePinState)
+			}
+			states[r.h][r.node] = *r.nps
+		}
+
+		if r.size > 0 {
+			sizes[r.h] = r.size
+		}
+
+		pending[r.h]--
+		if pending[r.h] == 0 && !cancelled[r.h] {
+			pbs := settlePin(pr[r.h], states[r.h], partial)
+			pbs.Size = sizes[r.h]
+
+			pb.NotifyPin(r.h, pbs)
+		}
 	}
+}
 
-	for h, want := range pr {
-		_, pinned := ps[h]
+// nodePins finds out what every node has pinned. Nodes that cannot be reached
+// are left out and listed as failed, and it is only an error if none of them
+// can be reached.
+func nodePins(ctx context.Context, nodes map[string]PinJuggler, pinTimeout time.Duration) (map[string]map[Hash]PinMode, []string, error) {
+	if len(nodes) == 0 {
+		return nil, nil, ErrNoNodes
+	}
 
-		var pbs PinBackendState
+	held := make(map[string]map[Hash]PinMode)
 
-		switch {
-		case want && pinned:
-			pbs = PinBackendState{PinPinned, nil}
+	var failed []string
+	var firstErr error
 
-		case want && !pinned:
-			err = pj.Pin(h)
-			if err != nil {
-				pbs = PinBackendState{PinError, errors.Wrap(err, "pinning unpinned pin")}
-			} else {
-				pbs = PinBackendState{PinPinned, nil}
+	for _, name := range nodeNames(nodes) {
+		pctx, cancel := withPinTimeout(ctx, pinTimeout)
+		ps, err := nodes[name].Pins(pctx)
+		cancel()
+
+		if err != nil {
+			if firstErr == nil {
+				firstErr = pinError(pctx, err)
 			}
 
-		case !want && pinned:
-			err = pj.Unpin(h)
-			if err != nil {
-				pbs = PinBackendState{PinError, errors.Wrap(err, "unpinning pinned pin")}
+			log.Printf("failed to get pins from node %s: %s", name, err)
+			failed = append(failed, name)
+			continue
+		}
+
+		held[name] = ps
+	}
+
+	if len(held) == 0 {
+		return nil, failed, firstErr
+	}
+
+	return held, failed, nil
+}
+
+func nodeNames(nodes map[string]PinJuggler) []string {
+	names := make([]string, 0, len(nodes))
+	for name, _ := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// planPins works out which nodes should pin or unpin what. Replicas already in
+// place are kept, preferring the ones in the right mode, and new ones go to the
+// nodes holding the fewest pins. Hashes that need no calls at all are settled
+// right away. A partial held leaves out nodes that may hold any hash, so then
+// no new replicas are placed and unwanted hashes are not settled.
+func planPins(pr map[Hash]PinRequirement, held map[string]map[Hash]PinMode, partial bool) ([]pinTask, map[Hash]*PinBackendState) {
+	var names []string
+	load := make(map[string]int)
+	for name, ps := range held {
+		names = append(names, name)
+		load[name] = len(ps)
+	}
+	sort.Strings(names)
+
+	hashes := make([]Hash, 0, len(pr))
+	for h, _ := range pr {
+		hashes = append(hashes, h)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	var tasks []pinTask
+	settled := make(map[Hash]*PinBackendState)
+
+	for _, h := range hashes {
+		r := pr[h]
+
+		var holders, others []string
+		for _, name := range names {
+			if _, pinned := held[name][h]; pinned {
+				holders = append(holders, name)
 			} else {
-				pbs = PinBackendState{PinUnpinned, nil}
+				others = append(others, name)
 			}
+		}
 
-		case !want && !pinned:
-			pbs = PinBackendState{PinUnpinned, nil}
+		if !r.WantPinned {
+			if len(holders) == 0 && !partial {
+				settled[h] = &PinBackendState{Status: PinUnpinned}
+			}
 
-		default:
-			panic("somehow failed to account for the combinations of 2 booleans")
+			for _, name := range holders {
+				tasks = append(tasks, pinTask{h, name, r, held[name][h], true})
+			}
+
+			continue
+		}
+
+		sort.SliceStable(holders, func(i, j int) bool {
+			return held[holders[i]][h] == r.Mode && held[holders[j]][h] != r.Mode
+		})
+		sort.SliceStable(others, func(i, j int) bool {
+			return load[others[i]] < load[others[j]]
+		})
+
+		for i, name := range holders {
+			t := pinTask{h, name, r, held[name][h], true}
+			if i >= r.replicas() {
+				t.pr = PinRequirement{}
+				load[name]--
+			}
+
+			tasks = append(tasks, t)
+		}
+
+		if partial {
+			continue
 		}
 
-		pb.NotifyPin(h, &pbs)
+		for i := 0; i < r.replicas()-len(holders) && i < len(others); i++ {
+			name := others[i]
+			load[name]++
+
+			tasks = append(tasks, pinTask{h, name, r, 0, false})
+		}
+	}
+
+	return tasks, settled
+}
+
+// settlePin sums up how a hash is doing across the nodes. The first error in
+// node name order stands for the whole pin, and a wanted pin that could not
+// be placed on enough nodes is an error too. While partial, with some nodes
+// not saying what they hold, an unwanted pin or one on too few nodes stays
+// pending instead.
+func settlePin(pr PinRequirement, nodes map[string]NodePinState, partial bool) *PinBackendState {
+	var placed int
+	var firstErr error
+
+	pbs := &PinBackendState{
+		Nodes: make(map[string]NodePinState),
 	}
+
+	names := make([]string, 0, len(nodes))
+	for name, _ := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		nps := nodes[name]
+
+		if nps.Status == PinPinned {
+			placed++
+		}
+
+		if nps.LastError != nil && firstErr == nil {
+			firstErr = nps.LastError
+		}
+
+		// nodes that simply do not have the pin are not worth mentioning
+		if nps.Status != PinUnpinned {
+			pbs.Nodes[name] = nps
+		}
+	}
+
+	switch {
+	case !pr.WantPinned && firstErr == nil && partial:
+		pbs.Status = PinPending
+
+	case !pr.WantPinned && firstErr == nil:
+		pbs.Status = PinUnpinned
+
+	case !pr.WantPinned:
+		pbs.Status = PinError
+		pbs.LastError = firstErr
+
+	case placed >= pr.replicas():
+		pbs.Status = PinPinned
+
+	case firstErr != nil:
+		pbs.Status = PinError
+		pbs.LastError = firstErr
+
+	case partial:
+		pbs.Status = PinPending
+
+	default:
+		pbs.Status = PinError
+		pbs.LastError = errors.Errorf("only %d of %d replicas placed", placed, pr.replicas())
+	}
+
+	return pbs
+}
+
+// progressReporter passes PinPinning updates for h on node on to results,
+// dropping the ones that come in less than progressInterval after the last one.
+func progressReporter(h Hash, node string, results chan<- pinResult) func(PinProgress) {
+	var last time.Time
+
+	return func(p PinProgress) {
+		now := time.Now()
+		if now.Sub(last) < progressInterval {
+			return
+		}
+		last = now
+
+		results <- pinResult{h, node, nil, &p, 0}
+	}
+}
+
+func withPinTimeout(ctx context.Context, pinTimeout time.Duration) (context.Context, context.CancelFunc) {
+	if pinTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, pinTimeout)
+}
+
+// reconcilePin pins or unpins h on a single node as needed, returning the
+// state of the node, or nil if ctx was cancelled and there is nothing to
+// report. A hash pinned in the wrong mode is unpinned and pinned again.
+func reconcilePin(
+	ctx context.Context,
+	pj PinJuggler,
+	h Hash,
+	pr PinRequirement,
+	mode PinMode,
+	pinned bool,
+	pinTimeout time.Duration,
+	progress func(PinProgress),
+) *NodePinState {
+	pctx, cancel := withPinTimeout(ctx, pinTimeout)
+	defer cancel()
+
+	want := pr.WantPinned
+
+	var nps NodePinState
+
+	switch {
+	case want && pinned && mode == pr.Mode:
+		nps = NodePinState{PinPinned, nil}
+
+	case want && pinned:
+		err := pj.Unpin(pctx, h)
+		if err == nil {
+			err = pinWithProgress(pctx, pj, h, pr.Mode, progress)
+		}
+		if err != nil {
+			nps = NodePinState{PinError, errors.Wrapf(pinError(pctx, err), "repinning %s pin as %s", mode, pr.Mode)}
+		} else {
+			nps = NodePinState{PinPinned, nil}
+		}
+
+	case want && !pinned:
+		err := pinWithProgress(pctx, pj, h, pr.Mode, progress)
+		if err != nil {
+			nps = NodePinState{PinError, errors.Wrap(pinError(pctx, err), "pinning unpinned pin")}
+		} else {
+			nps = NodePinState{PinPinned, nil}
+		}
+
+	case !want && pinned:
+		err := pj.Unpin(pctx, h)
+		if err != nil {
+			nps = NodePinState{PinError, errors.Wrap(pinError(pctx, err), "unpinning pinned pin")}
+		} else {
+			nps = NodePinState{PinUnpinned, nil}
+		}
+
+	case !want && !pinned:
+		nps = NodePinState{PinUnpinned, nil}
+
+	default:
+		panic("somehow failed to account for the combinations of 2 booleans")
+	}
+
+	if nps.Status == PinError && ctx.Err() != nil {
+		return nil
+	}
+
+	return &nps
+}
+
+func pinWithProgress(ctx context.Context, pj PinJuggler, h Hash, m PinMode, progress func(PinProgress)) error {
+	if ppj, ok := pj.(PinProgressJuggler); ok {
+		return ppj.PinProgress(ctx, h, m, progress)
+	}
+
+	return pj.Pin(ctx, h, m)
+}
+
+// pinSize looks up the size of a freshly pinned hash on the node that pinned
+// it, if the node can tell. Failing to do so is not the pin's fault, so it is
+// only logged and the size is left for the next time around.
+func pinSize(ctx context.Context, pj PinJuggler, h Hash, m PinMode, pinTimeout time.Duration) int64 {
+	sizer, ok := pj.(PinSizer)
+	if !ok {
+		return 0
+	}
+
+	pctx, cancel := withPinTimeout(ctx, pinTimeout)
+	defer cancel()
+
+	size, err := sizer.PinSize(pctx, h, m)
+	if err != nil {
+		log.Printf("failed to size pin %s: %+v", h, err)
+		return 0
+	}
+
+	return size
+}
+
+// pinError replaces errors caused by the pin timeout with ErrTimedOut so that
+// they are easy to tell apart from the node refusing the pin.
+func pinError(pctx context.Context, err error) error {
+	if pctx.Err() == context.DeadlineExceeded {
+		return ErrTimedOut
+	}
+
+	return err
 }
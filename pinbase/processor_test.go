@@ -1,9 +1,12 @@
 package pinbase
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"reflect"
 
@@ -12,8 +15,12 @@ import (
 
 type MemoryBackendInfo struct {
 	WantPinned       bool
+	Mode             PinMode
+	Replication      int
 	Status           PinStatus
 	LastErrorMessage string
+	Progress         PinProgress
+	Size             int64
 }
 
 func (i *MemoryBackendInfo) String() string {
@@ -27,18 +34,25 @@ func (i *MemoryBackendInfo) String() string {
 
 type MemoryBackend struct {
 	Pins   map[Hash]*MemoryBackendInfo
+	Nodes  map[Hash]map[string]NodePinState
+	Dirty  map[Hash]struct{}
 	Bumper chan struct{}
+	m      *sync.Mutex
 }
 
 type MemoryJuggler struct {
-	P               map[Hash]struct{}
+	P               map[Hash]PinMode
 	PinsShouldError bool
+	m               *sync.Mutex
 }
 
 func NewMemoryBackend() *MemoryBackend {
 	return &MemoryBackend{
 		Pins:   make(map[Hash]*MemoryBackendInfo),
+		Nodes:  make(map[Hash]map[string]NodePinState),
+		Dirty:  make(map[Hash]struct{}),
 		Bumper: make(chan struct{}),
+		m:      &sync.Mutex{},
 	}
 }
 
@@ -46,17 +60,42 @@ func (mb *MemoryBackend) PinProcessorBump() <-chan struct{} {
 	return mb.Bumper
 }
 
-func (mb *MemoryBackend) PinRequirements() map[Hash]bool {
-	r := make(map[Hash]bool)
+func (mb *MemoryBackend) PinRequirements() map[Hash]PinRequirement {
+	mb.m.Lock()
+	defer mb.m.Unlock()
+
+	r := make(map[Hash]PinRequirement)
 
 	for h, i := range mb.Pins {
-		r[h] = i.WantPinned
+		r[h] = PinRequirement{i.WantPinned, i.Mode, i.Replication, i.Size > 0}
 	}
 
+	mb.Dirty = make(map[Hash]struct{})
+
+	return r
+}
+
+func (mb *MemoryBackend) DirtyPinRequirements() map[Hash]PinRequirement {
+	mb.m.Lock()
+	defer mb.m.Unlock()
+
+	r := make(map[Hash]PinRequirement)
+
+	for h, _ := range mb.Dirty {
+		if i, ok := mb.Pins[h]; ok {
+			r[h] = PinRequirement{i.WantPinned, i.Mode, i.Replication, i.Size > 0}
+		}
+	}
+
+	mb.Dirty = make(map[Hash]struct{})
+
 	return r
 }
 
 func (mb *MemoryBackend) NotifyPin(p Hash, s *PinBackendState) {
+	mb.m.Lock()
+	defer mb.m.Unlock()
+
 	_, ok := mb.Pins[p]
 	if ok {
 		mb.Pins[p].Status = s.Status
@@ -72,48 +111,87 @@ func (mb *MemoryBackend) NotifyPin(p Hash, s *PinBackendState) {
 	} else {
 		mb.Pins[p].LastErrorMessage = ""
 	}
+
+	if s.Progress != nil {
+		mb.Pins[p].Progress = *s.Progress
+	}
+
+	if s.Nodes != nil && s.Status != PinPinning {
+		mb.Nodes[p] = s.Nodes
+	}
+
+	if s.Size > 0 {
+		mb.Pins[p].Size = s.Size
+	}
+}
+
+func (mb *MemoryBackend) Status(p Hash) PinStatus {
+	mb.m.Lock()
+	defer mb.m.Unlock()
+
+	i, ok := mb.Pins[p]
+	if !ok {
+		return PinPending
+	}
+
+	return i.Status
 }
 
 var _ PinBackend = &MemoryBackend{}
 
 func NewMemoryJuggler() *MemoryJuggler {
 	return &MemoryJuggler{
-		P:               make(map[Hash]struct{}),
+		P:               make(map[Hash]PinMode),
 		PinsShouldError: false,
+		m:               &sync.Mutex{},
 	}
 }
 
-func (mj *MemoryJuggler) Pin(h Hash) error {
+func (mj *MemoryJuggler) Pin(_ context.Context, h Hash, m PinMode) error {
 	if strings.HasPrefix(string(h), "bad") {
 		return errors.New("cannot pin bad hash")
 	}
 
-	mj.P[h] = struct{}{}
+	mj.m.Lock()
+	defer mj.m.Unlock()
+
+	mj.P[h] = m
 	return nil
 }
 
-func (mj *MemoryJuggler) Unpin(h Hash) error {
+func (mj *MemoryJuggler) Unpin(_ context.Context, h Hash) error {
 	if strings.HasPrefix(string(h), "bad") {
 		return errors.New("cannot unpin bad hash")
 	}
 
+	mj.m.Lock()
+	defer mj.m.Unlock()
+
 	delete(mj.P, h)
 	return nil
 }
 
-func (mj *MemoryJuggler) Pins() (map[Hash]struct{}, error) {
+func (mj *MemoryJuggler) Pins(_ context.Context) (map[Hash]PinMode, error) {
 	if mj.PinsShouldError {
 		return nil, errors.New("can't get no pins")
 	}
 
-	return mj.P, nil
+	mj.m.Lock()
+	defer mj.m.Unlock()
+
+	p := make(map[Hash]PinMode)
+	for h, m := range mj.P {
+		p[h] = m
+	}
+
+	return p, nil
 }
 
 var _ PinJuggler = &MemoryJuggler{}
 
 func TestProcessPins(t *testing.T) {
 	pj := NewMemoryJuggler()
-	pj.P[Hash("old")] = struct{}{}
+	pj.P[Hash("old")] = PinRecursive
 
 	pb := NewMemoryBackend()
 	pb.Pins[Hash("wanted")] = &MemoryBackendInfo{
@@ -134,7 +212,7 @@ func TestProcessPins(t *testing.T) {
 		LastErrorMessage: "",
 	}
 
-	processPins(pb, pj)
+	processPins(context.Background(), pb.PinRequirements(), pb, StaticNodes{"local": pj}, 2, 0)
 
 	if !reflect.DeepEqual(
 		pb.Pins,
@@ -161,9 +239,9 @@ func TestProcessPins(t *testing.T) {
 
 	if !reflect.DeepEqual(
 		pj.P,
-		map[Hash]struct{}{
-			Hash("old"):    struct{}{},
-			Hash("wanted"): struct{}{},
+		map[Hash]PinMode{
+			Hash("old"):    PinRecursive,
+			Hash("wanted"): PinRecursive,
 		},
 	) {
 		t.Errorf("pin storage state incorrect: %+v", pj.P)
@@ -174,25 +252,26 @@ func TestProcessPins(t *testing.T) {
 
 	pj.PinsShouldError = true
 
-	processPins(pb, pj)
+	processPins(context.Background(), pb.PinRequirements(), pb, StaticNodes{"local": pj}, 2, 0)
 
+	// the pins are not to blame for the node, so they are left alone
 	if !reflect.DeepEqual(
 		pb.Pins,
 		map[Hash]*MemoryBackendInfo{
 			Hash("wanted"): &MemoryBackendInfo{
 				WantPinned:       false,
-				Status:           PinError,
-				LastErrorMessage: "get initial pins: can't get no pins",
+				Status:           PinPinned,
+				LastErrorMessage: "",
 			},
 			Hash("notwanted"): &MemoryBackendInfo{
 				WantPinned:       false,
-				Status:           PinError,
-				LastErrorMessage: "get initial pins: can't get no pins",
+				Status:           PinUnpinned,
+				LastErrorMessage: "",
 			},
 			Hash("badjunk"): &MemoryBackendInfo{
 				WantPinned:       true,
 				Status:           PinError,
-				LastErrorMessage: "get initial pins: can't get no pins",
+				LastErrorMessage: "pinning unpinned pin: cannot pin bad hash",
 			},
 		},
 	) {
@@ -201,9 +280,9 @@ func TestProcessPins(t *testing.T) {
 
 	if !reflect.DeepEqual(
 		pj.P,
-		map[Hash]struct{}{
-			Hash("old"):    struct{}{},
-			Hash("wanted"): struct{}{},
+		map[Hash]PinMode{
+			Hash("old"):    PinRecursive,
+			Hash("wanted"): PinRecursive,
 		},
 	) {
 		t.Errorf("pin storage state incorrect: %+v", pj.P)
@@ -211,7 +290,7 @@ func TestProcessPins(t *testing.T) {
 
 	pj.PinsShouldError = false
 
-	processPins(pb, pj)
+	processPins(context.Background(), pb.PinRequirements(), pb, StaticNodes{"local": pj}, 2, 0)
 
 	if !reflect.DeepEqual(
 		pb.Pins,
@@ -238,10 +317,611 @@ func TestProcessPins(t *testing.T) {
 
 	if !reflect.DeepEqual(
 		pj.P,
-		map[Hash]struct{}{
-			Hash("old"): struct{}{},
+		map[Hash]PinMode{
+			Hash("old"): PinRecursive,
 		},
 	) {
 		t.Errorf("pin storage state incorrect: %+v", pj.P)
 	}
 }
+
+// SlowJuggler blocks pinning of any hash starting with "slow" until Release is
+// closed or the context is done, announcing each blocked hash on Started first.
+type SlowJuggler struct {
+	*MemoryJuggler
+	Started chan Hash
+	Release chan struct{}
+}
+
+func NewSlowJuggler() *SlowJuggler {
+	return &SlowJuggler{
+		MemoryJuggler: NewMemoryJuggler(),
+		Started:       make(chan Hash),
+		Release:       make(chan struct{}),
+	}
+}
+
+func (sj *SlowJuggler) Pin(ctx context.Context, h Hash, m PinMode) error {
+	if strings.HasPrefix(string(h), "slow") {
+		sj.Started <- h
+
+		select {
+		case <-sj.Release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return sj.MemoryJuggler.Pin(ctx, h, m)
+}
+
+var _ PinJuggler = &SlowJuggler{}
+
+func TestProcessPinsConcurrently(t *testing.T) {
+	pj := NewSlowJuggler()
+
+	pb := NewMemoryBackend()
+	for _, h := range []Hash{"slow1", "slow2", "fast1", "fast2", "fast3"} {
+		pb.Pins[h] = &MemoryBackendInfo{
+			WantPinned:       true,
+			Status:           PinPending,
+			LastErrorMessage: "",
+		}
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		processPins(context.Background(), pb.PinRequirements(), pb, StaticNodes{"local": pj}, 3, 0)
+		close(finished)
+	}()
+
+	// both slow pins should be in flight at the same time
+	started := make(map[Hash]bool)
+	for len(started) < 2 {
+		select {
+		case h := <-pj.Started:
+			started[h] = true
+
+		case <-time.After(1 * time.Second):
+			t.Fatalf("slow pins did not start in parallel: %+v", started)
+		}
+	}
+
+	// and the remaining worker should get through the fast pins meanwhile
+	deadline := time.After(1 * time.Second)
+	for _, h := range []Hash{"fast1", "fast2", "fast3"} {
+		for pb.Status(h) != PinPinned {
+			select {
+			case <-deadline:
+				t.Fatalf("fast pin %s stuck behind slow pins: %s", h, pb.Status(h))
+
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}
+
+	for _, h := range []Hash{"slow1", "slow2"} {
+		if s := pb.Status(h); s != PinPending {
+			t.Errorf("slow pin %s finished before release: %s", h, s)
+		}
+	}
+
+	close(pj.Release)
+
+	select {
+	case <-finished:
+	case <-time.After(1 * time.Second):
+		t.Fatal("processPins did not finish after release")
+	}
+
+	for _, h := range []Hash{"slow1", "slow2", "fast1", "fast2", "fast3"} {
+		if s := pb.Status(h); s != PinPinned {
+			t.Errorf("pin %s not pinned: %s", h, s)
+		}
+	}
+}
+
+func TestProcessPinsTimeout(t *testing.T) {
+	pj := NewSlowJuggler()
+
+	pb := NewMemoryBackend()
+	for _, h := range []Hash{"slow", "fast"} {
+		pb.Pins[h] = &MemoryBackendInfo{
+			WantPinned:       true,
+			Status:           PinPending,
+			LastErrorMessage: "",
+		}
+	}
+
+	go func() { <-pj.Started }()
+
+	processPins(context.Background(), pb.PinRequirements(), pb, StaticNodes{"local": pj}, 2, 20*time.Millisecond)
+
+	if !reflect.DeepEqual(
+		pb.Pins,
+		map[Hash]*MemoryBackendInfo{
+			Hash("slow"): &MemoryBackendInfo{
+				WantPinned:       true,
+				Status:           PinError,
+				LastErrorMessage: "pinning unpinned pin: timed out",
+			},
+			Hash("fast"): &MemoryBackendInfo{
+				WantPinned:       true,
+				Status:           PinPinned,
+				LastErrorMessage: "",
+			},
+		},
+	) {
+		t.Errorf("pin backend state incorrect: %+v", pb.Pins)
+	}
+}
+
+func TestProcessPinsCancel(t *testing.T) {
+	pj := NewSlowJuggler()
+
+	pb := NewMemoryBackend()
+	pb.Pins[Hash("slow")] = &MemoryBackendInfo{
+		WantPinned:       true,
+		Status:           PinPending,
+		LastErrorMessage: "",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	finished := make(chan struct{})
+	go func() {
+		ManagePins(ctx, make(chan struct{}), pb, StaticNodes{"local": pj}, time.Minute, 1, 0)
+		close(finished)
+	}()
+
+	select {
+	case <-pj.Started:
+	case <-time.After(1 * time.Second):
+		t.Fatal("slow pin did not start")
+	}
+
+	cancel()
+
+	select {
+	case <-finished:
+	case <-time.After(1 * time.Second):
+		t.Fatal("cancelling the context did not interrupt the slow pin")
+	}
+
+	if s := pb.Status(Hash("slow")); s != PinPending {
+		t.Errorf("cancelled pin should not have been reported: %s", s)
+	}
+}
+
+func TestProcessPinsDone(t *testing.T) {
+	pj := NewSlowJuggler()
+
+	pb := NewMemoryBackend()
+	pb.Pins[Hash("slow")] = &MemoryBackendInfo{
+		WantPinned:       true,
+		Status:           PinPending,
+		LastErrorMessage: "",
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		ManagePins(context.Background(), done, pb, StaticNodes{"local": pj}, time.Minute, 1, 0)
+		close(finished)
+	}()
+
+	select {
+	case <-pj.Started:
+	case <-time.After(1 * time.Second):
+		t.Fatal("slow pin did not start")
+	}
+
+	close(done)
+
+	select {
+	case <-finished:
+		t.Fatal("closing done did not wait for the slow pin")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(pj.Release)
+
+	select {
+	case <-finished:
+	case <-time.After(1 * time.Second):
+		t.Fatal("did not stop after the slow pin finished")
+	}
+
+	if s := pb.Status(Hash("slow")); s != PinPinned {
+		t.Errorf("slow pin should have been pinned: %s", s)
+	}
+}
+
+func TestProcessPinsDirty(t *testing.T) {
+	pj := NewMemoryJuggler()
+
+	pb := NewMemoryBackend()
+	pb.Pins[Hash("clean")] = &MemoryBackendInfo{
+		WantPinned:       true,
+		Status:           PinPending,
+		LastErrorMessage: "",
+	}
+	pb.Pins[Hash("dirty")] = &MemoryBackendInfo{
+		WantPinned:       true,
+		Status:           PinPending,
+		LastErrorMessage: "",
+	}
+	pb.Dirty[Hash("dirty")] = struct{}{}
+
+	processPins(context.Background(), pb.DirtyPinRequirements(), pb, StaticNodes{"local": pj}, 2, 0)
+
+	if s := pb.Status(Hash("dirty")); s != PinPinned {
+		t.Errorf("dirty pin should have been pinned: %s", s)
+	}
+
+	if s := pb.Status(Hash("clean")); s != PinPending {
+		t.Errorf("clean pin should have been left alone: %s", s)
+	}
+
+	if len(pb.Dirty) != 0 {
+		t.Errorf("dirty set should have been cleared: %+v", pb.Dirty)
+	}
+
+	processPins(context.Background(), pb.PinRequirements(), pb, StaticNodes{"local": pj}, 2, 0)
+
+	if s := pb.Status(Hash("clean")); s != PinPinned {
+		t.Errorf("full sweep should have pinned the clean pin: %s", s)
+	}
+}
+
+// ProgressJuggler reports three steps of progress for every hash it pins.
+type ProgressJuggler struct {
+	*MemoryJuggler
+}
+
+func NewProgressJuggler() *ProgressJuggler {
+	return &ProgressJuggler{
+		MemoryJuggler: NewMemoryJuggler(),
+	}
+}
+
+func (pj *ProgressJuggler) PinProgress(ctx context.Context, h Hash, m PinMode, progress func(PinProgress)) error {
+	for i := int64(1); i <= 3; i++ {
+		progress(PinProgress{Blocks: i, Bytes: i * 100})
+	}
+
+	return pj.MemoryJuggler.Pin(ctx, h, m)
+}
+
+var _ PinProgressJuggler = &ProgressJuggler{}
+
+func TestProcessPinsProgress(t *testing.T) {
+	defer func(d time.Duration) { progressInterval = d }(progressInterval)
+
+	for _, tc := range []struct {
+		interval time.Duration
+		progress PinProgress
+	}{
+		{0, PinProgress{Blocks: 3, Bytes: 300}},
+		{time.Hour, PinProgress{Blocks: 1, Bytes: 100}},
+	} {
+		progressInterval = tc.interval
+
+		pj := NewProgressJuggler()
+
+		pb := NewMemoryBackend()
+		pb.Pins[Hash("big")] = &MemoryBackendInfo{
+			WantPinned:       true,
+			Status:           PinPending,
+			LastErrorMessage: "",
+		}
+
+		processPins(context.Background(), pb.PinRequirements(), pb, StaticNodes{"local": pj}, 1, 0)
+
+		if !reflect.DeepEqual(
+			pb.Pins,
+			map[Hash]*MemoryBackendInfo{
+				Hash("big"): &MemoryBackendInfo{
+					WantPinned:       true,
+					Status:           PinPinned,
+					LastErrorMessage: "",
+					Progress:         tc.progress,
+				},
+			},
+		) {
+			t.Errorf("interval %s: pin backend state incorrect: %+v", tc.interval, pb.Pins)
+		}
+	}
+}
+
+func TestProcessPinsModes(t *testing.T) {
+	pj := NewMemoryJuggler()
+	pj.P[Hash("tooshallow")] = PinDirect
+	pj.P[Hash("toodeep")] = PinRecursive
+	pj.P[Hash("justright")] = PinDirect
+
+	pb := NewMemoryBackend()
+	pb.Pins[Hash("tooshallow")] = &MemoryBackendInfo{
+		WantPinned: true,
+		Mode:       PinRecursive,
+		Status:     PinPending,
+	}
+	pb.Pins[Hash("toodeep")] = &MemoryBackendInfo{
+		WantPinned: true,
+		Mode:       PinDirect,
+		Status:     PinPending,
+	}
+	pb.Pins[Hash("justright")] = &MemoryBackendInfo{
+		WantPinned: true,
+		Mode:       PinDirect,
+		Status:     PinPending,
+	}
+	pb.Pins[Hash("new")] = &MemoryBackendInfo{
+		WantPinned: true,
+		Mode:       PinDirect,
+		Status:     PinPending,
+	}
+
+	processPins(context.Background(), pb.PinRequirements(), pb, StaticNodes{"local": pj}, 2, 0)
+
+	if !reflect.DeepEqual(
+		pj.P,
+		map[Hash]PinMode{
+			Hash("tooshallow"): PinRecursive,
+			Hash("toodeep"):    PinDirect,
+			Hash("justright"):  PinDirect,
+			Hash("new"):        PinDirect,
+		},
+	) {
+		t.Errorf("pin storage state incorrect: %+v", pj.P)
+	}
+
+	for h, _ := range pb.Pins {
+		if s := pb.Status(h); s != PinPinned {
+			t.Errorf("%s should be pinned: %s", h, s)
+		}
+	}
+}
+
+// SizingJuggler is a MemoryJuggler that knows the size of every hash but the
+// unsizable ones, counting how often it is asked.
+type SizingJuggler struct {
+	*MemoryJuggler
+	Asked map[Hash]int
+}
+
+func (sj *SizingJuggler) PinSize(_ context.Context, h Hash, m PinMode) (int64, error) {
+	sj.m.Lock()
+	defer sj.m.Unlock()
+
+	sj.Asked[h]++
+
+	if strings.HasPrefix(string(h), "unsizable") {
+		return 0, errors.New("cannot size bad hash")
+	}
+
+	if m == PinDirect {
+		return 1, nil
+	}
+
+	return int64(len(h)), nil
+}
+
+var _ PinSizer = &SizingJuggler{}
+
+func TestProcessPinsSizes(t *testing.T) {
+	pj := &SizingJuggler{NewMemoryJuggler(), make(map[Hash]int)}
+	pj.P[Hash("held")] = PinRecursive
+	pj.P[Hash("sized")] = PinRecursive
+
+	pb := NewMemoryBackend()
+	pb.Pins[Hash("held")] = &MemoryBackendInfo{WantPinned: true}
+	pb.Pins[Hash("sized")] = &MemoryBackendInfo{WantPinned: true, Size: 1000}
+	pb.Pins[Hash("new")] = &MemoryBackendInfo{WantPinned: true, Mode: PinDirect}
+	pb.Pins[Hash("unsizable")] = &MemoryBackendInfo{WantPinned: true}
+	pb.Pins[Hash("unwanted")] = &MemoryBackendInfo{WantPinned: false}
+
+	processPins(context.Background(), pb.PinRequirements(), pb, StaticNodes{"local": pj}, 2, 0)
+
+	for h, expected := range map[Hash]int64{
+		"held":      4,
+		"sized":     1000,
+		"new":       1,
+		"unsizable": 0,
+		"unwanted":  0,
+	} {
+		if s := pb.Pins[h].Size; s != expected {
+			t.Errorf("%s should have size %d: %d", h, expected, s)
+		}
+	}
+
+	if s := pb.Status("unsizable"); s != PinPinned {
+		t.Errorf("failing to size should not fail the pin: %s", s)
+	}
+
+	expectedAsked := map[Hash]int{"held": 1, "new": 1, "unsizable": 1}
+	if !reflect.DeepEqual(pj.Asked, expectedAsked) {
+		t.Errorf("got sizes asked %v, expected %v", pj.Asked, expectedAsked)
+	}
+
+	processPins(context.Background(), pb.PinRequirements(), pb, StaticNodes{"local": pj}, 2, 0)
+
+	expectedAsked = map[Hash]int{"held": 1, "new": 1, "unsizable": 2}
+	if !reflect.DeepEqual(pj.Asked, expectedAsked) {
+		t.Errorf("got sizes asked %v after another round, expected %v", pj.Asked, expectedAsked)
+	}
+}
+
+func TestPinRequirementMerge(t *testing.T) {
+	unwanted := PinRequirement{false, PinDirect, 3, false}
+	direct := PinRequirement{true, PinDirect, 2, false}
+	recursive := PinRequirement{true, PinRecursive, 1, true}
+
+	for _, tc := range []struct {
+		a, b, expected PinRequirement
+	}{
+		{unwanted, unwanted, unwanted},
+		{unwanted, direct, direct},
+		{direct, unwanted, direct},
+		{direct, direct, direct},
+		{direct, recursive, PinRequirement{true, PinRecursive, 2, false}},
+		{recursive, direct, PinRequirement{true, PinRecursive, 2, false}},
+		{recursive, recursive, recursive},
+		{unwanted, recursive, recursive},
+		{PinRequirement{}, unwanted, PinRequirement{}},
+	} {
+		if r := tc.a.Merge(tc.b); r != tc.expected {
+			t.Errorf("%+v merged with %+v: got %+v, expected %+v", tc.a, tc.b, r, tc.expected)
+		}
+	}
+}
+
+func TestProcessPinsReplication(t *testing.T) {
+	a := NewMemoryJuggler()
+	a.P[Hash("shrink")] = PinRecursive
+
+	b := NewMemoryJuggler()
+	b.P[Hash("shrink")] = PinRecursive
+
+	c := NewMemoryJuggler()
+	c.P[Hash("gone")] = PinRecursive
+	c.P[Hash("other")] = PinRecursive
+
+	pb := NewMemoryBackend()
+	pb.Pins[Hash("gone")] = &MemoryBackendInfo{
+		WantPinned: false,
+		Status:     PinPending,
+	}
+	pb.Pins[Hash("shrink")] = &MemoryBackendInfo{
+		WantPinned:  true,
+		Replication: 1,
+		Status:      PinPending,
+	}
+	pb.Pins[Hash("toomany")] = &MemoryBackendInfo{
+		WantPinned:  true,
+		Replication: 5,
+		Status:      PinPending,
+	}
+	pb.Pins[Hash("two")] = &MemoryBackendInfo{
+		WantPinned:  true,
+		Replication: 2,
+		Status:      PinPending,
+	}
+
+	nodes := StaticNodes{
+		"a": a,
+		"b": b,
+		"c": c,
+	}
+
+	processPins(context.Background(), pb.PinRequirements(), pb, nodes, 2, 0)
+
+	for name, expected := range map[string]map[Hash]PinMode{
+		"a": {"shrink": PinRecursive, "toomany": PinRecursive, "two": PinRecursive},
+		"b": {"toomany": PinRecursive, "two": PinRecursive},
+		"c": {"other": PinRecursive, "toomany": PinRecursive},
+	} {
+		if !reflect.DeepEqual(nodes[name].(*MemoryJuggler).P, expected) {
+			t.Errorf("node %s pin storage state incorrect: %+v", name, nodes[name].(*MemoryJuggler).P)
+		}
+	}
+
+	pinned := NodePinState{PinPinned, nil}
+
+	for h, expected := range map[Hash]struct {
+		status PinStatus
+		err    string
+		nodes  map[string]NodePinState
+	}{
+		"gone":    {PinUnpinned, "", map[string]NodePinState{}},
+		"shrink":  {PinPinned, "", map[string]NodePinState{"a": pinned}},
+		"toomany": {PinError, "only 3 of 5 replicas placed", map[string]NodePinState{"a": pinned, "b": pinned, "c": pinned}},
+		"two":     {PinPinned, "", map[string]NodePinState{"a": pinned, "b": pinned}},
+	} {
+		i := pb.Pins[h]
+		if i.Status != expected.status || i.LastErrorMessage != expected.err {
+			t.Errorf("%s: got %s %q, expected %s %q", h, i.Status, i.LastErrorMessage, expected.status, expected.err)
+		}
+
+		if !reflect.DeepEqual(pb.Nodes[h], expected.nodes) {
+			t.Errorf("%s: got nodes %+v, expected %+v", h, pb.Nodes[h], expected.nodes)
+		}
+	}
+}
+
+func TestProcessPinsNodeDown(t *testing.T) {
+	up := NewMemoryJuggler()
+	up.P[Hash("kept")] = PinRecursive
+	up.P[Hash("dropped")] = PinRecursive
+	up.P[Hash("short")] = PinRecursive
+
+	down := NewMemoryJuggler()
+	down.P[Hash("gone")] = PinRecursive
+	down.P[Hash("short")] = PinRecursive
+	down.PinsShouldError = true
+
+	pb := NewMemoryBackend()
+	for h, r := range map[Hash]int{"kept": 1, "short": 2, "new": 1} {
+		pb.Pins[h] = &MemoryBackendInfo{WantPinned: true, Replication: r, Status: PinPending}
+	}
+	for _, h := range []Hash{"dropped", "gone"} {
+		pb.Pins[h] = &MemoryBackendInfo{WantPinned: false, Status: PinPending}
+	}
+
+	nodes := StaticNodes{"up": up, "down": down}
+
+	check := func(tag string, expected map[Hash]PinStatus) {
+		for h, status := range expected {
+			if i := pb.Pins[h]; i.Status != status || i.LastErrorMessage != "" {
+				t.Errorf("%s: %s: got %s %q, expected %s", tag, h, i.Status, i.LastErrorMessage, status)
+			}
+		}
+	}
+
+	processPins(context.Background(), pb.PinRequirements(), pb, nodes, 2, 0)
+
+	// the down node may hold any of the hashes, so nothing is settled on
+	// its account and no replicas are added in its absence
+	check("node down", map[Hash]PinStatus{
+		"kept":    PinPinned,
+		"short":   PinPending,
+		"new":     PinPending,
+		"dropped": PinPending,
+		"gone":    PinPending,
+	})
+
+	if expected := map[Hash]PinMode{"kept": PinRecursive, "short": PinRecursive}; !reflect.DeepEqual(up.P, expected) {
+		t.Errorf("node down: got pins %+v, expected %+v", up.P, expected)
+	}
+
+	down.PinsShouldError = false
+
+	processPins(context.Background(), pb.PinRequirements(), pb, nodes, 2, 0)
+
+	check("node back", map[Hash]PinStatus{
+		"kept":    PinPinned,
+		"short":   PinPinned,
+		"new":     PinPinned,
+		"dropped": PinUnpinned,
+		"gone":    PinUnpinned,
+	})
+
+	if _, ok := down.P[Hash("gone")]; ok {
+		t.Errorf("node back: unwanted hash left on the node: %+v", down.P)
+	}
+}
+
+func TestProcessPinsNoNodes(t *testing.T) {
+	pb := NewMemoryBackend()
+	pb.Pins[Hash("wanted")] = &MemoryBackendInfo{
+		WantPinned: true,
+		Status:     PinPending,
+	}
+
+	processPins(context.Background(), pb.PinRequirements(), pb, StaticNodes{}, 2, 0)
+
+	if i := pb.Pins[Hash("wanted")]; i.Status != PinPending || i.LastErrorMessage != "" {
+		t.Errorf("pin backend state incorrect: %s", i)
+	}
+}
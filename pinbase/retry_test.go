@@ -0,0 +1,44 @@
+package pinbase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	rp := RetryPolicy{
+		MinDelay:    1 * time.Second,
+		MaxDelay:    10 * time.Second,
+		MaxAttempts: 4,
+	}
+
+	for attempts, expected := range map[int]time.Duration{
+		1: 1 * time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 8 * time.Second,
+		5: 10 * time.Second,
+		9: 10 * time.Second,
+	} {
+		if d := rp.Delay(attempts); d != expected {
+			t.Errorf("delay after %d attempts should be %s: %s", attempts, expected, d)
+		}
+	}
+
+	for attempts, expected := range map[int]bool{
+		1: false,
+		3: false,
+		4: true,
+		5: true,
+	} {
+		if f := rp.Fatal(attempts); f != expected {
+			t.Errorf("fatal after %d attempts should be %t", attempts, expected)
+		}
+	}
+
+	rp.MaxAttempts = 0
+
+	if rp.Fatal(1000) {
+		t.Error("zero max attempts should retry forever")
+	}
+}
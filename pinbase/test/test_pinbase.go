@@ -1,15 +1,47 @@
 package test
 
 import (
+	"context"
+	"encoding/json"
 	cerrors "errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/apiarian/ipfs-pinbase/pinbase/webhook"
 	"github.com/pkg/errors"
 )
 
+// Pins are kept under canonical CIDs. The tests name theirs after what they
+// stand for, in the same order as the CIDs sort.
+const (
+	hashA        = pinbase.Hash("bafybeihae2lwbj2j7qdukb72npgosvxgsaqdrzcy265huwd57vdlcymzn4")
+	hashAbc      = pinbase.Hash("bafybeihbigwyjgaq5gbvhfvwxd3a65kfo734spm5vyinbqsvhsjddupxeu")
+	hashB        = pinbase.Hash("bafybeihc26e24esn2h67rtmkgmshlevyh77gbtqav2ref336fsuer7ucnq")
+	hashBar      = pinbase.Hash("bafybeihdsoa3a56nbpfulgn3dfdtuicbopznctvonjnloojkhv7r6vkg6m")
+	hashBaz      = pinbase.Hash("bafybeihepajuf27egh3sgk6kn6y2tcfzvkca3j5phzfolea3vvwboxo5dm")
+	hashC        = pinbase.Hash("bafybeihfdp4mmi25ata2ojhd6rysm6wspu3qxxfvbhmronf6idqw6pgbae")
+	hashCurrent  = pinbase.Hash("bafybeihgkxj62hfmlscf6qeobwkvxffld3f3qdef7rgdryek62ynyyyvvq")
+	hashD        = pinbase.Hash("bafybeihhraid5yktdd6nfl43oo2oxoztveb3aig6pmyh24pv73ipim7fja")
+	hashFuture   = pinbase.Hash("bafybeihinzmytzsdygu5fudngsoyuaweiog543dsm5rz6pdqnoycg3k6nq")
+	hashH1       = pinbase.Hash("bafybeihjk54uhvnyy6tknbdym6x5wq4wrxtdng75cihmbomfenewkizrfq")
+	hashH2       = pinbase.Hash("bafybeihkysstm7ozemal2noxiuvcqlbra4kh3picapclsi7frpomk6lgx4")
+	hashOpen     = pinbase.Hash("bafybeihlqba3zyxud2okuwqamzaych5ty66wb2aghyxcdkhdrdpuaoggn4")
+	hashOpening  = pinbase.Hash("bafybeihmz2tbrwtcmnqwfchnh454aoymhc4ru4gqhs6a3uygmgcwyknonm")
+	hashPast     = pinbase.Hash("bafybeihnvricf5mz6hdlui6uvpzd2ipidqawpunbqsf2v7hlh3fulydmqu")
+	hashPin1     = pinbase.Hash("bafybeihovj5ksh4wu7ktm545fvlcfx6yfa6xcgyqu4zs5k3uff4bogqlwy")
+	hashPin2     = pinbase.Hash("bafybeihpeejpmdmux6jlaklhkrv6hfg4lmg24io7e5iy5cdtk56hv6y374")
+	hashQux      = pinbase.Hash("bafybeihqjklslkz2mfyvf5vp3gna2vafgekgjpu3kwgiaoquzutomnduz4")
+	hashUnsized  = pinbase.Hash("bafybeihrr236gkfkrcviht2wywc22no27464jfosyxadqcumpstxxhh5qq")
+	hashUnwanted = pinbase.Hash("bafybeihsvdpqwqklsbcabffddkvvtzod5p5cxffdci3l5txcfpmtk2l2oa")
+)
+
 func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 	// empty service is empty
 	parties, err := ps.Parties()
@@ -200,7 +232,7 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 
 	// create a pin
 	err = ps.CreatePin(pinbase.Hash("foo"), &pinbase.PinCreate{
-		ID:         pinbase.Hash("bar"),
+		ID:         hashBar,
 		Aliases:    []string{"really cool", "super rad"},
 		WantPinned: true,
 	})
@@ -208,6 +240,15 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 		t.Errorf("did not create pin: %+v", err)
 	}
 
+	// hash pins have to be CIDs
+	err = ps.CreatePin(pinbase.Hash("foo"), &pinbase.PinCreate{
+		ID:         pinbase.Hash("bar"),
+		WantPinned: true,
+	})
+	if errors.Cause(err) != pinbase.ErrInvalidHash {
+		t.Errorf("expected an invalid hash creating pin bar: %+v", err)
+	}
+
 	// list the pins
 	pins, err = ps.Pins(pinbase.Hash("foo"))
 	if err != nil {
@@ -221,7 +262,7 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 	if pin := pins[0]; !reflect.DeepEqual(
 		pin,
 		&pinbase.PinView{
-			ID:         pinbase.Hash("bar"),
+			ID:         hashBar,
 			Aliases:    []string{"really cool", "super rad"},
 			WantPinned: true,
 			Status:     pinbase.PinPending,
@@ -233,7 +274,7 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 
 	// add another pin
 	err = ps.CreatePin(pinbase.Hash("foo"), &pinbase.PinCreate{
-		ID:         pinbase.Hash("abc"),
+		ID:         hashAbc,
 		Aliases:    []string{"something"},
 		WantPinned: false,
 	})
@@ -258,15 +299,15 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 	if !reflect.DeepEqual(
 		pinsByKey,
 		map[pinbase.Hash]*pinbase.PinView{
-			pinbase.Hash("bar"): &pinbase.PinView{
-				ID:         pinbase.Hash("bar"),
+			hashBar: &pinbase.PinView{
+				ID:         hashBar,
 				Aliases:    []string{"really cool", "super rad"},
 				WantPinned: true,
 				Status:     pinbase.PinPending,
 				LastError:  nil,
 			},
-			pinbase.Hash("abc"): &pinbase.PinView{
-				ID:         pinbase.Hash("abc"),
+			hashAbc: &pinbase.PinView{
+				ID:         hashAbc,
 				Aliases:    []string{"something"},
 				WantPinned: false,
 				Status:     pinbase.PinPending,
@@ -278,7 +319,7 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 	}
 
 	// get an existing pin
-	pin, err := ps.Pin(pinbase.Hash("foo"), pinbase.Hash("bar"))
+	pin, err := ps.Pin(pinbase.Hash("foo"), hashBar)
 	if err != nil {
 		t.Errorf("did not get pin: %+v", err)
 	}
@@ -286,7 +327,7 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 	if !reflect.DeepEqual(
 		pin,
 		&pinbase.PinView{
-			ID:         pinbase.Hash("bar"),
+			ID:         hashBar,
 			Aliases:    []string{"really cool", "super rad"},
 			WantPinned: true,
 			Status:     pinbase.PinPending,
@@ -297,7 +338,7 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 	}
 
 	// try to get an nonexistent pin
-	pin, err = ps.Pin(pinbase.Hash("foo"), pinbase.Hash("baz"))
+	pin, err = ps.Pin(pinbase.Hash("foo"), hashBaz)
 	if err != nil {
 		t.Errorf("failed to try to get the pin: %+v", err)
 	}
@@ -309,7 +350,7 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 	// update a pin
 	err = ps.UpdatePin(
 		pinbase.Hash("foo"),
-		pinbase.Hash("bar"),
+		hashBar,
 		&pinbase.PinEdit{
 			Aliases:    []string{"really rad"},
 			WantPinned: false,
@@ -319,7 +360,7 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 		t.Errorf("did not update the pin: %+v", err)
 	}
 
-	pin, err = ps.Pin(pinbase.Hash("foo"), pinbase.Hash("bar"))
+	pin, err = ps.Pin(pinbase.Hash("foo"), hashBar)
 	if err != nil {
 		t.Errorf("did not get the pin: %+v", err)
 	}
@@ -327,7 +368,7 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 	if !reflect.DeepEqual(
 		pin,
 		&pinbase.PinView{
-			ID:         pinbase.Hash("bar"),
+			ID:         hashBar,
 			Aliases:    []string{"really rad"},
 			WantPinned: false,
 			Status:     pinbase.PinPending,
@@ -338,11 +379,11 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 	}
 
 	// delete a pin
-	err = ps.DeletePin(pinbase.Hash("foo"), pinbase.Hash("abc"))
+	err = ps.DeletePin(pinbase.Hash("foo"), hashAbc, "")
 	if err != nil {
 		t.Errorf("did not delete the pin: %+v", err)
 
-		pin, err = ps.Pin(pinbase.Hash("foo"), pinbase.Hash("abc"))
+		pin, err = ps.Pin(pinbase.Hash("foo"), hashAbc)
 		if err != nil {
 			t.Errorf("failed to try to get the pin: %+v", err)
 		}
@@ -360,17 +401,17 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 			t.Errorf("we don't have exactly one pin again: %+v", pins)
 		}
 
-		if pin := pins[0]; pin.ID != pinbase.Hash("bar") {
+		if pin := pins[0]; pin.ID != hashBar {
 			t.Errorf("we don't still have the expected pin: %+v", pin)
 		}
 
 		// delte a nonexistent pin
-		err = ps.DeletePin(pinbase.Hash("foo"), pinbase.Hash("baz"))
+		err = ps.DeletePin(pinbase.Hash("foo"), hashBaz, "")
 		if err != nil {
 			t.Errorf("failed to delete nonexistent pin: %+v", err)
 		}
 
-		pin, err := ps.Pin(pinbase.Hash("foo"), pinbase.Hash("baz"))
+		pin, err := ps.Pin(pinbase.Hash("foo"), hashBaz)
 		if err != nil {
 			t.Errorf("failed to try to get the pin: %+v", err)
 		}
@@ -381,6 +422,9 @@ func TestPinServiceHappyPath(t *testing.T, ps pinbase.PinService) {
 	}
 }
 
+// checkBump waits a little for a bump on c. Backends may send bumps from a
+// goroutine of their own, which can take longer than a few microseconds to
+// get scheduled.
 func checkBump(t *testing.T, tag string, expect bool, c <-chan struct{}) {
 	select {
 	case <-c:
@@ -390,7 +434,7 @@ func checkBump(t *testing.T, tag string, expect bool, c <-chan struct{}) {
 			t.Errorf("%s: got an unexpected bump", tag)
 		}
 
-	case <-time.After(25 * time.Microsecond):
+	case <-time.After(25 * time.Millisecond):
 		if expect {
 			t.Errorf("%s: did not get the expected bump", tag)
 		} else {
@@ -423,7 +467,7 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 	err = ps.CreatePin(
 		pinbase.Hash("foo"),
 		&pinbase.PinCreate{
-			ID:         pinbase.Hash("bar"),
+			ID:         hashBar,
 			Aliases:    []string{"something"},
 			WantPinned: true,
 		},
@@ -437,8 +481,8 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 	reqs = pb.PinRequirements()
 	if !reflect.DeepEqual(
 		reqs,
-		map[pinbase.Hash]bool{
-			pinbase.Hash("bar"): true,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true},
 		},
 	) {
 		t.Errorf("got the wrong requirements: %+v", reqs)
@@ -446,7 +490,7 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 
 	err = ps.UpdatePin(
 		pinbase.Hash("foo"),
-		pinbase.Hash("bar"),
+		hashBar,
 		&pinbase.PinEdit{
 			Aliases:    []string{"something", "something else"},
 			WantPinned: true,
@@ -461,8 +505,8 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 	reqs = pb.PinRequirements()
 	if !reflect.DeepEqual(
 		reqs,
-		map[pinbase.Hash]bool{
-			pinbase.Hash("bar"): true,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true},
 		},
 	) {
 		t.Errorf("pin requirements changed unexpectedly: %+v", reqs)
@@ -470,7 +514,7 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 
 	err = ps.UpdatePin(
 		pinbase.Hash("foo"),
-		pinbase.Hash("bar"),
+		hashBar,
 		&pinbase.PinEdit{
 			Aliases:    []string{"something"},
 			WantPinned: false,
@@ -485,8 +529,8 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 	reqs = pb.PinRequirements()
 	if !reflect.DeepEqual(
 		reqs,
-		map[pinbase.Hash]bool{
-			pinbase.Hash("bar"): false,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: false},
 		},
 	) {
 		t.Errorf("pin requirements are wrong: %+v", reqs)
@@ -495,7 +539,7 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 	err = ps.CreatePin(
 		pinbase.Hash("foo"),
 		&pinbase.PinCreate{
-			ID:         pinbase.Hash("baz"),
+			ID:         hashBaz,
 			Aliases:    []string{"doomed"},
 			WantPinned: true,
 		},
@@ -509,15 +553,15 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 	reqs = pb.PinRequirements()
 	if !reflect.DeepEqual(
 		reqs,
-		map[pinbase.Hash]bool{
-			pinbase.Hash("bar"): false,
-			pinbase.Hash("baz"): true,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: false},
+			hashBaz: {WantPinned: true},
 		},
 	) {
 		t.Errorf("pin requirements are wrong: %+v", reqs)
 	}
 
-	err = ps.DeletePin(pinbase.Hash("foo"), pinbase.Hash("baz"))
+	err = ps.DeletePin(pinbase.Hash("foo"), hashBaz, "")
 	if err != nil {
 		t.Errorf("failed to delete pin: %+v", err)
 	}
@@ -527,9 +571,9 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 	reqs = pb.PinRequirements()
 	if !reflect.DeepEqual(
 		reqs,
-		map[pinbase.Hash]bool{
-			pinbase.Hash("bar"): false,
-			pinbase.Hash("baz"): false,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: false},
+			hashBaz: {WantPinned: false},
 		},
 	) {
 		t.Errorf("pin requirements are wrong: %+v", reqs)
@@ -537,7 +581,7 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 
 	err = ps.UpdatePin(
 		pinbase.Hash("foo"),
-		pinbase.Hash("bar"),
+		hashBar,
 		&pinbase.PinEdit{
 			Aliases:    []string{"everything is about to end"},
 			WantPinned: true,
@@ -552,9 +596,9 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 	reqs = pb.PinRequirements()
 	if !reflect.DeepEqual(
 		reqs,
-		map[pinbase.Hash]bool{
-			pinbase.Hash("bar"): true,
-			pinbase.Hash("baz"): false,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true},
+			hashBaz: {WantPinned: false},
 		},
 	) {
 		t.Errorf("pin requirements are wrong: %+v", reqs)
@@ -570,9 +614,9 @@ func TestPinBackendHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pin
 	reqs = pb.PinRequirements()
 	if !reflect.DeepEqual(
 		reqs,
-		map[pinbase.Hash]bool{
-			pinbase.Hash("bar"): false,
-			pinbase.Hash("baz"): false,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: false},
+			hashBaz: {WantPinned: false},
 		},
 	) {
 		t.Errorf("pin requirements are wrong: %+v", reqs)
@@ -627,7 +671,7 @@ func TestPinFeedbackHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pi
 	err = ps.CreatePin(
 		pinbase.Hash("party1"),
 		&pinbase.PinCreate{
-			ID:         pinbase.Hash("pin1"),
+			ID:         hashPin1,
 			Aliases:    []string{"something"},
 			WantPinned: true,
 		},
@@ -639,7 +683,7 @@ func TestPinFeedbackHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pi
 	err = ps.CreatePin(
 		pinbase.Hash("party2"),
 		&pinbase.PinCreate{
-			ID:         pinbase.Hash("pin1"),
+			ID:         hashPin1,
 			Aliases:    []string{"yup, something"},
 			WantPinned: true,
 		},
@@ -651,7 +695,7 @@ func TestPinFeedbackHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pi
 	err = ps.CreatePin(
 		pinbase.Hash("party1"),
 		&pinbase.PinCreate{
-			ID:         pinbase.Hash("pin2"),
+			ID:         hashPin2,
 			Aliases:    []string{"something else"},
 			WantPinned: true,
 		},
@@ -666,15 +710,15 @@ func TestPinFeedbackHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pi
 		ps,
 		map[pinbase.Hash]map[pinbase.Hash]*pinbase.PinView{
 			pinbase.Hash("party1"): map[pinbase.Hash]*pinbase.PinView{
-				pinbase.Hash("pin1"): &pinbase.PinView{
-					ID:         pinbase.Hash("pin1"),
+				hashPin1: &pinbase.PinView{
+					ID:         hashPin1,
 					Aliases:    []string{"something"},
 					WantPinned: true,
 					Status:     pinbase.PinPending,
 					LastError:  nil,
 				},
-				pinbase.Hash("pin2"): &pinbase.PinView{
-					ID:         pinbase.Hash("pin2"),
+				hashPin2: &pinbase.PinView{
+					ID:         hashPin2,
 					Aliases:    []string{"something else"},
 					WantPinned: true,
 					Status:     pinbase.PinPending,
@@ -682,8 +726,8 @@ func TestPinFeedbackHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pi
 				},
 			},
 			pinbase.Hash("party2"): map[pinbase.Hash]*pinbase.PinView{
-				pinbase.Hash("pin1"): &pinbase.PinView{
-					ID:         pinbase.Hash("pin1"),
+				hashPin1: &pinbase.PinView{
+					ID:         hashPin1,
 					Aliases:    []string{"yup, something"},
 					WantPinned: true,
 					Status:     pinbase.PinPending,
@@ -694,7 +738,7 @@ func TestPinFeedbackHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pi
 	)
 
 	pb.NotifyPin(
-		pinbase.Hash("pin1"),
+		hashPin1,
 		&pinbase.PinBackendState{
 			Status:    pinbase.PinError,
 			LastError: errors.New("ohz noz something went wrong"),
@@ -702,7 +746,7 @@ func TestPinFeedbackHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pi
 	)
 
 	pb.NotifyPin(
-		pinbase.Hash("pin2"),
+		hashPin2,
 		&pinbase.PinBackendState{
 			Status:    pinbase.PinPinned,
 			LastError: nil,
@@ -715,15 +759,15 @@ func TestPinFeedbackHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pi
 		ps,
 		map[pinbase.Hash]map[pinbase.Hash]*pinbase.PinView{
 			pinbase.Hash("party1"): map[pinbase.Hash]*pinbase.PinView{
-				pinbase.Hash("pin1"): &pinbase.PinView{
-					ID:         pinbase.Hash("pin1"),
+				hashPin1: &pinbase.PinView{
+					ID:         hashPin1,
 					Aliases:    []string{"something"},
 					WantPinned: true,
 					Status:     pinbase.PinError,
 					LastError:  cerrors.New("ohz noz something went wrong"),
 				},
-				pinbase.Hash("pin2"): &pinbase.PinView{
-					ID:         pinbase.Hash("pin2"),
+				hashPin2: &pinbase.PinView{
+					ID:         hashPin2,
 					Aliases:    []string{"something else"},
 					WantPinned: true,
 					Status:     pinbase.PinPinned,
@@ -731,8 +775,8 @@ func TestPinFeedbackHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pi
 				},
 			},
 			pinbase.Hash("party2"): map[pinbase.Hash]*pinbase.PinView{
-				pinbase.Hash("pin1"): &pinbase.PinView{
-					ID:         pinbase.Hash("pin1"),
+				hashPin1: &pinbase.PinView{
+					ID:         hashPin1,
 					Aliases:    []string{"yup, something"},
 					WantPinned: true,
 					Status:     pinbase.PinError,
@@ -742,3 +786,2251 @@ func TestPinFeedbackHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.Pi
 		},
 	)
 }
+
+func checkPinStatus(t *testing.T, tag string, ps pinbase.PinService, partyID, pinID pinbase.Hash, expected pinbase.PinStatus) {
+	pin, err := ps.Pin(partyID, pinID)
+	if err != nil {
+		t.Errorf("%s: failed to get pin: %+v", tag, err)
+		return
+	}
+
+	if pin == nil {
+		t.Errorf("%s: did not find pin %s under party %s", tag, pinID, partyID)
+		return
+	}
+
+	if pin.Status != expected {
+		t.Errorf("%s: got status %s, expected %s", tag, pin.Status, expected)
+	}
+}
+
+// TestPinRetryHappyPath expects the backend to be configured with the given
+// retry policy, with a MaxAttempts of 3.
+func TestPinRetryHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.PinService, rp pinbase.RetryPolicy) {
+	err := ps.CreateParty(&pinbase.PartyCreate{
+		ID:          pinbase.Hash("foo"),
+		Description: "hello",
+	})
+	if err != nil {
+		t.Errorf("failed to create party: %+v", err)
+	}
+
+	err = ps.CreatePin(
+		pinbase.Hash("foo"),
+		&pinbase.PinCreate{
+			ID:         hashBar,
+			Aliases:    []string{"flaky"},
+			WantPinned: true,
+		},
+	)
+	if err != nil {
+		t.Errorf("failed to create pin: %+v", err)
+	}
+
+	checkBump(t, "pin created", true, pb.PinProcessorBump())
+
+	fail := &pinbase.PinBackendState{
+		Status:    pinbase.PinError,
+		LastError: errors.New("could not find it"),
+	}
+
+	for i := 1; i < 3; i++ {
+		pb.NotifyPin(hashBar, fail)
+
+		checkPinStatus(t, "failed attempt", ps, pinbase.Hash("foo"), hashBar, pinbase.PinError)
+
+		reqs := pb.PinRequirements()
+		if len(reqs) != 0 {
+			t.Errorf("attempt %d: pin not backed off: %+v", i, reqs)
+		}
+
+		time.Sleep(rp.Delay(i) + 10*time.Millisecond)
+
+		reqs = pb.PinRequirements()
+		if !reflect.DeepEqual(
+			reqs,
+			map[pinbase.Hash]pinbase.PinRequirement{
+				hashBar: {WantPinned: true},
+			},
+		) {
+			t.Errorf("attempt %d: pin not retried after backoff: %+v", i, reqs)
+		}
+	}
+
+	pb.NotifyPin(hashBar, fail)
+
+	checkPinStatus(t, "final attempt", ps, pinbase.Hash("foo"), hashBar, pinbase.PinFatal)
+
+	time.Sleep(rp.MaxDelay + 10*time.Millisecond)
+
+	reqs := pb.PinRequirements()
+	if len(reqs) != 0 {
+		t.Errorf("fatal pin still required: %+v", reqs)
+	}
+
+	err = ps.ResetPin(pinbase.Hash("foo"), hashBar, "")
+	if err != nil {
+		t.Errorf("failed to reset pin: %+v", err)
+	}
+
+	checkBump(t, "pin reset", true, pb.PinProcessorBump())
+
+	checkPinStatus(t, "reset", ps, pinbase.Hash("foo"), hashBar, pinbase.PinPending)
+
+	reqs = pb.PinRequirements()
+	if !reflect.DeepEqual(
+		reqs,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true},
+		},
+	) {
+		t.Errorf("reset pin not required: %+v", reqs)
+	}
+
+	// a single failure after the reset starts the count over
+	pb.NotifyPin(hashBar, fail)
+
+	checkPinStatus(t, "failed after reset", ps, pinbase.Hash("foo"), hashBar, pinbase.PinError)
+
+	pb.NotifyPin(
+		hashBar,
+		&pinbase.PinBackendState{
+			Status:    pinbase.PinPinned,
+			LastError: nil,
+		},
+	)
+
+	checkPinStatus(t, "pinned", ps, pinbase.Hash("foo"), hashBar, pinbase.PinPinned)
+
+	reqs = pb.PinRequirements()
+	if !reflect.DeepEqual(
+		reqs,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true},
+		},
+	) {
+		t.Errorf("pinned pin not required: %+v", reqs)
+	}
+
+	err = ps.ResetPin(pinbase.Hash("foo"), hashBaz, "")
+	if err == nil {
+		t.Error("did not get an error resetting a nonexistent pin")
+	}
+
+	// a party still waiting out its backoff did not make the attempt that
+	// failed for another party holding the same hash
+	err = ps.CreateParty(&pinbase.PartyCreate{
+		ID:          pinbase.Hash("bar"),
+		Description: "world",
+	})
+	if err != nil {
+		t.Errorf("failed to create party: %+v", err)
+	}
+
+	err = ps.CreatePin(pinbase.Hash("foo"), &pinbase.PinCreate{ID: hashBaz, WantPinned: true})
+	if err != nil {
+		t.Errorf("failed to create pin: %+v", err)
+	}
+
+	pb.NotifyPin(hashBaz, fail)
+
+	err = ps.CreatePin(pinbase.Hash("bar"), &pinbase.PinCreate{ID: hashBaz, WantPinned: true})
+	if err != nil {
+		t.Errorf("failed to create pin: %+v", err)
+	}
+
+	pb.NotifyPin(hashBaz, fail)
+
+	time.Sleep(rp.MaxDelay + 10*time.Millisecond)
+
+	pb.NotifyPin(hashBaz, fail)
+
+	checkPinStatus(t, "second attempt", ps, pinbase.Hash("foo"), hashBaz, pinbase.PinError)
+	checkPinStatus(t, "second attempt", ps, pinbase.Hash("bar"), hashBaz, pinbase.PinError)
+
+	time.Sleep(rp.MaxDelay + 10*time.Millisecond)
+
+	pb.NotifyPin(hashBaz, fail)
+
+	checkPinStatus(t, "third attempt", ps, pinbase.Hash("foo"), hashBaz, pinbase.PinFatal)
+	checkPinStatus(t, "third attempt", ps, pinbase.Hash("bar"), hashBaz, pinbase.PinFatal)
+}
+
+func checkRequirements(t *testing.T, tag string, reqs, expected map[pinbase.Hash]pinbase.PinRequirement) {
+	if !reflect.DeepEqual(reqs, expected) {
+		t.Errorf("%s: got requirements %+v, expected %+v", tag, reqs, expected)
+	}
+}
+
+func TestPinDirtyHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.PinService) {
+	checkRequirements(t, "start", pb.DirtyPinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{})
+
+	for _, party := range []pinbase.Hash{"foo", "baz"} {
+		err := ps.CreateParty(&pinbase.PartyCreate{
+			ID:          party,
+			Description: "hello",
+		})
+		if err != nil {
+			t.Errorf("failed to create party %s: %+v", party, err)
+		}
+	}
+
+	checkRequirements(t, "parties created", pb.DirtyPinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{})
+
+	err := ps.CreatePin(
+		pinbase.Hash("foo"),
+		&pinbase.PinCreate{
+			ID:         hashBar,
+			WantPinned: true,
+		},
+	)
+	if err != nil {
+		t.Errorf("failed to create pin: %+v", err)
+	}
+
+	checkBump(t, "bar created", true, pb.PinProcessorBump())
+
+	err = ps.CreatePin(
+		pinbase.Hash("baz"),
+		&pinbase.PinCreate{
+			ID:         hashQux,
+			WantPinned: true,
+		},
+	)
+	if err != nil {
+		t.Errorf("failed to create pin: %+v", err)
+	}
+
+	checkBump(t, "qux created", true, pb.PinProcessorBump())
+
+	checkRequirements(
+		t,
+		"pins created",
+		pb.DirtyPinRequirements(),
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true},
+			hashQux: {WantPinned: true},
+		},
+	)
+
+	checkRequirements(t, "dirty set taken", pb.DirtyPinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{})
+
+	// another party not wanting the pin must not outvote the first one
+	err = ps.CreatePin(
+		pinbase.Hash("baz"),
+		&pinbase.PinCreate{
+			ID:         hashBar,
+			WantPinned: false,
+		},
+	)
+	if err != nil {
+		t.Errorf("failed to create pin: %+v", err)
+	}
+
+	checkBump(t, "shared pin created", true, pb.PinProcessorBump())
+
+	checkRequirements(
+		t,
+		"shared pin",
+		pb.DirtyPinRequirements(),
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true},
+		},
+	)
+
+	err = ps.DeletePin(pinbase.Hash("foo"), hashBar, "")
+	if err != nil {
+		t.Errorf("failed to delete pin: %+v", err)
+	}
+
+	checkBump(t, "bar deleted", true, pb.PinProcessorBump())
+
+	checkRequirements(
+		t,
+		"bar deleted",
+		pb.DirtyPinRequirements(),
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: false},
+		},
+	)
+
+	err = ps.DeleteParty(pinbase.Hash("baz"))
+	if err != nil {
+		t.Errorf("failed to delete party: %+v", err)
+	}
+
+	checkBump(t, "baz deleted", true, pb.PinProcessorBump())
+
+	checkRequirements(
+		t,
+		"baz deleted",
+		pb.DirtyPinRequirements(),
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: false},
+			hashQux: {WantPinned: false},
+		},
+	)
+
+	err = ps.CreatePin(
+		pinbase.Hash("foo"),
+		&pinbase.PinCreate{
+			ID:         hashBar,
+			WantPinned: true,
+		},
+	)
+	if err != nil {
+		t.Errorf("failed to create pin: %+v", err)
+	}
+
+	checkBump(t, "bar recreated", true, pb.PinProcessorBump())
+
+	// a full sweep covers the dirty hashes as well
+	checkRequirements(
+		t,
+		"full sweep",
+		pb.PinRequirements(),
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true},
+			hashQux: {WantPinned: false},
+		},
+	)
+
+	checkRequirements(t, "after full sweep", pb.DirtyPinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{})
+}
+
+func checkPinParties(t *testing.T, tag string, ps pinbase.PinService, pinID pinbase.Hash, expected []pinbase.Hash) {
+	parties, err := ps.PinParties(pinID)
+	if err != nil {
+		t.Errorf("%s: failed to get pin parties: %+v", tag, err)
+		return
+	}
+
+	var ids []pinbase.Hash
+	for _, p := range parties {
+		ids = append(ids, p.ID)
+	}
+
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("%s: got pin parties %+v, expected %+v", tag, ids, expected)
+	}
+}
+
+func TestPinPartiesHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.PinService) {
+	checkPinParties(t, "start", ps, hashBar, nil)
+
+	for _, party := range []pinbase.Hash{"baz", "foo", "qux"} {
+		err := ps.CreateParty(&pinbase.PartyCreate{
+			ID:          party,
+			Description: "hello",
+		})
+		if err != nil {
+			t.Errorf("failed to create party %s: %+v", party, err)
+		}
+
+		err = ps.CreatePin(
+			party,
+			&pinbase.PinCreate{
+				ID:         hashBar,
+				WantPinned: party != pinbase.Hash("qux"),
+			},
+		)
+		if err != nil {
+			t.Errorf("failed to create pin for %s: %+v", party, err)
+		}
+
+		checkBump(t, "pin created", true, pb.PinProcessorBump())
+	}
+
+	checkPinParties(
+		t,
+		"pins created",
+		ps,
+		hashBar,
+		[]pinbase.Hash{"baz", "foo", "qux"},
+	)
+
+	pb.NotifyPin(
+		hashBar,
+		&pinbase.PinBackendState{
+			Status:    pinbase.PinPinned,
+			LastError: nil,
+		},
+	)
+
+	for _, party := range []pinbase.Hash{"baz", "foo", "qux"} {
+		checkPinStatus(t, "notified", ps, party, hashBar, pinbase.PinPinned)
+	}
+
+	err := ps.DeletePin(pinbase.Hash("foo"), hashBar, "")
+	if err != nil {
+		t.Errorf("failed to delete pin: %+v", err)
+	}
+
+	checkBump(t, "pin deleted", true, pb.PinProcessorBump())
+
+	checkPinParties(
+		t,
+		"pin deleted",
+		ps,
+		hashBar,
+		[]pinbase.Hash{"baz", "qux"},
+	)
+
+	reqs := pb.PinRequirements()
+	if !reflect.DeepEqual(
+		reqs,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true},
+		},
+	) {
+		t.Errorf("pin held by another party not required: %+v", reqs)
+	}
+
+	err = ps.DeleteParty(pinbase.Hash("baz"))
+	if err != nil {
+		t.Errorf("failed to delete party: %+v", err)
+	}
+
+	checkBump(t, "party deleted", true, pb.PinProcessorBump())
+
+	checkPinParties(
+		t,
+		"party deleted",
+		ps,
+		hashBar,
+		[]pinbase.Hash{"qux"},
+	)
+
+	reqs = pb.PinRequirements()
+	if !reflect.DeepEqual(
+		reqs,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: false},
+		},
+	) {
+		t.Errorf("pin only held unwanted not unpinned: %+v", reqs)
+	}
+
+	err = ps.DeleteParty(pinbase.Hash("qux"))
+	if err != nil {
+		t.Errorf("failed to delete party: %+v", err)
+	}
+
+	checkBump(t, "last party deleted", true, pb.PinProcessorBump())
+
+	checkPinParties(t, "last party deleted", ps, hashBar, nil)
+
+	reqs = pb.PinRequirements()
+	if !reflect.DeepEqual(
+		reqs,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: false},
+		},
+	) {
+		t.Errorf("orphaned pin not archived: %+v", reqs)
+	}
+}
+
+func checkArchive(t *testing.T, tag string, ps pinbase.PinService, expected []*pinbase.ArchivedPinView) {
+	archived, err := ps.ArchivedPins()
+	if err != nil {
+		t.Errorf("%s: failed to get archived pins: %+v", tag, err)
+		return
+	}
+
+	if !reflect.DeepEqual(archived, expected) {
+		t.Errorf("%s: got archived pins %+v, expected %+v", tag, archived, expected)
+	}
+}
+
+func TestPinArchiveHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.PinService) {
+	checkArchive(t, "start", ps, nil)
+
+	err := ps.CreateParty(&pinbase.PartyCreate{
+		ID:          pinbase.Hash("foo"),
+		Description: "hello",
+	})
+	if err != nil {
+		t.Errorf("failed to create party: %+v", err)
+	}
+
+	for _, pin := range []pinbase.Hash{hashBar, hashBaz} {
+		err = ps.CreatePin(
+			pinbase.Hash("foo"),
+			&pinbase.PinCreate{
+				ID:         pin,
+				WantPinned: true,
+			},
+		)
+		if err != nil {
+			t.Errorf("failed to create pin %s: %+v", pin, err)
+		}
+
+		checkBump(t, "pin created", true, pb.PinProcessorBump())
+
+		err = ps.DeletePin(pinbase.Hash("foo"), pin, "")
+		if err != nil {
+			t.Errorf("failed to delete pin %s: %+v", pin, err)
+		}
+
+		checkBump(t, "pin deleted", true, pb.PinProcessorBump())
+	}
+
+	checkArchive(
+		t,
+		"pins deleted",
+		ps,
+		[]*pinbase.ArchivedPinView{
+			&pinbase.ArchivedPinView{
+				ID:     hashBar,
+				Status: pinbase.PinPending,
+			},
+			&pinbase.ArchivedPinView{
+				ID:     hashBaz,
+				Status: pinbase.PinPending,
+			},
+		},
+	)
+
+	pb.NotifyPin(
+		hashBar,
+		&pinbase.PinBackendState{
+			Status:    pinbase.PinError,
+			LastError: errors.New("node said no"),
+		},
+	)
+
+	pb.NotifyPin(
+		hashBaz,
+		&pinbase.PinBackendState{
+			Status:    pinbase.PinUnpinned,
+			LastError: nil,
+		},
+	)
+
+	checkArchive(
+		t,
+		"notified",
+		ps,
+		[]*pinbase.ArchivedPinView{
+			&pinbase.ArchivedPinView{
+				ID:        hashBar,
+				Status:    pinbase.PinError,
+				LastError: cerrors.New("node said no"),
+			},
+		},
+	)
+
+	reqs := pb.PinRequirements()
+	if !reflect.DeepEqual(
+		reqs,
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: false},
+		},
+	) {
+		t.Errorf("unpinned archive entry still required: %+v", reqs)
+	}
+
+	// a party picking the hash up again takes it out of the archive
+	err = ps.CreatePin(
+		pinbase.Hash("foo"),
+		&pinbase.PinCreate{
+			ID:         hashBar,
+			WantPinned: true,
+		},
+	)
+	if err != nil {
+		t.Errorf("failed to recreate pin: %+v", err)
+	}
+
+	checkBump(t, "pin recreated", true, pb.PinProcessorBump())
+
+	checkArchive(t, "pin recreated", ps, nil)
+}
+
+func TestPinProgressHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.PinService) {
+	err := ps.CreateParty(&pinbase.PartyCreate{
+		ID:          pinbase.Hash("foo"),
+		Description: "hello",
+	})
+	if err != nil {
+		t.Errorf("failed to create party: %+v", err)
+	}
+
+	err = ps.CreatePin(
+		pinbase.Hash("foo"),
+		&pinbase.PinCreate{
+			ID:         hashBar,
+			WantPinned: true,
+		},
+	)
+	if err != nil {
+		t.Errorf("failed to create pin: %+v", err)
+	}
+
+	checkBump(t, "pin created", true, pb.PinProcessorBump())
+
+	checkProgress := func(tag string, status pinbase.PinStatus, progress pinbase.PinProgress) {
+		pin, err := ps.Pin(pinbase.Hash("foo"), hashBar)
+		if err != nil {
+			t.Errorf("%s: failed to get pin: %+v", tag, err)
+			return
+		}
+
+		if pin.Status != status || pin.Progress != progress {
+			t.Errorf("%s: got %s %s, expected %s %s", tag, pin.Status, &pin.Progress, status, &progress)
+		}
+	}
+
+	checkProgress("created", pinbase.PinPending, pinbase.PinProgress{})
+
+	pb.NotifyPin(
+		hashBar,
+		&pinbase.PinBackendState{
+			Status:   pinbase.PinPinning,
+			Progress: &pinbase.PinProgress{Blocks: 10, Bytes: 2048},
+		},
+	)
+
+	checkProgress("pinning", pinbase.PinPinning, pinbase.PinProgress{Blocks: 10, Bytes: 2048})
+
+	pb.NotifyPin(
+		hashBar,
+		&pinbase.PinBackendState{
+			Status: pinbase.PinPinned,
+		},
+	)
+
+	checkProgress("pinned", pinbase.PinPinned, pinbase.PinProgress{Blocks: 10, Bytes: 2048})
+
+	err = ps.ResetPin(pinbase.Hash("foo"), hashBar, "")
+	if err != nil {
+		t.Errorf("failed to reset pin: %+v", err)
+	}
+
+	checkBump(t, "pin reset", true, pb.PinProcessorBump())
+
+	checkProgress("reset", pinbase.PinPending, pinbase.PinProgress{})
+}
+
+func TestPinModeHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.PinService) {
+	for _, party := range []pinbase.Hash{"foo", "baz"} {
+		err := ps.CreateParty(&pinbase.PartyCreate{
+			ID:          party,
+			Description: "hello",
+		})
+		if err != nil {
+			t.Errorf("failed to create party %s: %+v", party, err)
+		}
+	}
+
+	err := ps.CreatePin(
+		pinbase.Hash("foo"),
+		&pinbase.PinCreate{
+			ID:         hashBar,
+			WantPinned: true,
+			Mode:       pinbase.PinDirect,
+		},
+	)
+	if err != nil {
+		t.Errorf("failed to create pin: %+v", err)
+	}
+
+	checkBump(t, "direct pin created", true, pb.PinProcessorBump())
+
+	pin, err := ps.Pin(pinbase.Hash("foo"), hashBar)
+	if err != nil {
+		t.Errorf("failed to get pin: %+v", err)
+	} else if pin.Mode != pinbase.PinDirect {
+		t.Errorf("pin mode not stored: %s", pin.Mode)
+	}
+
+	checkRequirements(
+		t,
+		"direct pin",
+		pb.PinRequirements(),
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true, Mode: pinbase.PinDirect},
+		},
+	)
+
+	err = ps.CreatePin(
+		pinbase.Hash("baz"),
+		&pinbase.PinCreate{
+			ID:         hashBar,
+			WantPinned: true,
+			Mode:       pinbase.PinRecursive,
+		},
+	)
+	if err != nil {
+		t.Errorf("failed to create pin: %+v", err)
+	}
+
+	checkBump(t, "recursive pin created", true, pb.PinProcessorBump())
+
+	checkRequirements(
+		t,
+		"recursive pin dirty",
+		pb.DirtyPinRequirements(),
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true, Mode: pinbase.PinRecursive},
+		},
+	)
+
+	checkRequirements(
+		t,
+		"recursive pin",
+		pb.PinRequirements(),
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true, Mode: pinbase.PinRecursive},
+		},
+	)
+
+	// changing only the mode is enough to get the pin looked at again
+	err = ps.UpdatePin(
+		pinbase.Hash("baz"),
+		hashBar,
+		&pinbase.PinEdit{
+			WantPinned: true,
+			Mode:       pinbase.PinDirect,
+		},
+	)
+	if err != nil {
+		t.Errorf("failed to update pin: %+v", err)
+	}
+
+	checkBump(t, "pin mode updated", true, pb.PinProcessorBump())
+
+	checkRequirements(
+		t,
+		"pin mode updated",
+		pb.DirtyPinRequirements(),
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true, Mode: pinbase.PinDirect},
+		},
+	)
+}
+
+func TestPinReplicationHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.PinService) {
+	for _, party := range []pinbase.Hash{"foo", "baz"} {
+		err := ps.CreateParty(&pinbase.PartyCreate{
+			ID:          party,
+			Description: "hello",
+		})
+		if err != nil {
+			t.Errorf("failed to create party %s: %+v", party, err)
+		}
+	}
+
+	for party, replication := range map[pinbase.Hash]int{"foo": 2, "baz": 3} {
+		err := ps.CreatePin(
+			party,
+			&pinbase.PinCreate{
+				ID:          hashBar,
+				WantPinned:  true,
+				Replication: replication,
+			},
+		)
+		if err != nil {
+			t.Errorf("failed to create pin for %s: %+v", party, err)
+		}
+
+		checkBump(t, "pin created", true, pb.PinProcessorBump())
+	}
+
+	checkRequirements(
+		t,
+		"replicated pins",
+		pb.PinRequirements(),
+		map[pinbase.Hash]pinbase.PinRequirement{
+			hashBar: {WantPinned: true, Replication: 3},
+		},
+	)
+
+	pb.NotifyPin(
+		hashBar,
+		&pinbase.PinBackendState{
+			Status: pinbase.PinPinning,
+			Nodes: map[string]pinbase.NodePinState{
+				"a": {Status: pinbase.PinPinning},
+			},
+		},
+	)
+
+	pb.NotifyPin(
+		hashBar,
+		&pinbase.PinBackendState{
+			Status: pinbase.PinPinning,
+			Nodes: map[string]pinbase.NodePinState{
+				"b": {Status: pinbase.PinPinning},
+			},
+		},
+	)
+
+	checkNodes := func(tag string, expected map[string]pinbase.NodePinState) {
+		for _, party := range []pinbase.Hash{"foo", "baz"} {
+			pin, err := ps.Pin(party, hashBar)
+			if err != nil {
+				t.Errorf("%s: failed to get pin for %s: %+v", tag, party, err)
+				continue
+			}
+
+			if !reflect.DeepEqual(pin.Nodes, expected) {
+				t.Errorf("%s: got nodes %+v for %s, expected %+v", tag, pin.Nodes, party, expected)
+			}
+		}
+	}
+
+	checkNodes(
+		"pinning",
+		map[string]pinbase.NodePinState{
+			"a": {Status: pinbase.PinPinning},
+			"b": {Status: pinbase.PinPinning},
+		},
+	)
+
+	pb.NotifyPin(
+		hashBar,
+		&pinbase.PinBackendState{
+			Status:    pinbase.PinError,
+			LastError: errors.New("b is full"),
+			Nodes: map[string]pinbase.NodePinState{
+				"a": {Status: pinbase.PinPinned},
+				"b": {Status: pinbase.PinError, LastError: errors.New("b is full")},
+			},
+		},
+	)
+
+	checkNodes(
+		"settled",
+		map[string]pinbase.NodePinState{
+			"a": {Status: pinbase.PinPinned},
+			"b": {Status: pinbase.PinError, LastError: cerrors.New("b is full")},
+		},
+	)
+
+	pin, err := ps.Pin(pinbase.Hash("foo"), hashBar)
+	if err != nil {
+		t.Errorf("failed to get pin: %+v", err)
+	} else if pin.Replication != 2 {
+		t.Errorf("replication not stored: %d", pin.Replication)
+	}
+}
+
+func checkNode(t *testing.T, tag string, ns pinbase.NodeService, id string, expected *pinbase.NodeView) *pinbase.NodeView {
+	nv, err := ns.Node(id)
+	if err != nil {
+		t.Errorf("%s: failed to get node %s: %+v", tag, id, err)
+		return nil
+	}
+
+	if expected == nil || nv == nil {
+		if nv != expected {
+			t.Errorf("%s: got node %v, expected %v", tag, nv, expected)
+		}
+		return nv
+	}
+
+	// the last seen time is set by the service, so only its presence is checked
+	if nv.LastSeen.IsZero() != expected.LastSeen.IsZero() {
+		t.Errorf("%s: got node last seen at %v, expected it to be zero: %t", tag, nv.LastSeen, expected.LastSeen.IsZero())
+	}
+
+	got := *nv
+	got.LastSeen = expected.LastSeen
+	if !reflect.DeepEqual(&got, expected) {
+		t.Errorf("%s: got node %v, expected %v", tag, nv, expected)
+	}
+
+	return nv
+}
+
+func TestNodeServiceHappyPath(t *testing.T, ns pinbase.NodeService) {
+	nodes, err := ns.Nodes()
+	if err != nil {
+		t.Errorf("failed to get nodes: %+v", err)
+	}
+	if len(nodes) != 0 {
+		t.Errorf("started out with nodes: %v", nodes)
+	}
+
+	checkNode(t, "start", ns, "a", nil)
+
+	for _, nc := range []*pinbase.NodeCreate{
+		{ID: "b", APIAddress: "127.0.0.1:5002"},
+		{ID: "a", APIAddress: "127.0.0.1:5001"},
+	} {
+		err = ns.CreateNode(nc)
+		if err != nil {
+			t.Errorf("failed to create node %s: %+v", nc.ID, err)
+		}
+	}
+
+	err = ns.CreateNode(&pinbase.NodeCreate{ID: "a", APIAddress: "127.0.0.1:5003"})
+	if err == nil {
+		t.Error("created node a twice")
+	}
+
+	err = ns.CreateNode(&pinbase.NodeCreate{ID: "c"})
+	if err == nil {
+		t.Error("created node c without an address")
+	}
+
+	nodes, err = ns.Nodes()
+	if err != nil {
+		t.Errorf("failed to get nodes: %+v", err)
+	}
+	expectedNodes := []*pinbase.NodeView{
+		{ID: "a", APIAddress: "127.0.0.1:5001"},
+		{ID: "b", APIAddress: "127.0.0.1:5002"},
+	}
+	if !reflect.DeepEqual(nodes, expectedNodes) {
+		t.Errorf("got nodes %v, expected %v", nodes, expectedNodes)
+	}
+
+	err = ns.NotifyNode("a", &pinbase.NodeHealth{Reachable: true, PinCount: 3, RepoSize: 1024})
+	if err != nil {
+		t.Errorf("failed to notify node a: %+v", err)
+	}
+
+	seen := checkNode(
+		t,
+		"reachable",
+		ns,
+		"a",
+		&pinbase.NodeView{
+			ID:         "a",
+			APIAddress: "127.0.0.1:5001",
+			Reachable:  true,
+			LastSeen:   time.Now(),
+			PinCount:   3,
+			RepoSize:   1024,
+		},
+	)
+
+	err = ns.NotifyNode("a", &pinbase.NodeHealth{Reachable: false})
+	if err != nil {
+		t.Errorf("failed to notify node a: %+v", err)
+	}
+
+	unreachable := checkNode(
+		t,
+		"unreachable",
+		ns,
+		"a",
+		&pinbase.NodeView{
+			ID:         "a",
+			APIAddress: "127.0.0.1:5001",
+			Reachable:  false,
+			LastSeen:   time.Now(),
+			PinCount:   3,
+			RepoSize:   1024,
+		},
+	)
+	if seen != nil && unreachable != nil && !unreachable.LastSeen.Equal(seen.LastSeen) {
+		t.Errorf("unreachable node moved its last seen from %v to %v", seen.LastSeen, unreachable.LastSeen)
+	}
+
+	err = ns.UpdateNode("a", &pinbase.NodeEdit{APIAddress: "127.0.0.1:5001"})
+	if err != nil {
+		t.Errorf("failed to update node a: %+v", err)
+	}
+
+	checkNode(
+		t,
+		"same address",
+		ns,
+		"a",
+		&pinbase.NodeView{
+			ID:         "a",
+			APIAddress: "127.0.0.1:5001",
+			Reachable:  false,
+			LastSeen:   time.Now(),
+			PinCount:   3,
+			RepoSize:   1024,
+		},
+	)
+
+	err = ns.UpdateNode("a", &pinbase.NodeEdit{APIAddress: "127.0.0.1:5011"})
+	if err != nil {
+		t.Errorf("failed to update node a: %+v", err)
+	}
+
+	checkNode(
+		t,
+		"new address",
+		ns,
+		"a",
+		&pinbase.NodeView{
+			ID:         "a",
+			APIAddress: "127.0.0.1:5011",
+		},
+	)
+
+	err = ns.UpdateNode("c", &pinbase.NodeEdit{APIAddress: "127.0.0.1:5003"})
+	if err == nil {
+		t.Error("updated missing node c")
+	}
+
+	err = ns.DeleteNode("b")
+	if err != nil {
+		t.Errorf("failed to delete node b: %+v", err)
+	}
+
+	checkNode(t, "deleted", ns, "b", nil)
+
+	err = ns.NotifyNode("b", &pinbase.NodeHealth{Reachable: true})
+	if err != nil {
+		t.Errorf("failed to notify deleted node b: %+v", err)
+	}
+
+	checkNode(t, "deleted notified", ns, "b", nil)
+}
+
+// MapSizer is a pinbase.PinSizer for the hashes in the map, the mode is ignored.
+type MapSizer map[pinbase.Hash]int64
+
+func (ms MapSizer) PinSize(_ context.Context, h pinbase.Hash, _ pinbase.PinMode) (int64, error) {
+	size, ok := ms[h]
+	if !ok {
+		return 0, errors.Errorf("unknown hash %s", h)
+	}
+
+	return size, nil
+}
+
+var _ pinbase.PinSizer = MapSizer{}
+
+func checkParty(t *testing.T, tag string, ps pinbase.PinService, partyID pinbase.Hash, quota pinbase.PartyQuota, usage pinbase.PartyUsage) {
+	p, err := ps.Party(partyID)
+	if err != nil {
+		t.Errorf("%s: failed to get party %s: %+v", tag, partyID, err)
+		return
+	}
+
+	if p.Quota != quota || p.Usage != usage {
+		t.Errorf("%s: got party quota %+v usage %+v, expected %+v and %+v", tag, p.Quota, p.Usage, quota, usage)
+	}
+}
+
+func checkQuotaError(t *testing.T, tag string, err error, expectExceeded bool) {
+	exceeded := errors.Cause(err) == pinbase.ErrQuotaExceeded
+
+	if exceeded != expectExceeded {
+		t.Errorf("%s: got error %v, expected the quota to be exceeded: %t", tag, err, expectExceeded)
+	}
+
+	if err != nil && !exceeded {
+		t.Errorf("%s: unexpected error: %+v", tag, err)
+	}
+}
+
+// QuotaSizer sizes the pins of TestPinQuotaHappyPath.
+var QuotaSizer = MapSizer{hashA: 40, hashB: 50, hashC: 30, hashD: 10}
+
+// TestPinQuotaHappyPath expects the service to size pins with QuotaSizer.
+func TestPinQuotaHappyPath(t *testing.T, ps pinbase.PinService) {
+	err := ps.CreateParty(&pinbase.PartyCreate{
+		ID:          pinbase.Hash("foo"),
+		Description: "limited",
+		Quota:       pinbase.PartyQuota{MaxPins: 2, MaxBytes: 100},
+	})
+	if err != nil {
+		t.Errorf("failed to create party foo: %+v", err)
+	}
+
+	err = ps.CreateParty(&pinbase.PartyCreate{
+		ID:          pinbase.Hash("bar"),
+		Description: "unlimited",
+	})
+	if err != nil {
+		t.Errorf("failed to create party bar: %+v", err)
+	}
+
+	checkParty(t, "start", ps, "foo", pinbase.PartyQuota{MaxPins: 2, MaxBytes: 100}, pinbase.PartyUsage{})
+
+	create := func(partyID, pinID pinbase.Hash, want bool) error {
+		return ps.CreatePin(partyID, &pinbase.PinCreate{ID: pinID, WantPinned: want})
+	}
+
+	update := func(partyID, pinID pinbase.Hash, aliases []string, want bool) error {
+		return ps.UpdatePin(partyID, pinID, &pinbase.PinEdit{Aliases: aliases, WantPinned: want})
+	}
+
+	checkQuotaError(t, "create a", create("foo", hashA, true), false)
+	checkQuotaError(t, "create b", create("foo", hashB, true), false)
+	checkParty(t, "a and b", ps, "foo", pinbase.PartyQuota{MaxPins: 2, MaxBytes: 100}, pinbase.PartyUsage{Pins: 2, Bytes: 90})
+
+	checkQuotaError(t, "create c", create("foo", hashC, true), true)
+	checkQuotaError(t, "create unwanted c", create("foo", hashC, false), false)
+	checkQuotaError(t, "want c", update("foo", hashC, nil, true), true)
+	checkParty(t, "unwanted c", ps, "foo", pinbase.PartyQuota{MaxPins: 2, MaxBytes: 100}, pinbase.PartyUsage{Pins: 2, Bytes: 90})
+
+	err = ps.UpdateParty("foo", &pinbase.PartyEdit{
+		Description: "limited",
+		Quota:       pinbase.PartyQuota{MaxPins: 1, MaxBytes: 100},
+	})
+	if err != nil {
+		t.Errorf("failed to update party foo: %+v", err)
+	}
+
+	checkQuotaError(t, "over quota alias a", update("foo", hashA, []string{"aaa"}, true), false)
+
+	err = ps.UpdateParty("foo", &pinbase.PartyEdit{
+		Description: "limited",
+		Quota:       pinbase.PartyQuota{MaxBytes: 100},
+	})
+	if err != nil {
+		t.Errorf("failed to update party foo: %+v", err)
+	}
+
+	checkQuotaError(t, "create d", create("foo", hashD, true), false)
+	checkQuotaError(t, "want c over bytes", update("foo", hashC, nil, true), true)
+	checkQuotaError(t, "unwant a", update("foo", hashA, nil, false), false)
+	checkQuotaError(t, "want c", update("foo", hashC, nil, true), false)
+	checkParty(t, "b, c and d", ps, "foo", pinbase.PartyQuota{MaxBytes: 100}, pinbase.PartyUsage{Pins: 3, Bytes: 90})
+
+	// parties without a byte quota do not get their pins sized
+	checkQuotaError(t, "create unsized", create("bar", hashUnsized, true), false)
+	checkParty(t, "unsized", ps, "bar", pinbase.PartyQuota{}, pinbase.PartyUsage{Pins: 1})
+}
+
+func TestPinSizeHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.PinService) {
+	for _, partyID := range []pinbase.Hash{"foo", "baz"} {
+		err := ps.CreateParty(&pinbase.PartyCreate{
+			ID:          partyID,
+			Description: "hello",
+		})
+		if err != nil {
+			t.Errorf("failed to create party %s: %+v", partyID, err)
+		}
+	}
+
+	for _, pc := range []struct {
+		partyID pinbase.Hash
+		pinID   pinbase.Hash
+		want    bool
+	}{
+		{"foo", hashBar, true},
+		{"foo", hashQux, true},
+		{"baz", hashBar, false},
+	} {
+		err := ps.CreatePin(pc.partyID, &pinbase.PinCreate{ID: pc.pinID, WantPinned: pc.want})
+		if err != nil {
+			t.Errorf("failed to create pin %s for %s: %+v", pc.pinID, pc.partyID, err)
+		}
+
+		checkBump(t, "pin created", true, pb.PinProcessorBump())
+	}
+
+	checkRequirements(t, "created", pb.PinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{
+		hashBar: {WantPinned: true},
+		hashQux: {WantPinned: true},
+	})
+
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinPinned, Size: 4096})
+	pb.NotifyPin(hashQux, &pinbase.PinBackendState{Status: pinbase.PinPinned})
+
+	checkRequirements(t, "pinned", pb.PinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{
+		hashBar: {WantPinned: true, Sized: true},
+		hashQux: {WantPinned: true},
+	})
+
+	for _, partyID := range []pinbase.Hash{"foo", "baz"} {
+		pin, err := ps.Pin(partyID, hashBar)
+		if err != nil {
+			t.Errorf("failed to get pin bar for %s: %+v", partyID, err)
+		} else if pin.Size != 4096 {
+			t.Errorf("pin bar for %s should have size 4096: %d", partyID, pin.Size)
+		}
+	}
+
+	// a later notification without a size does not forget it
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinPinned})
+
+	checkParty(t, "foo pinned", ps, "foo", pinbase.PartyQuota{}, pinbase.PartyUsage{
+		Pins:        2,
+		Bytes:       4096,
+		PinnedPins:  2,
+		PinnedBytes: 4096,
+	})
+
+	checkParty(t, "baz pinned", ps, "baz", pinbase.PartyQuota{}, pinbase.PartyUsage{
+		PinnedPins:  1,
+		PinnedBytes: 4096,
+	})
+
+	pb.NotifyPin(hashQux, &pinbase.PinBackendState{Status: pinbase.PinPinned, Size: 1024})
+
+	checkParty(t, "qux sized", ps, "foo", pinbase.PartyQuota{}, pinbase.PartyUsage{
+		Pins:        2,
+		Bytes:       5120,
+		PinnedPins:  2,
+		PinnedBytes: 5120,
+	})
+}
+
+func TestKeyServiceHappyPath(t *testing.T, ks pinbase.KeyService) {
+	keys, err := ks.Keys()
+	if err != nil {
+		t.Errorf("failed to get keys: %+v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("started out with keys: %v", keys)
+	}
+
+	foo, fooKey, err := ks.CreateKey(&pinbase.KeyCreate{Description: "foo"})
+	if err != nil {
+		t.Fatalf("failed to create key foo: %+v", err)
+	}
+
+	bar, barKey, err := ks.CreateKey(&pinbase.KeyCreate{Description: "bar"})
+	if err != nil {
+		t.Fatalf("failed to create key bar: %+v", err)
+	}
+
+	if foo.ID == bar.ID || fooKey == barKey {
+		t.Errorf("got the same key twice: %s %s", fooKey, barKey)
+	}
+
+	if foo.Description != "foo" || foo.Created.IsZero() {
+		t.Errorf("got unexpected key view: %+v", foo)
+	}
+
+	kv, err := ks.Key(foo.ID)
+	if err != nil {
+		t.Errorf("failed to get key foo: %+v", err)
+	}
+	if !reflect.DeepEqual(kv, foo) {
+		t.Errorf("got key %+v, expected %+v", kv, foo)
+	}
+
+	keys, err = ks.Keys()
+	if err != nil {
+		t.Errorf("failed to get keys: %+v", err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("expected two keys: %v", keys)
+	}
+
+	for _, tc := range []struct {
+		key      string
+		expected *pinbase.KeyView
+	}{
+		{fooKey, foo},
+		{barKey, bar},
+		{"", nil},
+		{foo.ID, nil},
+		{foo.ID + ".", nil},
+		{foo.ID + barKey[len(bar.ID):], nil},
+		{"nope." + fooKey, nil},
+	} {
+		kv, err := ks.Authenticate(tc.key)
+		if err != nil {
+			t.Errorf("failed to authenticate %q: %+v", tc.key, err)
+		}
+		if !reflect.DeepEqual(kv, tc.expected) {
+			t.Errorf("authenticating %q got %v, expected %v", tc.key, kv, tc.expected)
+		}
+	}
+
+	err = ks.DeleteKey(foo.ID)
+	if err != nil {
+		t.Errorf("failed to delete key foo: %+v", err)
+	}
+
+	kv, err = ks.Authenticate(fooKey)
+	if err != nil || kv != nil {
+		t.Errorf("deleted key still authenticates: %v %+v", kv, err)
+	}
+
+	kv, err = ks.Key(foo.ID)
+	if err != nil || kv != nil {
+		t.Errorf("deleted key still shows up: %v %+v", kv, err)
+	}
+}
+
+func checkGrants(t *testing.T, tag string, ps pinbase.PinService, partyID pinbase.Hash, expected map[string]pinbase.Role) {
+	p, err := ps.Party(partyID)
+	if err != nil {
+		t.Errorf("%s: failed to get party %s: %+v", tag, partyID, err)
+		return
+	}
+
+	if len(p.Grants) != len(expected) {
+		t.Errorf("%s: got grants %v, expected %v", tag, p.Grants, expected)
+		return
+	}
+	for k, r := range expected {
+		if p.Grants[k] != r {
+			t.Errorf("%s: got grants %v, expected %v", tag, p.Grants, expected)
+			return
+		}
+	}
+}
+
+func TestPinGrantHappyPath(t *testing.T, ps pinbase.PinService) {
+	err := ps.CreateParty(&pinbase.PartyCreate{
+		ID:          "foo",
+		Description: "foo party",
+	})
+	if err != nil {
+		t.Fatalf("failed to create party: %+v", err)
+	}
+
+	checkGrants(t, "new party", ps, "foo", map[string]pinbase.Role{})
+
+	err = ps.GrantParty("foo", "alice", pinbase.RolePartyOwner)
+	if err != nil {
+		t.Fatalf("failed to grant alice: %+v", err)
+	}
+	err = ps.GrantParty("foo", "bob", pinbase.RoleReadOnly)
+	if err != nil {
+		t.Fatalf("failed to grant bob: %+v", err)
+	}
+
+	checkGrants(t, "granted", ps, "foo", map[string]pinbase.Role{
+		"alice": pinbase.RolePartyOwner,
+		"bob":   pinbase.RoleReadOnly,
+	})
+
+	err = ps.GrantParty("foo", "bob", pinbase.RolePartyOwner)
+	if err != nil {
+		t.Fatalf("failed to promote bob: %+v", err)
+	}
+	err = ps.GrantParty("foo", "alice", pinbase.RoleNone)
+	if err != nil {
+		t.Fatalf("failed to revoke alice: %+v", err)
+	}
+
+	checkGrants(t, "changed", ps, "foo", map[string]pinbase.Role{
+		"bob": pinbase.RolePartyOwner,
+	})
+
+	err = ps.UpdateParty("foo", &pinbase.PartyEdit{Description: "still foo"})
+	if err != nil {
+		t.Fatalf("failed to update party: %+v", err)
+	}
+
+	checkGrants(t, "updated", ps, "foo", map[string]pinbase.Role{
+		"bob": pinbase.RolePartyOwner,
+	})
+
+	err = ps.GrantParty("foo", "carol", pinbase.RoleAdmin)
+	if err == nil {
+		t.Error("expected an error granting the admin role")
+	}
+
+	err = ps.GrantParty("bar", "carol", pinbase.RoleReadOnly)
+	if err == nil {
+		t.Error("expected an error granting a missing party")
+	}
+}
+
+func checkEvents(t *testing.T, tag string, s *pinbase.Subscription, expected []pinbase.PinEvent) {
+	var got []pinbase.PinEvent
+	for len(got) < len(expected) {
+		select {
+		case e := <-s.C:
+			got = append(got, pinbase.PinEvent{Party: e.Party, Pin: e.Pin, Status: e.Status, LastError: e.LastError})
+		default:
+			t.Errorf("%s: got events %+v, expected %+v", tag, got, expected)
+			return
+		}
+	}
+
+	select {
+	case e := <-s.C:
+		t.Errorf("%s: got an extra event %+v", tag, e)
+	default:
+	}
+
+	// the order of the parties sharing a pin does not matter
+	sort.Slice(got, func(i, j int) bool {
+		return got[i].Pin < got[j].Pin || got[i].Pin == got[j].Pin && got[i].Party < got[j].Party
+	})
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("%s: got events %+v, expected %+v", tag, got, expected)
+	}
+}
+
+// TestPinEventsHappyPath expects the backend to publish to hub.
+func TestPinEventsHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.PinService, hub *pinbase.EventHub) {
+	for _, partyID := range []pinbase.Hash{"foo", "baz"} {
+		err := ps.CreateParty(&pinbase.PartyCreate{
+			ID:          partyID,
+			Description: "hello",
+		})
+		if err != nil {
+			t.Fatalf("failed to create party %s: %+v", partyID, err)
+		}
+	}
+
+	for _, pc := range []struct {
+		partyID pinbase.Hash
+		pinID   pinbase.Hash
+	}{
+		{"foo", hashBar},
+		{"foo", hashQux},
+		{"baz", hashBar},
+	} {
+		err := ps.CreatePin(pc.partyID, &pinbase.PinCreate{ID: pc.pinID, WantPinned: true})
+		if err != nil {
+			t.Fatalf("failed to create pin %s for %s: %+v", pc.pinID, pc.partyID, err)
+		}
+
+		checkBump(t, "pin created", true, pb.PinProcessorBump())
+	}
+
+	all := hub.Subscribe("")
+	defer all.Close()
+	baz := hub.Subscribe("baz")
+	defer baz.Close()
+
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinPinning})
+	pb.NotifyPin(hashQux, &pinbase.PinBackendState{Status: pinbase.PinError, LastError: errors.New("oops")})
+
+	checkEvents(t, "all started", all, []pinbase.PinEvent{
+		{Party: "baz", Pin: hashBar, Status: pinbase.PinPinning},
+		{Party: "foo", Pin: hashBar, Status: pinbase.PinPinning},
+		{Party: "foo", Pin: hashQux, Status: pinbase.PinError, LastError: "oops"},
+	})
+	checkEvents(t, "baz started", baz, []pinbase.PinEvent{
+		{Party: "baz", Pin: hashBar, Status: pinbase.PinPinning},
+	})
+
+	// progress without a change of status is not an event
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinPinning, Progress: &pinbase.PinProgress{Blocks: 3}})
+
+	checkEvents(t, "progress", all, nil)
+
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinPinned})
+
+	checkEvents(t, "all pinned", all, []pinbase.PinEvent{
+		{Party: "baz", Pin: hashBar, Status: pinbase.PinPinned},
+		{Party: "foo", Pin: hashBar, Status: pinbase.PinPinned},
+	})
+	checkEvents(t, "baz pinned", baz, []pinbase.PinEvent{
+		{Party: "baz", Pin: hashBar, Status: pinbase.PinPinned},
+	})
+}
+
+// webhookReceiver records the payloads posted to it that carry a good
+// signature, failing the first failures requests.
+type webhookReceiver struct {
+	t        *testing.T
+	secret   string
+	failures int
+
+	mu       sync.Mutex
+	payloads []webhook.Payload
+}
+
+func (wr *webhookReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		wr.t.Errorf("failed to read webhook body: %+v", err)
+		return
+	}
+
+	if !webhook.Verify(wr.secret, body, req.Header.Get(webhook.SignatureHeader)) {
+		wr.t.Errorf("got a badly signed webhook: %s", body)
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	if wr.failures > 0 {
+		wr.failures--
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var p webhook.Payload
+	err = json.Unmarshal(body, &p)
+	if err != nil {
+		wr.t.Errorf("failed to decode webhook payload %s: %+v", body, err)
+		return
+	}
+	p.Time = time.Time{}
+
+	wr.payloads = append(wr.payloads, p)
+}
+
+func (wr *webhookReceiver) take() []webhook.Payload {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	p := wr.payloads
+	wr.payloads = nil
+	return p
+}
+
+// deliverDue sends the due deliveries and returns how many there were.
+func deliverDue(t *testing.T, tag string, q pinbase.WebhookQueue, s pinbase.WebhookSender) int {
+	ds, err := q.DueWebhookDeliveries(100)
+	if err != nil {
+		t.Fatalf("%s: failed to get due deliveries: %+v", tag, err)
+	}
+
+	for _, d := range ds {
+		err = q.WebhookDelivered(d.ID, s.SendWebhook(context.Background(), d))
+		if err != nil {
+			t.Fatalf("%s: failed to record delivery: %+v", tag, err)
+		}
+	}
+
+	return len(ds)
+}
+
+func checkDue(t *testing.T, tag string, q pinbase.WebhookQueue, s pinbase.WebhookSender, expected int) {
+	if n := deliverDue(t, tag, q, s); n != expected {
+		t.Errorf("%s: got %d due deliveries, expected %d", tag, n, expected)
+	}
+}
+
+func checkPayloads(t *testing.T, tag string, wr *webhookReceiver, expected []webhook.Payload) {
+	if got := wr.take(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("%s: got payloads %+v, expected %+v", tag, got, expected)
+	}
+}
+
+// TestWebhookHappyPath expects rp to be the retry policy of the webhook queue
+// and to give up after a few short delays.
+func TestWebhookHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.PinService, ws pinbase.WebhookService, q pinbase.WebhookQueue, rp pinbase.RetryPolicy) {
+	for _, partyID := range []pinbase.Hash{"foo", "baz"} {
+		err := ps.CreateParty(&pinbase.PartyCreate{
+			ID:          partyID,
+			Description: "hello",
+		})
+		if err != nil {
+			t.Fatalf("failed to create party %s: %+v", partyID, err)
+		}
+	}
+
+	_, _, err := ws.CreateWebhook("nope", &pinbase.WebhookCreate{URL: "http://example.com/"})
+	if err == nil {
+		t.Error("created a webhook for a party that does not exist")
+	}
+
+	wr := &webhookReceiver{t: t}
+	srv := httptest.NewServer(wr)
+	defer srv.Close()
+
+	s := &webhook.Sender{Client: srv.Client()}
+
+	wv, secret, err := ws.CreateWebhook("foo", &pinbase.WebhookCreate{URL: srv.URL})
+	if err != nil {
+		t.Fatalf("failed to create webhook: %+v", err)
+	}
+	if wv.ID == "" || wv.Party != "foo" || wv.URL != srv.URL || wv.Created.IsZero() {
+		t.Errorf("got a strange webhook: %+v", wv)
+	}
+	if len(secret) < 32 {
+		t.Errorf("got a short secret %q", secret)
+	}
+	wr.secret = secret
+
+	list, err := ws.Webhooks("foo")
+	if err != nil {
+		t.Fatalf("failed to list webhooks: %+v", err)
+	}
+	if len(list) != 1 || !reflect.DeepEqual(list[0], wv) {
+		t.Errorf("got webhooks %+v, expected just %+v", list, wv)
+	}
+
+	list, err = ws.Webhooks("baz")
+	if err != nil || len(list) != 0 {
+		t.Errorf("got webhooks %+v (%+v) for a party without any", list, err)
+	}
+
+	got, err := ws.Webhook("foo", wv.ID)
+	if err != nil || !reflect.DeepEqual(got, wv) {
+		t.Errorf("got webhook %+v (%+v), expected %+v", got, err, wv)
+	}
+
+	got, err = ws.Webhook("baz", wv.ID)
+	if err != nil || got != nil {
+		t.Errorf("got webhook %+v (%+v) from the wrong party", got, err)
+	}
+
+	for _, pc := range []struct {
+		partyID pinbase.Hash
+		pinID   pinbase.Hash
+	}{
+		{"foo", hashBar},
+		{"foo", hashQux},
+		{"baz", hashBar},
+	} {
+		err := ps.CreatePin(pc.partyID, &pinbase.PinCreate{ID: pc.pinID, WantPinned: true})
+		if err != nil {
+			t.Fatalf("failed to create pin %s for %s: %+v", pc.pinID, pc.partyID, err)
+		}
+	}
+
+	// pinning is not worth a post
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinPinning})
+
+	checkDue(t, "pinning", q, s, 0)
+
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinPinned})
+
+	checkDue(t, "pinned", q, s, 1)
+	checkPayloads(t, "pinned", wr, []webhook.Payload{
+		{Party: "foo", Hash: string(hashBar), Status: "pinned"},
+	})
+
+	// failed deliveries come back after a delay
+	wr.failures = 1
+	pb.NotifyPin(hashQux, &pinbase.PinBackendState{Status: pinbase.PinError, LastError: errors.New("oops")})
+
+	checkDue(t, "failing", q, s, 1)
+	checkPayloads(t, "failing", wr, nil)
+	checkDue(t, "failed", q, s, 0)
+
+	time.Sleep(rp.Delay(1) + 10*time.Millisecond)
+
+	checkDue(t, "retried", q, s, 1)
+	checkPayloads(t, "retried", wr, []webhook.Payload{
+		{Party: "foo", Hash: string(hashQux), Status: "error", LastError: "oops"},
+	})
+	checkDue(t, "delivered", q, s, 0)
+
+	// and are given up on eventually
+	wr.failures = rp.MaxAttempts
+	pb.NotifyPin(hashQux, &pinbase.PinBackendState{Status: pinbase.PinPinned})
+
+	for i := 1; i <= rp.MaxAttempts; i++ {
+		checkDue(t, "failing for good", q, s, 1)
+		time.Sleep(rp.Delay(i) + 10*time.Millisecond)
+	}
+
+	checkDue(t, "given up", q, s, 0)
+	checkPayloads(t, "given up", wr, nil)
+
+	// deleting the webhook drops its pending deliveries
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinError, LastError: errors.New("oops")})
+
+	err = ws.DeleteWebhook("foo", wv.ID)
+	if err != nil {
+		t.Fatalf("failed to delete webhook: %+v", err)
+	}
+
+	checkDue(t, "deleted", q, s, 0)
+	checkPayloads(t, "deleted", wr, nil)
+
+	list, err = ws.Webhooks("foo")
+	if err != nil || len(list) != 0 {
+		t.Errorf("got webhooks %+v (%+v) after deleting the only one", list, err)
+	}
+}
+
+func TestPinHistoryHappyPath(t *testing.T, pb pinbase.PinBackend, ps pinbase.PinService) {
+	err := ps.CreateParty(&pinbase.PartyCreate{
+		ID:          "foo",
+		Description: "hello",
+	})
+	if err != nil {
+		t.Fatalf("failed to create party: %+v", err)
+	}
+
+	_, err = ps.PinHistory("nope", hashBar)
+	if err == nil {
+		t.Error("got a history for a party that does not exist")
+	}
+
+	hs, err := ps.PinHistory("foo", hashBar)
+	if err != nil || len(hs) != 0 {
+		t.Errorf("got history %v (%+v) for a pin that never existed", hs, err)
+	}
+
+	err = ps.CreatePin("foo", &pinbase.PinCreate{
+		ID:          hashBar,
+		Aliases:     []string{"a"},
+		WantPinned:  true,
+		Mode:        pinbase.PinRecursive,
+		Replication: 1,
+		By:          "k1",
+	})
+	if err != nil {
+		t.Fatalf("failed to create pin: %+v", err)
+	}
+
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinPinning})
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinError, LastError: errors.New("oops")})
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinPinning})
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinPinned})
+	// no change, no entry
+	pb.NotifyPin(hashBar, &pinbase.PinBackendState{Status: pinbase.PinPinned})
+
+	err = ps.UpdatePin("foo", hashBar, &pinbase.PinEdit{
+		Aliases:     []string{"a"},
+		WantPinned:  false,
+		Mode:        pinbase.PinRecursive,
+		Replication: 1,
+		By:          "k2",
+	})
+	if err != nil {
+		t.Fatalf("failed to update pin: %+v", err)
+	}
+
+	err = ps.ResetPin("foo", hashBar, "k3")
+	if err != nil {
+		t.Fatalf("failed to reset pin: %+v", err)
+	}
+
+	err = ps.DeletePin("foo", hashBar, "k1")
+	if err != nil {
+		t.Fatalf("failed to delete pin: %+v", err)
+	}
+
+	// the history outlives the pin and picks up where it left off
+	err = ps.CreatePin("foo", &pinbase.PinCreate{
+		ID:         hashBar,
+		WantPinned: true,
+		Mode:       pinbase.PinDirect,
+		By:         "k2",
+	})
+	if err != nil {
+		t.Fatalf("failed to create pin again: %+v", err)
+	}
+
+	hs, err = ps.PinHistory("foo", hashBar)
+	if err != nil {
+		t.Fatalf("failed to get history: %+v", err)
+	}
+
+	var last time.Time
+	for i, h := range hs {
+		if h.Time.IsZero() || h.Time.Before(last) {
+			t.Errorf("entry %d has time %s after %s", i, h.Time, last)
+		}
+		last = h.Time
+		h.Time = time.Time{}
+	}
+
+	expected := []*pinbase.PinHistoryEntry{
+		{Change: pinbase.PinChangeCreated, By: "k1", Status: pinbase.PinPending, Changes: []string{`aliases: ["a"]`, "want-pinned: true", "mode: recursive", "replication: 1"}},
+		{Change: pinbase.PinChangeStatus, Status: pinbase.PinPinning},
+		{Change: pinbase.PinChangeStatus, Status: pinbase.PinError, LastError: "oops"},
+		{Change: pinbase.PinChangeStatus, Status: pinbase.PinPinning},
+		{Change: pinbase.PinChangeStatus, Status: pinbase.PinPinned},
+		{Change: pinbase.PinChangeUpdated, By: "k2", Status: pinbase.PinPending, Changes: []string{"want-pinned: true -> false"}},
+		{Change: pinbase.PinChangeReset, By: "k3", Status: pinbase.PinPending},
+		{Change: pinbase.PinChangeDeleted, By: "k1", Status: pinbase.PinPending},
+		{Change: pinbase.PinChangeCreated, By: "k2", Status: pinbase.PinPending, Changes: []string{"aliases: []", "want-pinned: true", "mode: direct", "replication: 0"}},
+	}
+	if !reflect.DeepEqual(hs, expected) {
+		t.Errorf("got history\n%v\nexpected\n%v", hs, expected)
+	}
+
+	// the history goes away along with the party
+	err = ps.DeleteParty("foo")
+	if err != nil {
+		t.Fatalf("failed to delete party: %+v", err)
+	}
+
+	_, err = ps.PinHistory("foo", hashBar)
+	if err == nil {
+		t.Error("got a history for a deleted party")
+	}
+}
+
+func checkPinWanted(t *testing.T, tag string, ps pinbase.PinService, partyID, pinID pinbase.Hash, wanted bool, expiresAt time.Time) {
+	p, err := ps.Pin(partyID, pinID)
+	if err != nil || p == nil {
+		t.Fatalf("%s: failed to get pin %s: %+v", tag, pinID, err)
+	}
+
+	if p.WantPinned != wanted || !p.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("%s: pin %s is wanted(%t) until %s, expected wanted(%t) until %s", tag, pinID, p.WantPinned, p.ExpiresAt, wanted, expiresAt)
+	}
+}
+
+func TestPinExpiryHappyPath(t *testing.T, pe pinbase.PinExpirer, ps pinbase.PinService) {
+	err := ps.CreateParty(&pinbase.PartyCreate{
+		ID:          "foo",
+		Description: "hello",
+		Quota:       pinbase.PartyQuota{MaxPins: 1},
+	})
+	if err != nil {
+		t.Fatalf("failed to create party: %+v", err)
+	}
+
+	start := time.Now().UTC().Round(0)
+	soon := start.Add(time.Hour)
+
+	err = ps.CreatePin("foo", &pinbase.PinCreate{ID: hashBar, WantPinned: true, ExpiresAt: soon})
+	if err != nil {
+		t.Fatalf("failed to create expiring pin: %+v", err)
+	}
+	checkBump(t, "expiring pin created", true, pe.PinProcessorBump())
+
+	err = ps.CreatePin("foo", &pinbase.PinCreate{ID: hashBaz})
+	if err != nil {
+		t.Fatalf("failed to create unwanted pin: %+v", err)
+	}
+	checkBump(t, "unwanted pin created", true, pe.PinProcessorBump())
+
+	// leave only what the expiry marks dirty
+	pe.DirtyPinRequirements()
+
+	checkPinWanted(t, "created", ps, "foo", hashBar, true, soon)
+
+	pe.ExpirePins(start)
+
+	checkBump(t, "nothing expired", false, pe.PinProcessorBump())
+	checkPinWanted(t, "nothing expired", ps, "foo", hashBar, true, soon)
+
+	pe.ExpirePins(soon)
+
+	checkBump(t, "expired", true, pe.PinProcessorBump())
+	checkPinWanted(t, "expired", ps, "foo", hashBar, false, soon)
+	checkPinStatus(t, "expired", ps, "foo", hashBar, pinbase.PinPending)
+
+	reqs := pe.DirtyPinRequirements()
+	checkRequirements(t, "expired", reqs, map[pinbase.Hash]pinbase.PinRequirement{
+		hashBar: pinbase.PinRequirement{},
+	})
+
+	// expired pins stay expired
+	pe.ExpirePins(soon.Add(time.Hour))
+
+	checkBump(t, "still expired", false, pe.PinProcessorBump())
+
+	hs, err := ps.PinHistory("foo", hashBar)
+	if err != nil || len(hs) == 0 {
+		t.Fatalf("failed to get history: %+v", err)
+	}
+	if h := hs[len(hs)-1]; h.Change != pinbase.PinChangeExpired || !h.Time.Equal(soon) {
+		t.Errorf("expected the history to end with the expiry at %s: %v", soon, h)
+	}
+
+	// renewing wants the pin again, as far as the quota allows
+	err = ps.UpdatePin("foo", hashBaz, &pinbase.PinEdit{WantPinned: true})
+	if err != nil {
+		t.Fatalf("failed to want pin: %+v", err)
+	}
+	checkBump(t, "wanted", true, pe.PinProcessorBump())
+
+	later := soon.Add(24 * time.Hour)
+
+	err = ps.RenewPin("foo", hashBar, later, "k1")
+	if errors.Cause(err) != pinbase.ErrQuotaExceeded {
+		t.Errorf("expected renewing past the quota to fail: %+v", err)
+	}
+	checkPinWanted(t, "renewed past the quota", ps, "foo", hashBar, false, soon)
+
+	err = ps.DeletePin("foo", hashBaz, "k1")
+	if err != nil {
+		t.Fatalf("failed to delete pin: %+v", err)
+	}
+	checkBump(t, "deleted", true, pe.PinProcessorBump())
+
+	err = ps.RenewPin("foo", hashBar, later, "k1")
+	if err != nil {
+		t.Fatalf("failed to renew pin: %+v", err)
+	}
+
+	checkBump(t, "renewed", true, pe.PinProcessorBump())
+	checkPinWanted(t, "renewed", ps, "foo", hashBar, true, later)
+
+	// renewing a wanted pin only moves its expiry
+	err = ps.RenewPin("foo", hashBar, time.Time{}, "k1")
+	if err != nil {
+		t.Fatalf("failed to renew pin for good: %+v", err)
+	}
+
+	checkBump(t, "renewed for good", false, pe.PinProcessorBump())
+	checkPinWanted(t, "renewed for good", ps, "foo", hashBar, true, time.Time{})
+
+	pe.ExpirePins(later.Add(time.Hour))
+
+	checkBump(t, "never expires", false, pe.PinProcessorBump())
+	checkPinWanted(t, "never expires", ps, "foo", hashBar, true, time.Time{})
+
+	hs, err = ps.PinHistory("foo", hashBar)
+	if err != nil {
+		t.Fatalf("failed to get history: %+v", err)
+	}
+	var changes [][]string
+	for _, h := range hs[len(hs)-2:] {
+		if h.Change != pinbase.PinChangeRenewed || h.By != "k1" {
+			t.Errorf("expected a renewal by k1: %v", h)
+		}
+		changes = append(changes, h.Changes)
+	}
+	expected := [][]string{
+		{"want-pinned: false -> true", "expires-at: " + soon.Format(time.RFC3339) + " -> " + later.Format(time.RFC3339)},
+		{"expires-at: " + later.Format(time.RFC3339) + " -> never"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("got renewal changes %q, expected %q", changes, expected)
+	}
+}
+
+func TestPinWindowHappyPath(t *testing.T, sc pinbase.PinScheduler, ps pinbase.PinService) {
+	err := ps.CreateParty(&pinbase.PartyCreate{
+		ID:          "foo",
+		Description: "hello",
+	})
+	if err != nil {
+		t.Fatalf("failed to create party: %+v", err)
+	}
+
+	start := time.Now().UTC().Round(0).Truncate(time.Second)
+
+	windows := []struct {
+		id                  pinbase.Hash
+		notBefore, notAfter time.Time
+	}{
+		{hashPast, start.Add(-2 * time.Hour), start.Add(-time.Hour)},
+		{hashCurrent, start.Add(-time.Hour), start.Add(time.Hour)},
+		{hashFuture, start.Add(2 * time.Hour), start.Add(3 * time.Hour)},
+		{hashOpening, start.Add(4 * time.Hour), time.Time{}},
+		{hashOpen, time.Time{}, time.Time{}},
+	}
+	for _, w := range windows {
+		err = ps.CreatePin("foo", &pinbase.PinCreate{
+			ID:          w.id,
+			WantPinned:  true,
+			Replication: 1,
+			NotBefore:   w.notBefore,
+			NotAfter:    w.notAfter,
+		})
+		if err != nil {
+			t.Fatalf("failed to create pin %s: %+v", w.id, err)
+		}
+		checkBump(t, "window pin created", true, sc.PinProcessorBump())
+	}
+
+	// unwanted pins do not change with their windows
+	err = ps.CreatePin("foo", &pinbase.PinCreate{ID: hashUnwanted, NotBefore: start.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("failed to create unwanted pin: %+v", err)
+	}
+	checkBump(t, "unwanted pin created", true, sc.PinProcessorBump())
+
+	checkRequirements(t, "windows", sc.PinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{
+		hashPast:     pinbase.PinRequirement{},
+		hashCurrent:  pinbase.PinRequirement{WantPinned: true, Replication: 1},
+		hashFuture:   pinbase.PinRequirement{},
+		hashOpening:  pinbase.PinRequirement{},
+		hashOpen:     pinbase.PinRequirement{WantPinned: true, Replication: 1},
+		hashUnwanted: pinbase.PinRequirement{},
+	})
+
+	p, err := ps.Pin("foo", hashFuture)
+	if err != nil || p == nil {
+		t.Fatalf("failed to get pin: %+v", err)
+	}
+	if !p.NotBefore.Equal(start.Add(2*time.Hour)) || !p.NotAfter.Equal(start.Add(3*time.Hour)) || p.InWindow(start) {
+		t.Errorf("got the wrong window for the future pin: %s to %s", p.NotBefore, p.NotAfter)
+	}
+
+	// the pins written so far are noted without going over every pin
+	if next := sc.DirtyPinChange(start); !next.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected the written pins to change at %s, got %s", start.Add(time.Hour), next)
+	}
+	if next := sc.DirtyPinChange(start); !next.IsZero() {
+		t.Errorf("expected no more written pins to change, got %s", next)
+	}
+
+	for _, c := range []struct {
+		now, next time.Time
+	}{
+		{start, start.Add(time.Hour)},
+		{start.Add(time.Hour), start.Add(2 * time.Hour)},
+		{start.Add(2 * time.Hour), start.Add(3 * time.Hour)},
+		{start.Add(3 * time.Hour), start.Add(4 * time.Hour)},
+		{start.Add(4 * time.Hour), time.Time{}},
+	} {
+		if next := sc.NextPinChange(c.now); !next.Equal(c.next) {
+			t.Errorf("expected the pin change after %s at %s, got %s", c.now, c.next, next)
+		}
+	}
+
+	// opening the window up wants the pin right away
+	err = ps.UpdatePin("foo", hashFuture, &pinbase.PinEdit{WantPinned: true, Replication: 1, By: "k1"})
+	if err != nil {
+		t.Fatalf("failed to update pin: %+v", err)
+	}
+	checkBump(t, "opened", true, sc.PinProcessorBump())
+
+	sc.DirtyPinRequirements()
+	checkRequirements(t, "opened", sc.PinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{
+		hashPast:     pinbase.PinRequirement{},
+		hashCurrent:  pinbase.PinRequirement{WantPinned: true, Replication: 1},
+		hashFuture:   pinbase.PinRequirement{WantPinned: true, Replication: 1},
+		hashOpening:  pinbase.PinRequirement{},
+		hashOpen:     pinbase.PinRequirement{WantPinned: true, Replication: 1},
+		hashUnwanted: pinbase.PinRequirement{},
+	})
+
+	hs, err := ps.PinHistory("foo", hashFuture)
+	if err != nil || len(hs) != 2 {
+		t.Fatalf("failed to get history: %+v %v", err, hs)
+	}
+	expected := [][]string{
+		{
+			`aliases: []`,
+			"want-pinned: true",
+			"mode: " + pinbase.PinMode(0).String(),
+			"replication: 1",
+			"not-before: " + start.Add(2*time.Hour).Format(time.RFC3339),
+			"not-after: " + start.Add(3*time.Hour).Format(time.RFC3339),
+		},
+		{
+			"not-before: " + start.Add(2*time.Hour).Format(time.RFC3339) + " -> open",
+			"not-after: " + start.Add(3*time.Hour).Format(time.RFC3339) + " -> open",
+		},
+	}
+	if changes := [][]string{hs[0].Changes, hs[1].Changes}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("got window changes %q, expected %q", changes, expected)
+	}
+
+	// moving the expiry into the past needs a sweep right away
+	err = ps.UpdatePin("foo", hashCurrent, &pinbase.PinEdit{
+		WantPinned:  true,
+		Replication: 1,
+		ExpiresAt:   start.Add(-time.Minute),
+		NotBefore:   start.Add(-time.Hour),
+		NotAfter:    start.Add(time.Hour),
+		By:          "k1",
+	})
+	if err != nil {
+		t.Fatalf("failed to update pin: %+v", err)
+	}
+	checkBump(t, "expiry passed", true, sc.PinProcessorBump())
+
+	now := time.Now()
+	if next := sc.DirtyPinChange(now); !next.Equal(now) {
+		t.Errorf("expected the passed expiry to be due at %s, got %s", now, next)
+	}
+}
+
+// TestPinNameHappyPath stands in for the pin processor and the name resolver,
+// handing the backend resolutions. The backend should hold on to previous
+// targets for grace.
+func TestPinNameHappyPath(t *testing.T, nt pinbase.PinNameTracker, ps pinbase.PinService, grace time.Duration) {
+	for _, party := range []pinbase.Hash{"foo", "bar"} {
+		err := ps.CreateParty(&pinbase.PartyCreate{
+			ID:          party,
+			Description: "hello",
+		})
+		if err != nil {
+			t.Fatalf("failed to create party %s: %+v", party, err)
+		}
+	}
+
+	err := ps.CreatePin("foo", &pinbase.PinCreate{
+		ID:          "example.com",
+		Kind:        pinbase.PinKindName,
+		WantPinned:  true,
+		Replication: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to create name pin: %+v", err)
+	}
+	checkBump(t, "name pin created", true, nt.PinProcessorBump())
+
+	err = ps.CreatePin("bar", &pinbase.PinCreate{ID: hashH2, WantPinned: true, Replication: 1})
+	if err != nil {
+		t.Fatalf("failed to create hash pin: %+v", err)
+	}
+	checkBump(t, "hash pin created", true, nt.PinProcessorBump())
+
+	nt.DirtyPinRequirements()
+
+	start := time.Now().UTC().Round(0)
+	wanted := pinbase.PinRequirement{WantPinned: true, Replication: 1}
+
+	checkDueNames(t, "created", nt, start, []string{"example.com"})
+	checkRequirements(t, "unresolved", nt.PinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{
+		hashH2: wanted,
+	})
+
+	// failing to resolve the name changes nothing but when it is resolved
+	// next
+	nt.NotifyName("example.com", "", cerrors.New("no such name"), start)
+
+	checkBump(t, "failed to resolve", false, nt.PinProcessorBump())
+	checkDueNames(t, "failed to resolve", nt, start, nil)
+	checkPinTarget(t, "failed to resolve", ps, "example.com", "", pinbase.PinPending)
+
+	resolved := start.Add(time.Minute)
+	checkDueNames(t, "due again", nt, resolved, []string{"example.com"})
+
+	nt.NotifyName("example.com", hashH1, nil, resolved)
+
+	checkBump(t, "resolved", true, nt.PinProcessorBump())
+	checkPinTarget(t, "resolved", ps, "example.com", hashH1, pinbase.PinPending)
+	checkRequirements(t, "resolved", nt.DirtyPinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{
+		hashH1: wanted,
+	})
+
+	// the name pin follows how its target is doing
+	nt.NotifyPin(hashH1, &pinbase.PinBackendState{Status: pinbase.PinPinned})
+	checkPinTarget(t, "target pinned", ps, "example.com", hashH1, pinbase.PinPinned)
+
+	// resolving to the same target again changes nothing
+	nt.NotifyName("example.com", hashH1, nil, resolved.Add(time.Minute))
+
+	checkBump(t, "resolved again", false, nt.PinProcessorBump())
+	checkPinTarget(t, "resolved again", ps, "example.com", hashH1, pinbase.PinPinned)
+
+	moved := resolved.Add(2 * time.Minute)
+	nt.NotifyName("example.com", hashH2, nil, moved)
+
+	checkBump(t, "moved", true, nt.PinProcessorBump())
+	checkPinTarget(t, "moved", ps, "example.com", hashH2, pinbase.PinPending)
+	checkRequirements(t, "moved", nt.PinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{
+		hashH1: wanted,
+		hashH2: wanted,
+	})
+
+	// the previous target no longer speaks for the name pin
+	nt.NotifyPin(hashH1, &pinbase.PinBackendState{Status: pinbase.PinError, LastError: cerrors.New("oops")})
+	checkPinTarget(t, "previous target failed", ps, "example.com", hashH2, pinbase.PinPending)
+
+	nt.ReleaseTargets(moved.Add(grace).Add(-time.Second))
+
+	checkBump(t, "in the grace period", false, nt.PinProcessorBump())
+	checkArchive(t, "in the grace period", ps, nil)
+
+	nt.ReleaseTargets(moved.Add(grace))
+
+	checkBump(t, "released", true, nt.PinProcessorBump())
+	checkArchive(t, "released", ps, []*pinbase.ArchivedPinView{
+		&pinbase.ArchivedPinView{ID: hashH1, Status: pinbase.PinPending},
+	})
+	checkRequirements(t, "released", nt.DirtyPinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{
+		hashH1: pinbase.PinRequirement{},
+	})
+
+	p, err := ps.Pin("foo", "example.com")
+	if err != nil || p == nil {
+		t.Fatalf("failed to get name pin: %+v", err)
+	}
+	expected := []*pinbase.PinResolution{
+		{Time: start, Error: "no such name"},
+		{Time: resolved, Target: hashH1},
+		{Time: moved, Target: hashH2},
+	}
+	if !reflect.DeepEqual(p.Resolutions, expected) {
+		t.Errorf("got resolutions %v, expected %v", p.Resolutions, expected)
+	}
+
+	hs, err := ps.PinHistory("foo", "example.com")
+	if err != nil {
+		t.Fatalf("failed to get history: %+v", err)
+	}
+	var changes [][]string
+	for _, h := range hs {
+		if h.Change == pinbase.PinChangeResolved {
+			changes = append(changes, h.Changes)
+		}
+	}
+	if e := [][]string{{"target: none -> " + string(hashH1)}, {"target: " + string(hashH1) + " -> " + string(hashH2)}}; !reflect.DeepEqual(changes, e) {
+		t.Errorf("got resolved changes %q, expected %q", changes, e)
+	}
+
+	// the other party's pin keeps the last target from the archive
+	err = ps.DeletePin("foo", "example.com", "k1")
+	if err != nil {
+		t.Fatalf("failed to delete name pin: %+v", err)
+	}
+
+	checkBump(t, "name pin deleted", true, nt.PinProcessorBump())
+	checkArchive(t, "name pin deleted", ps, []*pinbase.ArchivedPinView{
+		&pinbase.ArchivedPinView{ID: hashH1, Status: pinbase.PinPending},
+	})
+	checkRequirements(t, "name pin deleted", nt.DirtyPinRequirements(), map[pinbase.Hash]pinbase.PinRequirement{
+		hashH2: wanted,
+	})
+}
+
+func checkDueNames(t *testing.T, tag string, nt pinbase.PinNameTracker, now time.Time, expected []string) {
+	if names := nt.DueNames(now); !reflect.DeepEqual(names, expected) {
+		t.Errorf("%s: got due names %q, expected %q", tag, names, expected)
+	}
+}
+
+func checkPinTarget(t *testing.T, tag string, ps pinbase.PinService, pinID, target pinbase.Hash, status pinbase.PinStatus) {
+	p, err := ps.Pin("foo", pinID)
+	if err != nil || p == nil {
+		t.Errorf("%s: failed to get pin %s: %+v", tag, pinID, err)
+		return
+	}
+
+	if p.Kind != pinbase.PinKindName || p.Target != target || p.Status != status {
+		t.Errorf("%s: got %s pin %s targeting %q, expected a name pin targeting %q %s", tag, p.Kind, p.Status, p.Target, target, status)
+	}
+}
+
+// TestPinPathHappyPath expects the service to keep the path a pin was resolved
+// from along with it.
+func TestPinPathHappyPath(t *testing.T, ps pinbase.PinService) {
+	err := ps.CreateParty(&pinbase.PartyCreate{
+		ID:          "foo",
+		Description: "hello",
+	})
+	if err != nil {
+		t.Fatalf("failed to create party: %+v", err)
+	}
+
+	err = ps.CreatePin("foo", &pinbase.PinCreate{
+		ID:          hashH1,
+		Path:        "/ipfs/root/a/b",
+		WantPinned:  true,
+		Mode:        pinbase.PinRecursive,
+		Replication: 1,
+		By:          "k1",
+	})
+	if err != nil {
+		t.Fatalf("failed to create pin: %+v", err)
+	}
+
+	err = ps.CreatePin("foo", &pinbase.PinCreate{
+		ID:          hashH2,
+		WantPinned:  true,
+		Mode:        pinbase.PinRecursive,
+		Replication: 1,
+		By:          "k1",
+	})
+	if err != nil {
+		t.Fatalf("failed to create pin: %+v", err)
+	}
+
+	for pinID, path := range map[pinbase.Hash]string{hashH1: "/ipfs/root/a/b", hashH2: ""} {
+		p, err := ps.Pin("foo", pinID)
+		if err != nil || p == nil {
+			t.Fatalf("failed to get pin %s: %+v", pinID, err)
+		}
+		if p.Path != path {
+			t.Errorf("pin %s has path %q, expected %q", pinID, p.Path, path)
+		}
+	}
+
+	pins, err := ps.Pins("foo")
+	if err != nil {
+		t.Fatalf("failed to get pins: %+v", err)
+	}
+	for _, p := range pins {
+		if (p.ID == hashH1) != (p.Path != "") {
+			t.Errorf("listed pin %s has path %q", p.ID, p.Path)
+		}
+	}
+
+	hs, err := ps.PinHistory("foo", hashH1)
+	if err != nil || len(hs) != 1 {
+		t.Fatalf("got history %v (%+v)", hs, err)
+	}
+
+	expected := []string{"path: /ipfs/root/a/b", "aliases: []", "want-pinned: true", "mode: recursive", "replication: 1"}
+	if !reflect.DeepEqual(hs[0].Changes, expected) {
+		t.Errorf("got created changes %q, expected %q", hs[0].Changes, expected)
+	}
+}
@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the body,
+	// keyed with the webhook's secret.
+	SignatureHeader = "X-Pinbase-Webhook-Signature"
+
+	// DeliveryHeader carries the delivery id, which stays the same when a
+	// delivery is retried.
+	DeliveryHeader = "X-Pinbase-Delivery"
+
+	// EventHeader carries the kind of event posted, always "pin" for now.
+	EventHeader = "X-Pinbase-Event"
+)
+
+// Payload is the JSON body posted to webhooks.
+type Payload struct {
+	Party     string    `json:"party"`
+	Hash      string    `json:"hash"`
+	Status    string    `json:"status"`
+	LastError string    `json:"last-error"`
+	Time      time.Time `json:"time"`
+}
+
+// Sign returns the signature header value for body.
+func Sign(secret string, body []byte) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write(body)
+	return "sha256=" + hex.EncodeToString(m.Sum(nil))
+}
+
+// Verify reports whether signature is the one Sign makes for body.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Sender posts webhook deliveries over HTTP. Deliveries answered with anything
+// other than a 2xx status count as failed.
+type Sender struct {
+	Client *http.Client
+}
+
+func (s *Sender) SendWebhook(ctx context.Context, d *pinbase.WebhookDelivery) error {
+	body, err := json.Marshal(&Payload{
+		Party:     string(d.Event.Party),
+		Hash:      string(d.Event.Pin),
+		Status:    d.Event.Status.String(),
+		LastError: d.Event.LastError,
+		Time:      d.Event.Time,
+	})
+	if err != nil {
+		return errors.Wrap(err, "encode webhook payload")
+	}
+
+	req, err := http.NewRequest("POST", d.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "create webhook request")
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(d.Secret, body))
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(d.ID, 10))
+	req.Header.Set(EventHeader, "pin")
+
+	c := s.Client
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "post webhook")
+	}
+	defer resp.Body.Close()
+
+	// drain a little so the connection can be reused
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("webhook answered %s", resp.Status)
+	}
+
+	return nil
+}
+
+var _ pinbase.WebhookSender = &Sender{}
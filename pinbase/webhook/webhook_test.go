@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/apiarian/ipfs-pinbase/pinbase"
+)
+
+func TestSender(t *testing.T) {
+	status := http.StatusNoContent
+	var got *http.Request
+	var body []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Errorf("failed to read body: %+v", err)
+		}
+		got = req
+		rw.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	s := &Sender{Client: srv.Client()}
+
+	d := &pinbase.WebhookDelivery{
+		ID:      42,
+		Webhook: &pinbase.WebhookView{ID: "abc", Party: "foo", URL: srv.URL + "/hook"},
+		Secret:  "sekrit",
+		Event: &pinbase.PinEvent{
+			Party:  "foo",
+			Pin:    "bar",
+			Status: pinbase.PinPinned,
+			Time:   time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	err := s.SendWebhook(context.Background(), d)
+	if err != nil {
+		t.Fatalf("failed to send webhook: %+v", err)
+	}
+
+	if got.Method != "POST" || got.URL.Path != "/hook" {
+		t.Errorf("got %s %s", got.Method, got.URL.Path)
+	}
+
+	expected := `{"party":"foo","hash":"bar","status":"pinned","last-error":"","time":"2017-06-01T12:00:00Z"}`
+	if string(body) != expected {
+		t.Errorf("got body %s, expected %s", body, expected)
+	}
+
+	for h, v := range map[string]string{
+		"Content-Type":  "application/json",
+		DeliveryHeader:  "42",
+		EventHeader:     "pin",
+		SignatureHeader: Sign("sekrit", body),
+	} {
+		if g := got.Header.Get(h); g != v {
+			t.Errorf("got %s %q, expected %q", h, g, v)
+		}
+	}
+
+	if !Verify("sekrit", body, got.Header.Get(SignatureHeader)) {
+		t.Error("could not verify the signature")
+	}
+	if Verify("other", body, got.Header.Get(SignatureHeader)) {
+		t.Error("verified the signature with the wrong secret")
+	}
+
+	status = http.StatusBadGateway
+
+	err = s.SendWebhook(context.Background(), d)
+	if err == nil {
+		t.Error("a bad gateway counted as delivered")
+	}
+}
@@ -0,0 +1,136 @@
+package pinbase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type WebhookCreate struct {
+	URL string
+}
+
+// WebhookView describes a URL a party's pin events are posted to. The secret
+// the posts are signed with is only handed out when the webhook is created.
+type WebhookView struct {
+	ID      string
+	Party   Hash
+	URL     string
+	Created time.Time
+}
+
+func (wv *WebhookView) String() string {
+	return wv.ID + ": " + wv.URL
+}
+
+// WebhookStatus reports whether pins changing to the status are posted to the
+// webhooks of their parties.
+func WebhookStatus(s PinStatus) bool {
+	switch s {
+	case PinPinned, PinError, PinFatal:
+		return true
+	default:
+		return false
+	}
+}
+
+type WebhookProvider interface {
+	WebhookService() WebhookService
+	WebhookQueue() WebhookQueue
+}
+
+// WebhookService manages the webhooks of the parties. CreateWebhook returns the
+// secret the webhook's posts are signed with along with its view. Deleting a
+// webhook drops its pending deliveries.
+type WebhookService interface {
+	Webhooks(partyID Hash) ([]*WebhookView, error)
+	Webhook(partyID Hash, id string) (*WebhookView, error)
+
+	CreateWebhook(partyID Hash, wc *WebhookCreate) (*WebhookView, string, error)
+	DeleteWebhook(partyID Hash, id string) error
+}
+
+// WebhookDelivery is a pin event on its way to one of its party's webhooks.
+type WebhookDelivery struct {
+	ID       uint64
+	Webhook  *WebhookView
+	Secret   string
+	Event    *PinEvent
+	Attempts int
+}
+
+// WebhookQueue holds the webhook deliveries still to be made.
+// DueWebhookDeliveries returns up to limit of the deliveries whose time has
+// come, oldest first. WebhookDelivered records the outcome of a delivery, a
+// nil error meaning it arrived. Failed deliveries are tried again later until
+// the queue gives up on them.
+type WebhookQueue interface {
+	DueWebhookDeliveries(limit int) ([]*WebhookDelivery, error)
+	WebhookDelivered(id uint64, err error) error
+}
+
+type WebhookSender interface {
+	SendWebhook(ctx context.Context, d *WebhookDelivery) error
+}
+
+// webhookBatch is how many deliveries DeliverWebhooks takes on at a time.
+const webhookBatch = 100
+
+// DeliverWebhooks sends the due deliveries of q through s every interval until
+// done is closed or ctx is cancelled. Closing done lets the deliveries in
+// progress finish first, while cancelling ctx cuts them short.
+func DeliverWebhooks(
+	ctx context.Context,
+	done <-chan struct{},
+	q WebhookQueue,
+	s WebhookSender,
+	interval time.Duration,
+) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		for deliverWebhooks(ctx, done, q, s) {
+			// a full batch probably left more behind
+		}
+
+		select {
+		case <-t.C:
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverWebhooks makes a batch of due deliveries and reports whether it was
+// a full one.
+func deliverWebhooks(ctx context.Context, done <-chan struct{}, q WebhookQueue, s WebhookSender) bool {
+	ds, err := q.DueWebhookDeliveries(webhookBatch)
+	if err != nil {
+		log.Printf("failed to get the due webhook deliveries: %+v", err)
+		return false
+	}
+
+	for _, d := range ds {
+		select {
+		case <-done:
+			return false
+		default:
+		}
+
+		err := s.SendWebhook(ctx, d)
+		if ctx.Err() != nil {
+			// we are shutting down, this is nobody's fault
+			return false
+		}
+
+		err = q.WebhookDelivered(d.ID, err)
+		if err != nil {
+			log.Printf("failed to record webhook delivery %d: %+v", d.ID, err)
+		}
+	}
+
+	return len(ds) == webhookBatch
+}
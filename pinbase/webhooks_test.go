@@ -0,0 +1,95 @@
+package pinbase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeQueue struct {
+	mu        sync.Mutex
+	due       []*WebhookDelivery
+	delivered map[uint64]error
+}
+
+func (q *fakeQueue) DueWebhookDeliveries(limit int) ([]*WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if limit > len(q.due) {
+		limit = len(q.due)
+	}
+	ds := q.due[:limit]
+	q.due = q.due[limit:]
+	return ds, nil
+}
+
+func (q *fakeQueue) WebhookDelivered(id uint64, err error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.delivered[id] = err
+	return nil
+}
+
+type fakeSender struct{}
+
+var errOdd = errors.New("odd")
+
+func (fakeSender) SendWebhook(_ context.Context, d *WebhookDelivery) error {
+	if d.ID%2 == 1 {
+		return errOdd
+	}
+	return nil
+}
+
+func TestDeliverWebhooks(t *testing.T) {
+	q := &fakeQueue{delivered: make(map[uint64]error)}
+	for i := 0; i < webhookBatch+2; i++ {
+		q.due = append(q.due, &WebhookDelivery{ID: uint64(i)})
+	}
+
+	ctx := context.Background()
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		// a long interval, so only the batches left over by a full one go
+		// out before done is closed
+		DeliverWebhooks(ctx, done, q, fakeSender{}, time.Hour)
+		close(finished)
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		q.mu.Lock()
+		n := len(q.delivered)
+		q.mu.Unlock()
+
+		if n == webhookBatch+2 {
+			break
+		}
+
+		select {
+		case <-deadline:
+			t.Fatalf("only %d deliveries were made", n)
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	close(done)
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("did not stop after done was closed")
+	}
+
+	for id, err := range q.delivered {
+		if (id%2 == 1) != (err == errOdd) {
+			t.Errorf("delivery %d recorded as %v", id, err)
+		}
+	}
+}